@@ -0,0 +1,193 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defines a link to a remote Couchbase cluster
+type AnalyticsCouchbaseLink struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Hostname of a node in the remote cluster, with an optional port
+	Hostname string `json:"hostname"`
+	//+kubebuilder:default:=None
+	//+kubebuilder:validation:Enum:=None;Half;Full
+	// Encryption used for the connection, defaults to None
+	Encryption *string `json:"encryption,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of a secret with "username" and "password" keys used to connect to the remote cluster
+	SecretName string `json:"secretName"`
+}
+
+// Defines a link to Amazon S3
+type AnalyticsS3Link struct {
+	//+kubebuilder:validation:MinLength:=1
+	// AWS region
+	Region string `json:"region"`
+	// Custom S3 service endpoint
+	ServiceEndpoint *string `json:"serviceEndpoint,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of a secret with "accessKeyId" and "secretAccessKey" keys
+	SecretName string `json:"secretName"`
+}
+
+// Defines the desired state of an Analytics link. Exactly one of couchbase or s3 must be present.
+type AnalyticsLink struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z][A-Za-z0-9_]*$"
+	// Name of the link
+	Name string `json:"name"`
+	//+kubebuilder:validation:Pattern:="^[A-Za-z][A-Za-z0-9_]*$"
+	// Name of the dataverse, assumes "Default" if not present
+	DataverseName *string `json:"dataverseName,omitempty"`
+	// Links to a remote Couchbase cluster
+	Couchbase *AnalyticsCouchbaseLink `json:"couchbase,omitempty"`
+	// Links to Amazon S3
+	S3 *AnalyticsS3Link `json:"s3,omitempty"`
+}
+
+// Identifies the collection which is the source of an Analytics dataset
+type AnalyticsDatasetSource struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the bucket
+	BucketName string `json:"bucketName"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the scope, assumes "_default" if not present
+	ScopeName *string `json:"scopeName,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the collection, assumes "_default" if not present
+	CollectionName *string `json:"collectionName,omitempty"`
+}
+
+// Defines the desired state of an Analytics dataset
+type AnalyticsDataset struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z][A-Za-z0-9_]*$"
+	// Name of the dataset
+	Name string `json:"name"`
+	//+kubebuilder:validation:Pattern:="^[A-Za-z][A-Za-z0-9_]*$"
+	// Name of the dataverse, assumes "Default" if not present
+	DataverseName *string `json:"dataverseName,omitempty"`
+	//+kubebuilder:validation:Pattern:="^[A-Za-z][A-Za-z0-9_]*$"
+	// Name of the link in the same dataverse, assumes "Local" if not present
+	LinkName *string `json:"linkName,omitempty"`
+	// Collection which is the source of documents for the dataset
+	Source AnalyticsDatasetSource `json:"source"`
+	// Optional filter limiting the documents included in the dataset
+	Where *string `json:"where,omitempty"`
+}
+
+// Defines a field included in an Analytics index
+type AnalyticsIndexField struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Path to the field, with nested fields separated by periods
+	Path string `json:"path"`
+	//+kubebuilder:validation:Enum:=string;bigint;double
+	// Type of the field
+	Type string `json:"type"`
+}
+
+// Defines the desired state of an Analytics index
+type AnalyticsIndex struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z][A-Za-z0-9_]*$"
+	// Name of the index
+	Name string `json:"name"`
+	//+kubebuilder:validation:Pattern:="^[A-Za-z][A-Za-z0-9_]*$"
+	// Name of the dataverse, assumes "Default" if not present
+	DataverseName *string `json:"dataverseName,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the indexed dataset
+	DatasetName string `json:"datasetName"`
+	//+kubebuilder:validation:MinItems:=1
+	// Fields included in the index
+	Fields []AnalyticsIndexField `json:"fields"`
+}
+
+// Defines the desired state of CouchbaseAnalyticsSet
+type CouchbaseAnalyticsSetSpec struct {
+	// Defines how to connect to a Couchbase cluster
+	Cluster CouchbaseCluster `json:"cluster"`
+	// List of links to remote data sources, the "Local" link of each dataverse always exists
+	Links []AnalyticsLink `json:"links,omitempty"`
+	// List of datasets
+	Datasets []AnalyticsDataset `json:"datasets,omitempty"`
+	// List of indices on datasets
+	Indices []AnalyticsIndex `json:"indices,omitempty"`
+	//+kubebuilder:default=false
+	//+kubebuilder:validation:Optional
+	// Pauses synchronization for this set. Deleting the set will still perform cleanup.
+	Paused *bool `json:"paused"`
+}
+
+// Defines the observed state of a link, dataset, or index managed by an Analytics set
+type AppliedAnalyticsDefinition struct {
+	// Qualified name of the link, dataset, or index, starting with the dataverse
+	Name string `json:"name"`
+	// Hash of the definition most recently applied
+	DefinitionHash string `json:"definitionHash"`
+}
+
+// Defines the observed state of CouchbaseAnalyticsSet
+type CouchbaseAnalyticsSetStatus struct {
+	//+listType:=map
+	//+listMapKey:=type
+	// Conditions represent the latest available observations of an object's state
+	Conditions []metav1.Condition `json:"conditions"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of links created and managed by this resource
+	Links []AppliedAnalyticsDefinition `json:"links,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of datasets created and managed by this resource
+	Datasets []AppliedAnalyticsDefinition `json:"datasets,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of indices created and managed by this resource
+	Indices []AppliedAnalyticsDefinition `json:"indices,omitempty"`
+	// Time of the most recent sync attempt
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// Defines a set of Couchbase Analytics links, datasets, and indices
+type CouchbaseAnalyticsSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CouchbaseAnalyticsSetSpec   `json:"spec,omitempty"`
+	Status CouchbaseAnalyticsSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CouchbaseAnalyticsSetList contains a list of CouchbaseAnalyticsSet
+type CouchbaseAnalyticsSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CouchbaseAnalyticsSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CouchbaseAnalyticsSet{}, &CouchbaseAnalyticsSetList{})
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defines the desired state of a Couchbase collection
+type CouchbaseCollection struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:MaxLength:=251
+	//+kubebuilder:validation:Pattern:="^[A-Za-z0-9\\-][A-Za-z0-9_\\-%]*$"
+	// Name of the collection
+	Name string `json:"name"`
+	//+kubebuilder:validation:Minimum:=0
+	// Maximum time to live for documents in the collection, in seconds. If not present, uses the bucket's max TTL.
+	MaxTTL *int `json:"maxTTL,omitempty"`
+}
+
+// Defines the desired state of a Couchbase scope
+type CouchbaseScope struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:MaxLength:=251
+	//+kubebuilder:validation:Pattern:="^_default$|^[A-Za-z0-9\\-][A-Za-z0-9_\\-%]*$"
+	// Name of the scope, use "_default" to add collections to the default scope
+	Name string `json:"name"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of collections within the scope
+	Collections []CouchbaseCollection `json:"collections,omitempty"`
+}
+
+// Defines the desired state of a set of Couchbase scopes and collections
+type CouchbaseCollectionSetSpec struct {
+	// Defines how to connect to a Couchbase cluster
+	Cluster CouchbaseCluster `json:"cluster"`
+	// Name of the bucket
+	BucketName string `json:"bucketName"`
+	//+kubebuilder:validation:MinItems:=1
+	//+listType:=map
+	//+listMapKey:=name
+	// List of scopes
+	Scopes []CouchbaseScope `json:"scopes"`
+	//+kubebuilder:default=false
+	//+kubebuilder:validation:Optional
+	// Pauses scope and collection synchronization for this collection set
+	Paused *bool `json:"paused"`
+}
+
+// Defines the observed state of CouchbaseCollectionSet
+type CouchbaseCollectionSetStatus struct {
+	//+listType:=map
+	//+listMapKey:=type
+	// Conditions represent the latest available observations of an object's state
+	Conditions []metav1.Condition `json:"conditions"`
+	//+listType:=atomic
+	// List of collections which exist, in "scope.collection" format
+	Collections []string `json:"collections,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+//+kubebuilder:printcolumn:name="Bucket",type=string,JSONPath=`.spec.bucketName`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// Defines a set of Couchbase scopes and collections. Scopes and collections are created but never dropped.
+type CouchbaseCollectionSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CouchbaseCollectionSetSpec   `json:"spec,omitempty"`
+	Status CouchbaseCollectionSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CouchbaseCollectionSetList contains a list of CouchbaseCollectionSet
+type CouchbaseCollectionSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CouchbaseCollectionSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CouchbaseCollectionSet{}, &CouchbaseCollectionSetList{})
+}
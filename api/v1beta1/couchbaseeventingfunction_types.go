@@ -0,0 +1,175 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Identifies a bucket, scope, and collection
+type EventingKeyspace struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the bucket
+	BucketName string `json:"bucketName"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the scope, assumes "_default" if not present
+	ScopeName *string `json:"scopeName,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the collection, assumes "_default" if not present
+	CollectionName *string `json:"collectionName,omitempty"`
+}
+
+// Binds an alias in the function code to a keyspace
+type EventingBucketBinding struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z_$][A-Za-z0-9_$]*$"
+	// JavaScript identifier used to access the keyspace
+	Alias string `json:"alias"`
+	EventingKeyspace `json:",inline"`
+	//+kubebuilder:default:=ReadOnly
+	//+kubebuilder:validation:Enum:=ReadOnly;ReadWrite
+	// Access to the keyspace, defaults to ReadOnly
+	Access *string `json:"access,omitempty"`
+}
+
+// Binds an alias in the function code to a URL for use with curl()
+type EventingURLBinding struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z_$][A-Za-z0-9_$]*$"
+	// JavaScript identifier used to access the URL
+	Alias string `json:"alias"`
+	//+kubebuilder:validation:MinLength:=1
+	// URL prefix, including the scheme
+	Hostname string `json:"hostname"`
+	// Allows cookies to be sent and received
+	AllowCookies *bool `json:"allowCookies,omitempty"`
+	//+kubebuilder:default:=true
+	// Validates the SSL certificate of the remote server, defaults to true
+	ValidateSSLCertificate *bool `json:"validateSslCertificate,omitempty"`
+}
+
+// Binds an alias in the function code to a constant
+type EventingConstantBinding struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z_$][A-Za-z0-9_$]*$"
+	// JavaScript identifier used to access the constant
+	Alias string `json:"alias"`
+	// JavaScript literal value of the constant, such as "42" or "\"value\""
+	Value string `json:"value"`
+}
+
+// Defines settings of an Eventing function
+type EventingFunctionSettings struct {
+	//+kubebuilder:default:=Everything
+	//+kubebuilder:validation:Enum:=Everything;FromNow
+	// Mutations processed when the function is first deployed, defaults to Everything
+	StreamBoundary *string `json:"streamBoundary,omitempty"`
+	//+kubebuilder:validation:Minimum:=1
+	//+kubebuilder:validation:Maximum:=64
+	// Number of workers per Eventing node
+	WorkerCount *int `json:"workerCount,omitempty"`
+	//+kubebuilder:validation:Enum:=INFO;ERROR;WARNING;DEBUG;TRACE
+	// Level of application logging
+	LogLevel *string `json:"logLevel,omitempty"`
+	//+kubebuilder:validation:Minimum:=1
+	// Maximum time in seconds a handler may run
+	ExecutionTimeoutSeconds *int `json:"executionTimeoutSeconds,omitempty"`
+	// Language compatibility version, such as "6.6.2"
+	LanguageCompatibility *string `json:"languageCompatibility,omitempty"`
+}
+
+// Defines the desired state of CouchbaseEventingFunction
+type CouchbaseEventingFunctionSpec struct {
+	// Defines how to connect to a Couchbase cluster
+	Cluster CouchbaseCluster `json:"cluster"`
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:MaxLength:=100
+	//+kubebuilder:validation:Pattern:="^[A-Za-z0-9][A-Za-z0-9_\\-]*$"
+	// Name of the function within the cluster, defaults to the name of the resource
+	FunctionName *string `json:"functionName,omitempty"`
+	// Keyspace whose mutations are processed by the function
+	SourceKeyspace EventingKeyspace `json:"sourceKeyspace"`
+	// Keyspace used to store checkpoints and timers, which must be different from the source keyspace
+	MetadataKeyspace EventingKeyspace `json:"metadataKeyspace"`
+	//+kubebuilder:validation:MinLength:=1
+	// JavaScript code of the function
+	Code string `json:"code"`
+	//+listType:=map
+	//+listMapKey:=alias
+	// Keyspaces bound to the function
+	BucketBindings []EventingBucketBinding `json:"bucketBindings,omitempty"`
+	//+listType:=map
+	//+listMapKey:=alias
+	// URLs bound to the function
+	URLBindings []EventingURLBinding `json:"urlBindings,omitempty"`
+	//+listType:=map
+	//+listMapKey:=alias
+	// Constants bound to the function
+	ConstantBindings []EventingConstantBinding `json:"constantBindings,omitempty"`
+	// Settings of the function
+	Settings *EventingFunctionSettings `json:"settings,omitempty"`
+	//+kubebuilder:default:=Deployed
+	//+kubebuilder:validation:Enum:=Deployed;Paused;Undeployed
+	// Desired deployment state of the function, defaults to Deployed. Paused only applies to a function which
+	// has been deployed.
+	DeploymentState *string `json:"deploymentState,omitempty"`
+}
+
+// Defines the observed state of CouchbaseEventingFunction
+type CouchbaseEventingFunctionStatus struct {
+	//+listType:=map
+	//+listMapKey:=type
+	// Conditions represent the latest available observations of an object's state
+	Conditions []metav1.Condition `json:"conditions"`
+	// Name of the function within the cluster
+	FunctionName string `json:"functionName,omitempty"`
+	// Deployment status of the function reported by the Eventing service
+	DeploymentStatus string `json:"deploymentStatus,omitempty"`
+	// Hash of the function definition most recently applied
+	DefinitionHash string `json:"definitionHash,omitempty"`
+	// Time of the most recent sync attempt
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+//+kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.sourceKeyspace.bucketName`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.deploymentStatus`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// Defines a Couchbase Eventing function
+type CouchbaseEventingFunction struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CouchbaseEventingFunctionSpec   `json:"spec,omitempty"`
+	Status CouchbaseEventingFunctionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CouchbaseEventingFunctionList contains a list of CouchbaseEventingFunction
+type CouchbaseEventingFunctionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CouchbaseEventingFunction `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CouchbaseEventingFunction{}, &CouchbaseEventingFunctionList{})
+}
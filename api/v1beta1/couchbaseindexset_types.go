@@ -36,6 +36,50 @@ type GlobalSecondaryIndexPartition struct {
 	NumPartitions *int `json:"numPartitions,omitempty"`
 }
 
+// Defines a key within an array index key
+type GlobalSecondaryIndexFlattenKey struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Expression indexed for each array element, typically using the array variable
+	Expression string `json:"expression"`
+	//+kubebuilder:validation:Enum:=Ascending;Descending
+	// Sort direction of the key, defaults to Ascending
+	Direction *string `json:"direction,omitempty"`
+}
+
+// Defines how elements of an array are indexed
+type GlobalSecondaryIndexArrayKey struct {
+	//+kubebuilder:default:=Distinct
+	//+kubebuilder:validation:Enum:=Distinct;All
+	// Whether duplicate values within an array are indexed once (Distinct) or for every element (All)
+	Mode *string `json:"mode,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z_][A-Za-z0-9_]*$"
+	// Variable bound to each array element
+	Variable string `json:"variable"`
+	//+kubebuilder:validation:MinLength:=1
+	// Expression for the array
+	In string `json:"in"`
+	// Condition which filters the array elements included in the index
+	When *string `json:"when,omitempty"`
+	//+kubebuilder:validation:MinItems:=1
+	// Indexes multiple expressions for each element using FLATTEN_KEYS, instead of the key expression
+	FlattenKeys []GlobalSecondaryIndexFlattenKey `json:"flattenKeys,omitempty"`
+}
+
+// Defines a key of an index
+type GlobalSecondaryIndexKey struct {
+	// Property or deterministic function indexed by the key. For array keys, the expression indexed for each element.
+	// Required unless array.flattenKeys is present.
+	Expression *string `json:"expression,omitempty"`
+	//+kubebuilder:validation:Enum:=Ascending;Descending
+	// Sort direction of the key, defaults to Ascending
+	Direction *string `json:"direction,omitempty"`
+	// Includes documents where the key is missing, only allowed on the leading key
+	IncludeMissing *bool `json:"includeMissing,omitempty"`
+	// Indexes elements of an array, only one key in an index may be an array key
+	Array *GlobalSecondaryIndexArrayKey `json:"array,omitempty"`
+}
+
 // Defines the desired state of a Couchbase Global Secondary Index
 type GlobalSecondaryIndex struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
@@ -54,8 +98,25 @@ type GlobalSecondaryIndex struct {
 	// Name of the index's collection, assumes "_default" if not present
 	CollectionName *string `json:"collectionName,omitempty"`
 	//+kubebuilder:validation:MinItems:=1
-	// List of properties or deterministic functions which make up the index key
-	IndexKey []string `json:"indexKey"`
+	// List of scope names or glob patterns, creating the index in each matching scope. Only existing scopes are
+	// matched, and newly created scopes are picked up on the next sync. Only scopes in the bucket of the index set
+	// are matched. May not be combined with scopeName.
+	ScopeNames []string `json:"scopeNames,omitempty"`
+	//+kubebuilder:validation:MinItems:=1
+	// List of collection names or glob patterns, creating the index in each matching collection. Only existing
+	// collections are matched, and newly created collections are picked up on the next sync. May not be combined
+	// with collectionName.
+	CollectionNames []string `json:"collectionNames,omitempty"`
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:MinItems:=1
+	// List of properties or deterministic functions which make up the index key. Exactly one of indexKey or keys is
+	// required.
+	IndexKey []string `json:"indexKey,omitempty"`
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:MinItems:=1
+	// Structured alternative to indexKey, which supports ordering, MISSING semantics, and array indexing without
+	// writing the SQL++ syntax by hand
+	Keys []GlobalSecondaryIndexKey `json:"keys,omitempty"`
 	// Conditions to filter documents included on the index
 	Condition *string `json:"condition,omitempty"`
 	//+kubebuilder:validation:Minimum:=0
@@ -65,6 +126,10 @@ type GlobalSecondaryIndex struct {
 	RetainDeletedXAttr *bool `json:"retainDeletedXAttr,omitempty"`
 	// Defines partition information for a partitioned index
 	Partition *GlobalSecondaryIndexPartition `json:"partition,omitempty"`
+	// Prevents the index from being dropped automatically. Protection is retained after the index is removed from the
+	// index set, and must be lifted using liftDropProtection before the index will be dropped. Deleting the index set
+	// is blocked until the protection is lifted.
+	DropProtection *bool `json:"dropProtection,omitempty"`
 }
 
 type CouchbaseClusterRef struct {
@@ -80,6 +145,9 @@ type CouchbaseClusterManual struct {
 	ConnectionString string `json:"connectionString"`
 	// Name of a secret containing a username and password
 	SecretName string `json:"secretName"`
+	// Optional name of a secret containing PEM encoded CA certificates in the "ca.crt" key, used to verify the cluster
+	// when connecting to the REST APIs using "couchbases://". If not present, the system root certificates are used.
+	CASecretName *string `json:"caSecretName,omitempty"`
 }
 
 //+kubebuilder:validation:MinProperties:=1
@@ -92,19 +160,76 @@ type CouchbaseCluster struct {
 	Manual *CouchbaseClusterManual `json:"manual,omitempty"`
 }
 
+// Defines one of several Couchbase clusters targeted by an index set
+type CouchbaseIndexSetCluster struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:MaxLength:=40
+	//+kubebuilder:validation:Pattern:="^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	// Name identifying the cluster within the index set, used for its status and the names of its resources
+	Name string `json:"name"`
+	// Defines how to connect to the Couchbase cluster
+	Cluster CouchbaseCluster `json:"cluster"`
+}
+
+// Defines how changes are rolled out to multiple clusters
+type CouchbaseIndexSetRollout struct {
+	//+kubebuilder:default:=Parallel
+	//+kubebuilder:validation:Enum:=Parallel;Ordered
+	// Parallel syncs all clusters at once. Ordered syncs clusters in the order they are listed, waiting for each
+	// cluster to be in sync before syncing the next.
+	Strategy *string `json:"strategy,omitempty"`
+	//+kubebuilder:validation:Minimum:=0
+	// For ordered rollouts, the number of seconds to wait after a cluster is in sync before syncing the next cluster
+	WaitSeconds *int64 `json:"waitSeconds,omitempty"`
+}
+
+// Defines an instance of a CouchbaseIndexTemplate within an index set
+type CouchbaseIndexTemplateInstance struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the CouchbaseIndexTemplate resource. This resource must be in the same namespace.
+	TemplateName string `json:"templateName"`
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^_default$|^[A-Za-z0-9\\-][A-Za-z0-9_\\-%]*$"
+	// Name of the scope for the template's indices, overriding the scope defined by the template
+	ScopeName *string `json:"scopeName,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^_default$|^[A-Za-z0-9\\-][A-Za-z0-9_\\-%]*$"
+	// Name of the collection for the template's indices, overriding the collection defined by the template
+	CollectionName *string `json:"collectionName,omitempty"`
+	// Values for the template's parameters
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
 // Defines the desired state of a set of Couchbase indices
 type CouchbaseIndexSetSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// Defines how to connect to a Couchbase cluster
-	Cluster CouchbaseCluster `json:"cluster"`
+	// Defines how to connect to a Couchbase cluster. May not be combined with clusters.
+	Cluster *CouchbaseCluster `json:"cluster,omitempty"`
+	//+kubebuilder:validation:MinItems:=1
+	//+listType:=map
+	//+listMapKey:=name
+	// List of Couchbase clusters, each of which is synced separately. May not be combined with cluster.
+	Clusters []CouchbaseIndexSetCluster `json:"clusters,omitempty"`
+	// Defines how changes are rolled out when there are multiple clusters
+	Rollout *CouchbaseIndexSetRollout `json:"rollout,omitempty"`
 	// Name of the bucket
 	BucketName string `json:"bucketName"`
 	//+listType:=map
 	//+listMapKey:=name
 	// List of global secondary indices
 	Indices []GlobalSecondaryIndex `json:"indices,omitempty"`
+	//+listType:=atomic
+	// List of index templates to instantiate, adding their indices to the index set
+	Templates []CouchbaseIndexTemplateInstance `json:"templates,omitempty"`
+	// Indices written as N1QL CREATE INDEX statements, which are merged with indices. Statements which can't be
+	// represented as a global secondary index are rejected.
+	Ddl *CouchbaseIndexSetDdl `json:"ddl,omitempty"`
+	//+listType:=set
+	// List of drop protected indices which are no longer protected and may be dropped, in "scope.collection.name" format
+	// or just "name" for the default collection
+	LiftDropProtection []string `json:"liftDropProtection,omitempty"`
 	//+kubebuilder:default:=600
 	//+kubebuilder:validation:Optional
 	//+kubebuilder:validation:Minimum:=1
@@ -115,12 +240,270 @@ type CouchbaseIndexSetSpec struct {
 	//+kubebuilder:validation:Minimum:=0
 	// Specifies the number of retries before marking a sync attempt as failed.
 	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=0
+	// Specifies the duration in seconds to wait before dropping indices which are removed from the index set.
+	// Re-adding an index during this period cancels the drop. Drops are not delayed when the index set is deleted.
+	DropGracePeriodSeconds *int64 `json:"dropGracePeriodSeconds,omitempty"`
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=1
+	// Refuses to drop removed indices which have been scanned within this number of seconds, based on the index
+	// service statistics. Usage is not checked when the index set is deleted.
+	DropUsageWindowSeconds *int64 `json:"dropUsageWindowSeconds,omitempty"`
+	//+listType:=atomic
+	// Resources in the same namespace which must be Ready before the index set is synced, including cleanup when the
+	// index set is deleted. Dependencies which are being deleted are ignored.
+	DependsOn []CouchbaseIndexSetDependency `json:"dependsOn,omitempty"`
+	// Periodically collects usage statistics for the managed indices and reports indices which haven't been scanned
+	// recently, to help identify indices which may be removed
+	UsageTracking *CouchbaseIndexSetUsageTracking `json:"usageTracking,omitempty"`
+	//+listType:=atomic
+	// Queries which must use a particular index. After each successful sync the queries are explained, and the
+	// QueryPlansValid condition names any query whose plan doesn't use the expected index.
+	ExpectedPlans []CouchbaseIndexSetExpectedPlan `json:"expectedPlans,omitempty"`
+	// Runs representative workload queries through the Index Advisor and reports the recommended indices
+	Advisor *CouchbaseIndexSetAdvisor `json:"advisor,omitempty"`
+	// Estimates the memory and disk footprint of new indices before syncing, and blocks the sync if building them would
+	// push the index service past a threshold
+	CapacityCheck *CouchbaseIndexSetCapacityCheck `json:"capacityCheck,omitempty"`
+	// Restricts when syncs may run, for example to keep index builds outside of business hours
+	SyncWindows *CouchbaseIndexSetSyncWindows `json:"syncWindows,omitempty"`
+	//+kubebuilder:validation:Optional
+	// Also writes the indices as N1QL CREATE INDEX statements to the "indices.n1ql" key of the generated ConfigMap, for
+	// review and disaster recovery. The statements are informational, indices are still synced from "indices.yaml".
+	WriteDdl *bool `json:"writeDdl,omitempty"`
 	//+kubebuilder:default=false
 	//+kubebuilder:validation:Optional
 	// Pauses index synchronization for this index set. Deleting the index set will still perform cleanup.
 	Paused *bool `json:"paused"`
 }
 
+// Defines how index usage statistics are collected and when indices are reported as unused
+type CouchbaseIndexSetUsageTracking struct {
+	//+kubebuilder:default:=3600
+	//+kubebuilder:validation:Minimum:=60
+	// Number of seconds between collections of usage statistics
+	IntervalSeconds *int64 `json:"intervalSeconds,omitempty"`
+	//+kubebuilder:default:=2592000
+	//+kubebuilder:validation:Minimum:=1
+	// Reports indices which haven't been scanned within this number of seconds as unused. Indices which have never
+	// been scanned are measured from when the operator first collected their usage.
+	UnusedAfterSeconds *int64 `json:"unusedAfterSeconds,omitempty"`
+}
+
+// Defines a query which must use a particular index
+type CouchbaseIndexSetExpectedPlan struct {
+	// Name identifying the query in the QueryPlansValid condition, defaults to the statement
+	Name string `json:"name,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	// SQL++ query to explain
+	Statement string `json:"statement"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the index the query plan must use, in "scope.collection.name" format or just "name" to match an index
+	// of that name in any collection
+	MustUseIndex string `json:"mustUseIndex"`
+}
+
+// Defines representative workload queries which are run through the Index Advisor
+type CouchbaseIndexSetAdvisor struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Name of a ConfigMap in the same namespace, each key of which contains a single SQL++ query
+	ConfigMapName string `json:"configMapName"`
+	//+kubebuilder:default:=3600
+	//+kubebuilder:validation:Minimum:=60
+	// Number of seconds between runs of the Index Advisor. Changes to the queries are advised immediately.
+	IntervalSeconds *int64 `json:"intervalSeconds,omitempty"`
+}
+
+// Defines the thresholds for the capacity check performed before building new indices
+type CouchbaseIndexSetCapacityCheck struct {
+	//+kubebuilder:default:=90
+	//+kubebuilder:validation:Minimum:=1
+	//+kubebuilder:validation:Maximum:=100
+	// Maximum percentage of the index service memory quota which may be used once the new indices are built
+	MaxMemoryPercent *int32 `json:"maxMemoryPercent,omitempty"`
+	//+kubebuilder:default:=90
+	//+kubebuilder:validation:Minimum:=1
+	//+kubebuilder:validation:Maximum:=100
+	// Maximum percentage of the disk space holding index data on the index nodes which may be used once the new
+	// indices are built
+	MaxDiskPercent *int32 `json:"maxDiskPercent,omitempty"`
+	//+kubebuilder:default:=10
+	//+kubebuilder:validation:Minimum:=1
+	// Estimated number of array elements indexed per document by array indices, which can't be measured until the
+	// index is built
+	ArrayItemsPerDocument *int32 `json:"arrayItemsPerDocument,omitempty"`
+}
+
+// Defines a resource which must be Ready before an index set is synced
+type CouchbaseIndexSetDependency struct {
+	//+kubebuilder:default:=CouchbaseIndexSet
+	//+kubebuilder:validation:Enum:=CouchbaseIndexSet;CouchbaseCollectionSet;CouchbaseQueryFunctionSet
+	// Kind of the resource
+	Kind string `json:"kind,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the resource. This resource must be in the same namespace.
+	Name string `json:"name"`
+}
+
+// Defines indices written as N1QL CREATE INDEX statements. Statements may be supplied inline, from a ConfigMap, or
+// both. BUILD INDEX statements are ignored since indices are always built.
+type CouchbaseIndexSetDdl struct {
+	//+listType:=atomic
+	// List of CREATE INDEX statements, each entry may contain multiple statements separated by semicolons
+	Statements []string `json:"statements,omitempty"`
+	// Reads CREATE INDEX statements separated by semicolons from a ConfigMap in the same namespace
+	ConfigMapRef *CouchbaseIndexSetDdlConfigMapRef `json:"configMapRef,omitempty"`
+}
+
+// References a key of a ConfigMap containing N1QL statements
+type CouchbaseIndexSetDdlConfigMapRef struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the ConfigMap, which must be in the same namespace
+	Name string `json:"name"`
+	//+kubebuilder:validation:MinLength:=1
+	// Key within the ConfigMap containing the statements
+	Key string `json:"key"`
+}
+
+// Defines a recurring window of time during which syncs are allowed or denied
+type CouchbaseIndexSetSyncWindow struct {
+	//+kubebuilder:validation:Enum:=Allow;Deny
+	// Allow windows permit syncs only while one of them is active, Deny windows block syncs while active
+	Kind string `json:"kind"`
+	//+kubebuilder:validation:MinLength:=1
+	// Cron schedule for the start of the window, in "minute hour day-of-month month day-of-week" format
+	Schedule string `json:"schedule"`
+	//+kubebuilder:validation:Minimum:=60
+	// Length of the window in seconds
+	DurationSeconds int64 `json:"durationSeconds"`
+}
+
+// Defines when syncs may run. Each kind of sync has its own windows, and syncs of that kind are allowed at any time
+// if it has none. Deny windows take precedence over allow windows.
+type CouchbaseIndexSetSyncWindows struct {
+	// IANA time zone used to evaluate the schedules, such as "America/New_York". Defaults to UTC.
+	TimeZone *string `json:"timeZone,omitempty"`
+	//+listType:=atomic
+	// Windows for applying changes to the index set, changes made outside of these windows are held as pending
+	Changes []CouchbaseIndexSetSyncWindow `json:"changes,omitempty"`
+	//+listType:=atomic
+	// Windows for periodic resyncs when the index set hasn't changed since the last successful sync
+	Resyncs []CouchbaseIndexSetSyncWindow `json:"resyncs,omitempty"`
+	//+listType:=atomic
+	// Windows for dropping indices when the index set is deleted
+	Deletion []CouchbaseIndexSetSyncWindow `json:"deletion,omitempty"`
+}
+
+// Defines an index which has been removed from the index set and is waiting to be dropped
+type PendingIndexDrop struct {
+	// Name of the index, in "scope.collection.name" format or just "name" for the default collection
+	Name string `json:"name"`
+	// Time after which the index will be dropped
+	DropAfter metav1.Time `json:"dropAfter"`
+}
+
+// Usage statistics for a managed index, aggregated across all replicas and partitions
+type IndexUsage struct {
+	// Name of the index, in "scope.collection.name" format or just "name" for the default collection
+	Name string `json:"name"`
+	// Most recent time the index was scanned, omitted if it has never been scanned
+	LastScanTime *metav1.Time `json:"lastScanTime,omitempty"`
+	// Number of requests served by the index since the indexer started
+	NumRequests int64 `json:"numRequests"`
+	// Number of items in the index
+	ItemsCount int64 `json:"itemsCount"`
+	// Time the operator first collected usage for the index
+	TrackedSince metav1.Time `json:"trackedSince"`
+	// True if the index hasn't been scanned within unusedAfterSeconds
+	Unused bool `json:"unused,omitempty"`
+}
+
+// Recommendations from the Index Advisor for the workload queries of an index set
+type IndexSetAdvice struct {
+	// Time the queries were last advised
+	LastAdvised metav1.Time `json:"lastAdvised"`
+	// Hash of the advised queries, used to detect changes
+	QueriesHash string `json:"queriesHash,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// Advice for each query
+	Queries []QueryAdvice `json:"queries,omitempty"`
+	//+listType:=atomic
+	// Recommended indices which are not in the index set, for review before adding them to the index set
+	RecommendedIndices []GlobalSecondaryIndex `json:"recommendedIndices,omitempty"`
+}
+
+// Recommendations from the Index Advisor for a single query
+type QueryAdvice struct {
+	// Key of the query within the ConfigMap
+	Name string `json:"name"`
+	//+listType:=atomic
+	// Indices managed by the index set which the query would use, in "scope.collection.name" format or just "name"
+	// for the default collection
+	UsedIndices []string `json:"usedIndices,omitempty"`
+	//+listType:=atomic
+	// Names of the recommended indices for the query, in the same format as usedIndices
+	RecommendedIndices []string `json:"recommendedIndices,omitempty"`
+	// Error returned by the Index Advisor, if the query couldn't be advised
+	Error string `json:"error,omitempty"`
+}
+
+// Defines the revision of an index template applied to an index set
+type AppliedIndexTemplate struct {
+	// Name of the CouchbaseIndexTemplate resource
+	Name string `json:"name"`
+	// Generation of the CouchbaseIndexTemplate resource
+	Generation int64 `json:"generation"`
+}
+
+// Defines the observed state of one of several Couchbase clusters targeted by an index set
+type CouchbaseIndexSetClusterStatus struct {
+	// Name of the cluster within the index set
+	Name string `json:"name"`
+	//+listType:=map
+	//+listMapKey:=type
+	// Conditions represent the latest available observations of the cluster's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Name of the generated config map
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Connection to the cluster, used to drop its indices once it is removed from the index set
+	Cluster *CouchbaseCluster `json:"cluster,omitempty"`
+	//+listType:=atomic
+	// List of global secondary indices created and managed on this cluster
+	Indices []string `json:"indices,omitempty"`
+	//+listType:=atomic
+	// List of global secondary indices which are protected from being dropped
+	ProtectedIndices []string `json:"protectedIndices,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of indices which have been removed from the index set and are waiting to be dropped
+	PendingDrops []PendingIndexDrop `json:"pendingDrops,omitempty"`
+	//+listType:=atomic
+	// List of removed indices which were not dropped because they were recently used
+	InUseIndices []string `json:"inUseIndices,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// Revisions of the index templates applied by the most recent successful sync
+	Templates []AppliedIndexTemplate `json:"templates,omitempty"`
+	// Value of the couchbase.btburnett.com/sync-requested-at annotation when the most recent sync was started
+	LastSyncRequest string `json:"lastSyncRequest,omitempty"`
+	// Start of the next sync window, when a sync is held until then
+	NextSyncWindow *metav1.Time `json:"nextSyncWindow,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// Usage statistics for the managed indices, when usage tracking is enabled
+	IndexUsage []IndexUsage `json:"indexUsage,omitempty"`
+	// Time usage statistics were last collected
+	LastUsageCollection *metav1.Time `json:"lastUsageCollection,omitempty"`
+	// Recommendations from the Index Advisor, when the advisor is enabled
+	Advice *IndexSetAdvice `json:"advice,omitempty"`
+	// Time the expected query plans were last checked
+	LastQueryPlanCheck *metav1.Time `json:"lastQueryPlanCheck,omitempty"`
+	// Number of indices
+	IndexCount *int32 `json:"indexCount,omitempty"`
+}
+
 // Defines the observed state of CouchbaseIndexSet
 type CouchbaseIndexSetStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -132,9 +515,44 @@ type CouchbaseIndexSetStatus struct {
 	Conditions []metav1.Condition `json:"conditions"`
 	// Name of the generated config map
 	ConfigMapName string `json:"configMapName,omitempty"`
+	// Connection to the cluster the indices are managed on, used to find them again when switching to or from
+	// multiple clusters
+	Cluster *CouchbaseCluster `json:"cluster,omitempty"`
 	//+listType:=atomic
 	// List of global secondary indices created and managed by this resource
 	Indices []string `json:"indices,omitempty"`
+	//+listType:=atomic
+	// List of global secondary indices which are protected from being dropped
+	ProtectedIndices []string `json:"protectedIndices,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of indices which have been removed from the index set and are waiting to be dropped
+	PendingDrops []PendingIndexDrop `json:"pendingDrops,omitempty"`
+	//+listType:=atomic
+	// List of removed indices which were not dropped because they were recently used
+	InUseIndices []string `json:"inUseIndices,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// Revisions of the index templates applied by the most recent successful sync
+	Templates []AppliedIndexTemplate `json:"templates,omitempty"`
+	// Value of the couchbase.btburnett.com/sync-requested-at annotation when the most recent sync was started
+	LastSyncRequest string `json:"lastSyncRequest,omitempty"`
+	// Start of the next sync window, when a sync is held until then
+	NextSyncWindow *metav1.Time `json:"nextSyncWindow,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// Usage statistics for the managed indices, when usage tracking is enabled
+	IndexUsage []IndexUsage `json:"indexUsage,omitempty"`
+	// Time usage statistics were last collected
+	LastUsageCollection *metav1.Time `json:"lastUsageCollection,omitempty"`
+	// Recommendations from the Index Advisor, when the advisor is enabled
+	Advice *IndexSetAdvice `json:"advice,omitempty"`
+	// Time the expected query plans were last checked
+	LastQueryPlanCheck *metav1.Time `json:"lastQueryPlanCheck,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// Observed state of each cluster, when the index set targets multiple clusters
+	Clusters []CouchbaseIndexSetClusterStatus `json:"clusters,omitempty"`
 	// Number of indices
 	IndexCount *int32 `json:"indexCount"`
 }
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defines a parameter which is supplied when a template is instantiated
+type CouchbaseIndexTemplateParameter struct {
+	//+kubebuilder:validation:Pattern:="^[A-Za-z_][A-Za-z0-9_]*$"
+	// Name of the parameter, referenced as "${name}" within the template
+	Name string `json:"name"`
+	// Default value of the parameter. If not present, a value must be supplied when the template is instantiated.
+	Default *string `json:"default,omitempty"`
+}
+
+// Defines the desired state of a reusable set of Couchbase indices
+type CouchbaseIndexTemplateSpec struct {
+	//+listType:=map
+	//+listMapKey:=name
+	// List of parameters supplied when the template is instantiated
+	Parameters []CouchbaseIndexTemplateParameter `json:"parameters,omitempty"`
+	//+kubebuilder:validation:MinItems:=1
+	//+listType:=map
+	//+listMapKey:=name
+	// List of global secondary indices. Parameters may be referenced as "${name}" within the index key, condition,
+	// and partition expressions.
+	Indices []GlobalSecondaryIndex `json:"indices"`
+}
+
+//+kubebuilder:object:root=true
+
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// Defines a reusable, parameterized set of Couchbase indices which may be instantiated by a CouchbaseIndexSet
+type CouchbaseIndexTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CouchbaseIndexTemplateSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CouchbaseIndexTemplateList contains a list of CouchbaseIndexTemplate
+type CouchbaseIndexTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CouchbaseIndexTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CouchbaseIndexTemplate{}, &CouchbaseIndexTemplateList{})
+}
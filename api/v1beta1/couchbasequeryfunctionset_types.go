@@ -0,0 +1,144 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defines a JavaScript library used by SQL++ user-defined functions
+type QueryJavaScriptLibrary struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z_][A-Za-z0-9_\\-]*$"
+	// Name of the library
+	Name string `json:"name"`
+	//+kubebuilder:validation:MinLength:=1
+	// JavaScript source code of the library
+	Code string `json:"code"`
+}
+
+// Defines a JavaScript function within a library which implements a SQL++ user-defined function
+type QueryFunctionJavaScript struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the library within the same scope
+	LibraryName string `json:"libraryName"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the JavaScript function, defaults to the name of the user-defined function
+	FunctionName *string `json:"functionName,omitempty"`
+}
+
+// Defines a SQL++ user-defined function. Exactly one of expression or javaScript must be present.
+type QueryFunction struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z_][A-Za-z0-9_]*$"
+	// Name of the function
+	Name string `json:"name"`
+	//+listType:=atomic
+	// Names of the function parameters
+	Parameters []string `json:"parameters,omitempty"`
+	//+kubebuilder:validation:MinLength:=1
+	// SQL++ expression for an inline function
+	Expression *string `json:"expression,omitempty"`
+	// JavaScript function for an external function
+	JavaScript *QueryFunctionJavaScript `json:"javaScript,omitempty"`
+}
+
+// Defines the user-defined functions and JavaScript libraries within a scope
+type QueryFunctionScope struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^_default$|^[A-Za-z0-9\\-][A-Za-z0-9_\\-%]*$"
+	// Name of the scope
+	Name string `json:"name"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of JavaScript libraries
+	Libraries []QueryJavaScriptLibrary `json:"libraries,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of user-defined functions
+	Functions []QueryFunction `json:"functions,omitempty"`
+}
+
+// Defines the desired state of a set of SQL++ user-defined functions
+type CouchbaseQueryFunctionSetSpec struct {
+	// Defines how to connect to a Couchbase cluster
+	Cluster CouchbaseCluster `json:"cluster"`
+	// Name of the bucket
+	BucketName string `json:"bucketName"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of scopes containing functions and libraries
+	Scopes []QueryFunctionScope `json:"scopes,omitempty"`
+	//+kubebuilder:default=false
+	//+kubebuilder:validation:Optional
+	// Pauses function synchronization for this function set. Deleting the function set will still perform cleanup.
+	Paused *bool `json:"paused"`
+}
+
+// Defines the observed state of a function or library managed by a function set
+type AppliedQueryFunction struct {
+	// Name of the function or library, in "scope.name" format
+	Name string `json:"name"`
+	// Hash of the definition most recently applied
+	DefinitionHash string `json:"definitionHash"`
+}
+
+// Defines the observed state of CouchbaseQueryFunctionSet
+type CouchbaseQueryFunctionSetStatus struct {
+	//+listType:=map
+	//+listMapKey:=type
+	// Conditions represent the latest available observations of an object's state
+	Conditions []metav1.Condition `json:"conditions"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of JavaScript libraries created and managed by this resource
+	Libraries []AppliedQueryFunction `json:"libraries,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of user-defined functions created and managed by this resource
+	Functions []AppliedQueryFunction `json:"functions,omitempty"`
+	// Time of the most recent sync attempt
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+//+kubebuilder:printcolumn:name="Bucket",type=string,JSONPath=`.spec.bucketName`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// Defines a set of SQL++ user-defined functions and the JavaScript libraries they use
+type CouchbaseQueryFunctionSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CouchbaseQueryFunctionSetSpec   `json:"spec,omitempty"`
+	Status CouchbaseQueryFunctionSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CouchbaseQueryFunctionSetList contains a list of CouchbaseQueryFunctionSet
+type CouchbaseQueryFunctionSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CouchbaseQueryFunctionSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CouchbaseQueryFunctionSet{}, &CouchbaseQueryFunctionSetList{})
+}
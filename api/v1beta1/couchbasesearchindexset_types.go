@@ -0,0 +1,174 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Defines how documents are mapped to types within a full text search index
+type SearchIndexDocConfig struct {
+	//+kubebuilder:default:=scope.collection.type_field
+	//+kubebuilder:validation:Enum:=type_field;docid_prefix;docid_regexp;scope.collection.type_field;scope.collection.docid_prefix;scope.collection.docid_regexp
+	// Mode used to determine the type of each document, defaults to scope.collection.type_field
+	Mode *string `json:"mode,omitempty"`
+	//+kubebuilder:default:=type
+	// Document attribute containing the type, for type_field modes
+	TypeField *string `json:"typeField,omitempty"`
+	// Delimiter which ends the type prefix of document IDs, for docid_prefix modes
+	DocIDPrefixDelimiter *string `json:"docIdPrefixDelimiter,omitempty"`
+	// Regular expression which matches the type within document IDs, for docid_regexp modes
+	DocIDRegexp *string `json:"docIdRegexp,omitempty"`
+}
+
+// Defines the mapping of a type within a full text search index
+type SearchIndexTypeMapping struct {
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the type. When using a scope.collection mode this is in "scope.collection" or "scope.collection.type"
+	// format, which also selects the source collection.
+	Name string `json:"name"`
+	//+kubebuilder:pruning:PreserveUnknownFields
+	// Document mapping for the type as defined by the Search service, such as "dynamic" and "properties".
+	// If not present, all fields are indexed dynamically.
+	Mapping *runtime.RawExtension `json:"mapping,omitempty"`
+}
+
+// Defines the desired state of a Couchbase full text search index
+type SearchIndex struct {
+	//+kubebuilder:validation:MinLength:=1
+	//+kubebuilder:validation:Pattern:="^[A-Za-z][A-Za-z0-9_\\-]*$"
+	// Name of the index, which must be unique within the cluster
+	Name string `json:"name"`
+	//+kubebuilder:validation:MinLength:=1
+	// Name of the source scope. When present, type mapping names are relative to the scope, in "collection" or
+	// "collection.type" format, and a scope.collection mode is required.
+	ScopeName *string `json:"scopeName,omitempty"`
+	//+kubebuilder:validation:MinItems:=1
+	// List of source collections within the scope, which are indexed using a dynamic mapping unless a type mapping
+	// is listed for the collection. Requires scopeName.
+	CollectionNames []string `json:"collectionNames,omitempty"`
+	// Defines how documents are mapped to types
+	DocConfig *SearchIndexDocConfig `json:"docConfig,omitempty"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of type mappings. If not present, all documents are indexed using the default mapping.
+	Types []SearchIndexTypeMapping `json:"types,omitempty"`
+	//+kubebuilder:pruning:PreserveUnknownFields
+	// Document mapping used for documents which don't match a type. If not present, it is disabled when types are
+	// listed and indexes all fields dynamically otherwise.
+	DefaultMapping *runtime.RawExtension `json:"defaultMapping,omitempty"`
+	//+kubebuilder:default:=standard
+	// Name of the analyzer used for fields which don't specify an analyzer
+	DefaultAnalyzer *string `json:"defaultAnalyzer,omitempty"`
+	//+kubebuilder:pruning:PreserveUnknownFields
+	// Custom analysis components as defined by the Search service, such as "analyzers", "char_filters",
+	// "token_filters", and "tokenizers"
+	Analysis *runtime.RawExtension `json:"analysis,omitempty"`
+	//+kubebuilder:validation:Minimum:=0
+	//+kubebuilder:validation:Maximum:=3
+	// Number of replicas
+	NumReplicas *int `json:"numReplicas,omitempty"`
+	//+kubebuilder:validation:Minimum:=1
+	// Number of index partitions
+	NumPartitions *int `json:"numPartitions,omitempty"`
+}
+
+// Defines the desired state of a set of Couchbase full text search indices
+type CouchbaseSearchIndexSetSpec struct {
+	// Defines how to connect to a Couchbase cluster
+	Cluster CouchbaseCluster `json:"cluster"`
+	// Name of the bucket
+	BucketName string `json:"bucketName"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of full text search indices
+	Indices []SearchIndex `json:"indices,omitempty"`
+	//+kubebuilder:default=false
+	//+kubebuilder:validation:Optional
+	// Pauses index synchronization for this index set. Deleting the index set will still perform cleanup.
+	Paused *bool `json:"paused"`
+	//+kubebuilder:default:=600
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=1
+	// Specifies the duration in seconds relative to the startTime that a sync attempt may be active before the system tries to terminate it; value must be positive integer
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	//+kubebuilder:default:=2
+	//+kubebuilder:validation:Optional
+	//+kubebuilder:validation:Minimum:=0
+	// Specifies the number of retries before marking a sync attempt as failed.
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+// Defines the observed state of a full text search index managed by an index set
+type SearchIndexStatus struct {
+	// Name of the index
+	Name string `json:"name"`
+	// Hash of the index definition most recently applied
+	DefinitionHash string `json:"definitionHash"`
+	// UUID assigned to the index definition by the Search service, used to detect external changes
+	UUID string `json:"uuid,omitempty"`
+}
+
+// Defines the observed state of CouchbaseSearchIndexSet
+type CouchbaseSearchIndexSetStatus struct {
+	//+listType:=map
+	//+listMapKey:=type
+	// Conditions represent the latest available observations of an object's state
+	Conditions []metav1.Condition `json:"conditions"`
+	//+listType:=map
+	//+listMapKey:=name
+	// List of full text search indices created and managed by this resource
+	Indices []SearchIndexStatus `json:"indices,omitempty"`
+	// Time of the most recent sync attempt
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Name of the ConfigMap holding the request for the most recent sync Job
+	ConfigMapName string `json:"configMapName,omitempty"`
+	// Name of the most recent sync Job whose results are reflected in the tracked indices
+	LastSyncJobName string `json:"lastSyncJobName,omitempty"`
+	// Number of indices
+	IndexCount *int32 `json:"indexCount"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+//+kubebuilder:printcolumn:name="Bucket",type=string,JSONPath=`.spec.bucketName`
+//+kubebuilder:printcolumn:name="Indices",type=integer,JSONPath=`.status.indexCount`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// Defines a set of Couchbase full text search indices
+type CouchbaseSearchIndexSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CouchbaseSearchIndexSetSpec   `json:"spec,omitempty"`
+	Status CouchbaseSearchIndexSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CouchbaseSearchIndexSetList contains a list of CouchbaseSearchIndexSet
+type CouchbaseSearchIndexSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CouchbaseSearchIndexSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CouchbaseSearchIndexSet{}, &CouchbaseSearchIndexSetList{})
+}
@@ -23,71 +23,222 @@ package v1beta1
 
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CouchbaseCluster) DeepCopyInto(out *CouchbaseCluster) {
+func (in *AnalyticsCouchbaseLink) DeepCopyInto(out *AnalyticsCouchbaseLink) {
 	*out = *in
-	if in.ClusterRef != nil {
-		in, out := &in.ClusterRef, &out.ClusterRef
-		*out = new(CouchbaseClusterRef)
-		(*in).DeepCopyInto(*out)
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(string)
+		**out = **in
 	}
-	if in.Manual != nil {
-		in, out := &in.Manual, &out.Manual
-		*out = new(CouchbaseClusterManual)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsCouchbaseLink.
+func (in *AnalyticsCouchbaseLink) DeepCopy() *AnalyticsCouchbaseLink {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyticsCouchbaseLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyticsDataset) DeepCopyInto(out *AnalyticsDataset) {
+	*out = *in
+	if in.DataverseName != nil {
+		in, out := &in.DataverseName, &out.DataverseName
+		*out = new(string)
+		**out = **in
+	}
+	if in.LinkName != nil {
+		in, out := &in.LinkName, &out.LinkName
+		*out = new(string)
+		**out = **in
+	}
+	in.Source.DeepCopyInto(&out.Source)
+	if in.Where != nil {
+		in, out := &in.Where, &out.Where
+		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseCluster.
-func (in *CouchbaseCluster) DeepCopy() *CouchbaseCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsDataset.
+func (in *AnalyticsDataset) DeepCopy() *AnalyticsDataset {
 	if in == nil {
 		return nil
 	}
-	out := new(CouchbaseCluster)
+	out := new(AnalyticsDataset)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CouchbaseClusterManual) DeepCopyInto(out *CouchbaseClusterManual) {
+func (in *AnalyticsDatasetSource) DeepCopyInto(out *AnalyticsDatasetSource) {
 	*out = *in
+	if in.ScopeName != nil {
+		in, out := &in.ScopeName, &out.ScopeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.CollectionName != nil {
+		in, out := &in.CollectionName, &out.CollectionName
+		*out = new(string)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseClusterManual.
-func (in *CouchbaseClusterManual) DeepCopy() *CouchbaseClusterManual {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsDatasetSource.
+func (in *AnalyticsDatasetSource) DeepCopy() *AnalyticsDatasetSource {
 	if in == nil {
 		return nil
 	}
-	out := new(CouchbaseClusterManual)
+	out := new(AnalyticsDatasetSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CouchbaseClusterRef) DeepCopyInto(out *CouchbaseClusterRef) {
+func (in *AnalyticsIndex) DeepCopyInto(out *AnalyticsIndex) {
 	*out = *in
-	if in.SecretName != nil {
-		in, out := &in.SecretName, &out.SecretName
+	if in.DataverseName != nil {
+		in, out := &in.DataverseName, &out.DataverseName
 		*out = new(string)
 		**out = **in
 	}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]AnalyticsIndexField, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseClusterRef.
-func (in *CouchbaseClusterRef) DeepCopy() *CouchbaseClusterRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsIndex.
+func (in *AnalyticsIndex) DeepCopy() *AnalyticsIndex {
 	if in == nil {
 		return nil
 	}
-	out := new(CouchbaseClusterRef)
+	out := new(AnalyticsIndex)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CouchbaseIndexSet) DeepCopyInto(out *CouchbaseIndexSet) {
+func (in *AnalyticsIndexField) DeepCopyInto(out *AnalyticsIndexField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsIndexField.
+func (in *AnalyticsIndexField) DeepCopy() *AnalyticsIndexField {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyticsIndexField)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyticsLink) DeepCopyInto(out *AnalyticsLink) {
+	*out = *in
+	if in.DataverseName != nil {
+		in, out := &in.DataverseName, &out.DataverseName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Couchbase != nil {
+		in, out := &in.Couchbase, &out.Couchbase
+		*out = new(AnalyticsCouchbaseLink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(AnalyticsS3Link)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsLink.
+func (in *AnalyticsLink) DeepCopy() *AnalyticsLink {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyticsLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyticsS3Link) DeepCopyInto(out *AnalyticsS3Link) {
+	*out = *in
+	if in.ServiceEndpoint != nil {
+		in, out := &in.ServiceEndpoint, &out.ServiceEndpoint
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyticsS3Link.
+func (in *AnalyticsS3Link) DeepCopy() *AnalyticsS3Link {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyticsS3Link)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedAnalyticsDefinition) DeepCopyInto(out *AppliedAnalyticsDefinition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedAnalyticsDefinition.
+func (in *AppliedAnalyticsDefinition) DeepCopy() *AppliedAnalyticsDefinition {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedAnalyticsDefinition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedIndexTemplate) DeepCopyInto(out *AppliedIndexTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedIndexTemplate.
+func (in *AppliedIndexTemplate) DeepCopy() *AppliedIndexTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedIndexTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedQueryFunction) DeepCopyInto(out *AppliedQueryFunction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedQueryFunction.
+func (in *AppliedQueryFunction) DeepCopy() *AppliedQueryFunction {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedQueryFunction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseAnalyticsSet) DeepCopyInto(out *CouchbaseAnalyticsSet) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -95,18 +246,18 @@ func (in *CouchbaseIndexSet) DeepCopyInto(out *CouchbaseIndexSet) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSet.
-func (in *CouchbaseIndexSet) DeepCopy() *CouchbaseIndexSet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseAnalyticsSet.
+func (in *CouchbaseAnalyticsSet) DeepCopy() *CouchbaseAnalyticsSet {
 	if in == nil {
 		return nil
 	}
-	out := new(CouchbaseIndexSet)
+	out := new(CouchbaseAnalyticsSet)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CouchbaseIndexSet) DeepCopyObject() runtime.Object {
+func (in *CouchbaseAnalyticsSet) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -114,31 +265,31 @@ func (in *CouchbaseIndexSet) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CouchbaseIndexSetList) DeepCopyInto(out *CouchbaseIndexSetList) {
+func (in *CouchbaseAnalyticsSetList) DeepCopyInto(out *CouchbaseAnalyticsSetList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]CouchbaseIndexSet, len(*in))
+		*out = make([]CouchbaseAnalyticsSet, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetList.
-func (in *CouchbaseIndexSetList) DeepCopy() *CouchbaseIndexSetList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseAnalyticsSetList.
+func (in *CouchbaseAnalyticsSetList) DeepCopy() *CouchbaseAnalyticsSetList {
 	if in == nil {
 		return nil
 	}
-	out := new(CouchbaseIndexSetList)
+	out := new(CouchbaseAnalyticsSetList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CouchbaseIndexSetList) DeepCopyObject() runtime.Object {
+func (in *CouchbaseAnalyticsSetList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -146,25 +297,29 @@ func (in *CouchbaseIndexSetList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CouchbaseIndexSetSpec) DeepCopyInto(out *CouchbaseIndexSetSpec) {
+func (in *CouchbaseAnalyticsSetSpec) DeepCopyInto(out *CouchbaseAnalyticsSetSpec) {
 	*out = *in
 	in.Cluster.DeepCopyInto(&out.Cluster)
-	if in.Indices != nil {
-		in, out := &in.Indices, &out.Indices
-		*out = make([]GlobalSecondaryIndex, len(*in))
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]AnalyticsLink, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.ActiveDeadlineSeconds != nil {
-		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
-		*out = new(int64)
-		**out = **in
+	if in.Datasets != nil {
+		in, out := &in.Datasets, &out.Datasets
+		*out = make([]AnalyticsDataset, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.BackoffLimit != nil {
-		in, out := &in.BackoffLimit, &out.BackoffLimit
-		*out = new(int32)
-		**out = **in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]AnalyticsIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Paused != nil {
 		in, out := &in.Paused, &out.Paused
@@ -173,18 +328,18 @@ func (in *CouchbaseIndexSetSpec) DeepCopyInto(out *CouchbaseIndexSetSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetSpec.
-func (in *CouchbaseIndexSetSpec) DeepCopy() *CouchbaseIndexSetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseAnalyticsSetSpec.
+func (in *CouchbaseAnalyticsSetSpec) DeepCopy() *CouchbaseAnalyticsSetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CouchbaseIndexSetSpec)
+	out := new(CouchbaseAnalyticsSetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CouchbaseIndexSetStatus) DeepCopyInto(out *CouchbaseIndexSetStatus) {
+func (in *CouchbaseAnalyticsSetStatus) DeepCopyInto(out *CouchbaseAnalyticsSetStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -193,104 +348,2000 @@ func (in *CouchbaseIndexSetStatus) DeepCopyInto(out *CouchbaseIndexSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Links != nil {
+		in, out := &in.Links, &out.Links
+		*out = make([]AppliedAnalyticsDefinition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Datasets != nil {
+		in, out := &in.Datasets, &out.Datasets
+		*out = make([]AppliedAnalyticsDefinition, len(*in))
+		copy(*out, *in)
+	}
 	if in.Indices != nil {
 		in, out := &in.Indices, &out.Indices
-		*out = make([]string, len(*in))
+		*out = make([]AppliedAnalyticsDefinition, len(*in))
 		copy(*out, *in)
 	}
-	if in.IndexCount != nil {
-		in, out := &in.IndexCount, &out.IndexCount
-		*out = new(int32)
-		**out = **in
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetStatus.
-func (in *CouchbaseIndexSetStatus) DeepCopy() *CouchbaseIndexSetStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseAnalyticsSetStatus.
+func (in *CouchbaseAnalyticsSetStatus) DeepCopy() *CouchbaseAnalyticsSetStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CouchbaseIndexSetStatus)
+	out := new(CouchbaseAnalyticsSetStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalSecondaryIndex) DeepCopyInto(out *GlobalSecondaryIndex) {
+func (in *CouchbaseCluster) DeepCopyInto(out *CouchbaseCluster) {
 	*out = *in
-	if in.ScopeName != nil {
-		in, out := &in.ScopeName, &out.ScopeName
-		*out = new(string)
-		**out = **in
+	if in.ClusterRef != nil {
+		in, out := &in.ClusterRef, &out.ClusterRef
+		*out = new(CouchbaseClusterRef)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.CollectionName != nil {
-		in, out := &in.CollectionName, &out.CollectionName
-		*out = new(string)
-		**out = **in
+	if in.Manual != nil {
+		in, out := &in.Manual, &out.Manual
+		*out = new(CouchbaseClusterManual)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.IndexKey != nil {
-		in, out := &in.IndexKey, &out.IndexKey
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseCluster.
+func (in *CouchbaseCluster) DeepCopy() *CouchbaseCluster {
+	if in == nil {
+		return nil
 	}
-	if in.Condition != nil {
-		in, out := &in.Condition, &out.Condition
+	out := new(CouchbaseCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseClusterManual) DeepCopyInto(out *CouchbaseClusterManual) {
+	*out = *in
+	if in.CASecretName != nil {
+		in, out := &in.CASecretName, &out.CASecretName
 		*out = new(string)
 		**out = **in
 	}
-	if in.NumReplicas != nil {
-		in, out := &in.NumReplicas, &out.NumReplicas
-		*out = new(int)
-		**out = **in
-	}
-	if in.RetainDeletedXAttr != nil {
-		in, out := &in.RetainDeletedXAttr, &out.RetainDeletedXAttr
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Partition != nil {
-		in, out := &in.Partition, &out.Partition
-		*out = new(GlobalSecondaryIndexPartition)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalSecondaryIndex.
-func (in *GlobalSecondaryIndex) DeepCopy() *GlobalSecondaryIndex {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseClusterManual.
+func (in *CouchbaseClusterManual) DeepCopy() *CouchbaseClusterManual {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalSecondaryIndex)
+	out := new(CouchbaseClusterManual)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GlobalSecondaryIndexPartition) DeepCopyInto(out *GlobalSecondaryIndexPartition) {
+func (in *CouchbaseClusterRef) DeepCopyInto(out *CouchbaseClusterRef) {
 	*out = *in
-	if in.Expressions != nil {
-		in, out := &in.Expressions, &out.Expressions
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Strategy != nil {
-		in, out := &in.Strategy, &out.Strategy
+	if in.SecretName != nil {
+		in, out := &in.SecretName, &out.SecretName
 		*out = new(string)
 		**out = **in
 	}
-	if in.NumPartitions != nil {
-		in, out := &in.NumPartitions, &out.NumPartitions
-		*out = new(int)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalSecondaryIndexPartition.
-func (in *GlobalSecondaryIndexPartition) DeepCopy() *GlobalSecondaryIndexPartition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseClusterRef.
+func (in *CouchbaseClusterRef) DeepCopy() *CouchbaseClusterRef {
 	if in == nil {
 		return nil
 	}
-	out := new(GlobalSecondaryIndexPartition)
+	out := new(CouchbaseClusterRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseCollection) DeepCopyInto(out *CouchbaseCollection) {
+	*out = *in
+	if in.MaxTTL != nil {
+		in, out := &in.MaxTTL, &out.MaxTTL
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseCollection.
+func (in *CouchbaseCollection) DeepCopy() *CouchbaseCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseCollectionSet) DeepCopyInto(out *CouchbaseCollectionSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseCollectionSet.
+func (in *CouchbaseCollectionSet) DeepCopy() *CouchbaseCollectionSet {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseCollectionSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseCollectionSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseCollectionSetList) DeepCopyInto(out *CouchbaseCollectionSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CouchbaseCollectionSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseCollectionSetList.
+func (in *CouchbaseCollectionSetList) DeepCopy() *CouchbaseCollectionSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseCollectionSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseCollectionSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseCollectionSetSpec) DeepCopyInto(out *CouchbaseCollectionSetSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]CouchbaseScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseCollectionSetSpec.
+func (in *CouchbaseCollectionSetSpec) DeepCopy() *CouchbaseCollectionSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseCollectionSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseCollectionSetStatus) DeepCopyInto(out *CouchbaseCollectionSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseCollectionSetStatus.
+func (in *CouchbaseCollectionSetStatus) DeepCopy() *CouchbaseCollectionSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseCollectionSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseEventingFunction) DeepCopyInto(out *CouchbaseEventingFunction) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseEventingFunction.
+func (in *CouchbaseEventingFunction) DeepCopy() *CouchbaseEventingFunction {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseEventingFunction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseEventingFunction) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseEventingFunctionList) DeepCopyInto(out *CouchbaseEventingFunctionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CouchbaseEventingFunction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseEventingFunctionList.
+func (in *CouchbaseEventingFunctionList) DeepCopy() *CouchbaseEventingFunctionList {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseEventingFunctionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseEventingFunctionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseEventingFunctionSpec) DeepCopyInto(out *CouchbaseEventingFunctionSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.FunctionName != nil {
+		in, out := &in.FunctionName, &out.FunctionName
+		*out = new(string)
+		**out = **in
+	}
+	in.SourceKeyspace.DeepCopyInto(&out.SourceKeyspace)
+	in.MetadataKeyspace.DeepCopyInto(&out.MetadataKeyspace)
+	if in.BucketBindings != nil {
+		in, out := &in.BucketBindings, &out.BucketBindings
+		*out = make([]EventingBucketBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.URLBindings != nil {
+		in, out := &in.URLBindings, &out.URLBindings
+		*out = make([]EventingURLBinding, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConstantBindings != nil {
+		in, out := &in.ConstantBindings, &out.ConstantBindings
+		*out = make([]EventingConstantBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = new(EventingFunctionSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeploymentState != nil {
+		in, out := &in.DeploymentState, &out.DeploymentState
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseEventingFunctionSpec.
+func (in *CouchbaseEventingFunctionSpec) DeepCopy() *CouchbaseEventingFunctionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseEventingFunctionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseEventingFunctionStatus) DeepCopyInto(out *CouchbaseEventingFunctionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseEventingFunctionStatus.
+func (in *CouchbaseEventingFunctionStatus) DeepCopy() *CouchbaseEventingFunctionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseEventingFunctionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSet) DeepCopyInto(out *CouchbaseIndexSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSet.
+func (in *CouchbaseIndexSet) DeepCopy() *CouchbaseIndexSet {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseIndexSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetAdvisor) DeepCopyInto(out *CouchbaseIndexSetAdvisor) {
+	*out = *in
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetAdvisor.
+func (in *CouchbaseIndexSetAdvisor) DeepCopy() *CouchbaseIndexSetAdvisor {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetAdvisor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetCapacityCheck) DeepCopyInto(out *CouchbaseIndexSetCapacityCheck) {
+	*out = *in
+	if in.MaxMemoryPercent != nil {
+		in, out := &in.MaxMemoryPercent, &out.MaxMemoryPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxDiskPercent != nil {
+		in, out := &in.MaxDiskPercent, &out.MaxDiskPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ArrayItemsPerDocument != nil {
+		in, out := &in.ArrayItemsPerDocument, &out.ArrayItemsPerDocument
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetCapacityCheck.
+func (in *CouchbaseIndexSetCapacityCheck) DeepCopy() *CouchbaseIndexSetCapacityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetCapacityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetCluster) DeepCopyInto(out *CouchbaseIndexSetCluster) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetCluster.
+func (in *CouchbaseIndexSetCluster) DeepCopy() *CouchbaseIndexSetCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetClusterStatus) DeepCopyInto(out *CouchbaseIndexSetClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(CouchbaseCluster)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtectedIndices != nil {
+		in, out := &in.ProtectedIndices, &out.ProtectedIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingDrops != nil {
+		in, out := &in.PendingDrops, &out.PendingDrops
+		*out = make([]PendingIndexDrop, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InUseIndices != nil {
+		in, out := &in.InUseIndices, &out.InUseIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]AppliedIndexTemplate, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextSyncWindow != nil {
+		in, out := &in.NextSyncWindow, &out.NextSyncWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.IndexUsage != nil {
+		in, out := &in.IndexUsage, &out.IndexUsage
+		*out = make([]IndexUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUsageCollection != nil {
+		in, out := &in.LastUsageCollection, &out.LastUsageCollection
+		*out = (*in).DeepCopy()
+	}
+	if in.Advice != nil {
+		in, out := &in.Advice, &out.Advice
+		*out = new(IndexSetAdvice)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastQueryPlanCheck != nil {
+		in, out := &in.LastQueryPlanCheck, &out.LastQueryPlanCheck
+		*out = (*in).DeepCopy()
+	}
+	if in.IndexCount != nil {
+		in, out := &in.IndexCount, &out.IndexCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetClusterStatus.
+func (in *CouchbaseIndexSetClusterStatus) DeepCopy() *CouchbaseIndexSetClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetDdl) DeepCopyInto(out *CouchbaseIndexSetDdl) {
+	*out = *in
+	if in.Statements != nil {
+		in, out := &in.Statements, &out.Statements
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(CouchbaseIndexSetDdlConfigMapRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetDdl.
+func (in *CouchbaseIndexSetDdl) DeepCopy() *CouchbaseIndexSetDdl {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetDdl)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetDdlConfigMapRef) DeepCopyInto(out *CouchbaseIndexSetDdlConfigMapRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetDdlConfigMapRef.
+func (in *CouchbaseIndexSetDdlConfigMapRef) DeepCopy() *CouchbaseIndexSetDdlConfigMapRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetDdlConfigMapRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetDependency) DeepCopyInto(out *CouchbaseIndexSetDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetDependency.
+func (in *CouchbaseIndexSetDependency) DeepCopy() *CouchbaseIndexSetDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetExpectedPlan) DeepCopyInto(out *CouchbaseIndexSetExpectedPlan) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetExpectedPlan.
+func (in *CouchbaseIndexSetExpectedPlan) DeepCopy() *CouchbaseIndexSetExpectedPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetExpectedPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetList) DeepCopyInto(out *CouchbaseIndexSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CouchbaseIndexSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetList.
+func (in *CouchbaseIndexSetList) DeepCopy() *CouchbaseIndexSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseIndexSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetRollout) DeepCopyInto(out *CouchbaseIndexSetRollout) {
+	*out = *in
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(string)
+		**out = **in
+	}
+	if in.WaitSeconds != nil {
+		in, out := &in.WaitSeconds, &out.WaitSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetRollout.
+func (in *CouchbaseIndexSetRollout) DeepCopy() *CouchbaseIndexSetRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetSpec) DeepCopyInto(out *CouchbaseIndexSetSpec) {
+	*out = *in
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(CouchbaseCluster)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]CouchbaseIndexSetCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(CouchbaseIndexSetRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]GlobalSecondaryIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]CouchbaseIndexTemplateInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ddl != nil {
+		in, out := &in.Ddl, &out.Ddl
+		*out = new(CouchbaseIndexSetDdl)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LiftDropProtection != nil {
+		in, out := &in.LiftDropProtection, &out.LiftDropProtection
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DropGracePeriodSeconds != nil {
+		in, out := &in.DropGracePeriodSeconds, &out.DropGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DropUsageWindowSeconds != nil {
+		in, out := &in.DropUsageWindowSeconds, &out.DropUsageWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]CouchbaseIndexSetDependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsageTracking != nil {
+		in, out := &in.UsageTracking, &out.UsageTracking
+		*out = new(CouchbaseIndexSetUsageTracking)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpectedPlans != nil {
+		in, out := &in.ExpectedPlans, &out.ExpectedPlans
+		*out = make([]CouchbaseIndexSetExpectedPlan, len(*in))
+		copy(*out, *in)
+	}
+	if in.Advisor != nil {
+		in, out := &in.Advisor, &out.Advisor
+		*out = new(CouchbaseIndexSetAdvisor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CapacityCheck != nil {
+		in, out := &in.CapacityCheck, &out.CapacityCheck
+		*out = new(CouchbaseIndexSetCapacityCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncWindows != nil {
+		in, out := &in.SyncWindows, &out.SyncWindows
+		*out = new(CouchbaseIndexSetSyncWindows)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WriteDdl != nil {
+		in, out := &in.WriteDdl, &out.WriteDdl
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetSpec.
+func (in *CouchbaseIndexSetSpec) DeepCopy() *CouchbaseIndexSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetStatus) DeepCopyInto(out *CouchbaseIndexSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(CouchbaseCluster)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtectedIndices != nil {
+		in, out := &in.ProtectedIndices, &out.ProtectedIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingDrops != nil {
+		in, out := &in.PendingDrops, &out.PendingDrops
+		*out = make([]PendingIndexDrop, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InUseIndices != nil {
+		in, out := &in.InUseIndices, &out.InUseIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]AppliedIndexTemplate, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextSyncWindow != nil {
+		in, out := &in.NextSyncWindow, &out.NextSyncWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.IndexUsage != nil {
+		in, out := &in.IndexUsage, &out.IndexUsage
+		*out = make([]IndexUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUsageCollection != nil {
+		in, out := &in.LastUsageCollection, &out.LastUsageCollection
+		*out = (*in).DeepCopy()
+	}
+	if in.Advice != nil {
+		in, out := &in.Advice, &out.Advice
+		*out = new(IndexSetAdvice)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastQueryPlanCheck != nil {
+		in, out := &in.LastQueryPlanCheck, &out.LastQueryPlanCheck
+		*out = (*in).DeepCopy()
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]CouchbaseIndexSetClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IndexCount != nil {
+		in, out := &in.IndexCount, &out.IndexCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetStatus.
+func (in *CouchbaseIndexSetStatus) DeepCopy() *CouchbaseIndexSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetSyncWindow) DeepCopyInto(out *CouchbaseIndexSetSyncWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetSyncWindow.
+func (in *CouchbaseIndexSetSyncWindow) DeepCopy() *CouchbaseIndexSetSyncWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetSyncWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetSyncWindows) DeepCopyInto(out *CouchbaseIndexSetSyncWindows) {
+	*out = *in
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]CouchbaseIndexSetSyncWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resyncs != nil {
+		in, out := &in.Resyncs, &out.Resyncs
+		*out = make([]CouchbaseIndexSetSyncWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = make([]CouchbaseIndexSetSyncWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetSyncWindows.
+func (in *CouchbaseIndexSetSyncWindows) DeepCopy() *CouchbaseIndexSetSyncWindows {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetSyncWindows)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexSetUsageTracking) DeepCopyInto(out *CouchbaseIndexSetUsageTracking) {
+	*out = *in
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.UnusedAfterSeconds != nil {
+		in, out := &in.UnusedAfterSeconds, &out.UnusedAfterSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexSetUsageTracking.
+func (in *CouchbaseIndexSetUsageTracking) DeepCopy() *CouchbaseIndexSetUsageTracking {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexSetUsageTracking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexTemplate) DeepCopyInto(out *CouchbaseIndexTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexTemplate.
+func (in *CouchbaseIndexTemplate) DeepCopy() *CouchbaseIndexTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseIndexTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexTemplateInstance) DeepCopyInto(out *CouchbaseIndexTemplateInstance) {
+	*out = *in
+	if in.ScopeName != nil {
+		in, out := &in.ScopeName, &out.ScopeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.CollectionName != nil {
+		in, out := &in.CollectionName, &out.CollectionName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexTemplateInstance.
+func (in *CouchbaseIndexTemplateInstance) DeepCopy() *CouchbaseIndexTemplateInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexTemplateInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexTemplateList) DeepCopyInto(out *CouchbaseIndexTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CouchbaseIndexTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexTemplateList.
+func (in *CouchbaseIndexTemplateList) DeepCopy() *CouchbaseIndexTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseIndexTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexTemplateParameter) DeepCopyInto(out *CouchbaseIndexTemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexTemplateParameter.
+func (in *CouchbaseIndexTemplateParameter) DeepCopy() *CouchbaseIndexTemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexTemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseIndexTemplateSpec) DeepCopyInto(out *CouchbaseIndexTemplateSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]CouchbaseIndexTemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]GlobalSecondaryIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseIndexTemplateSpec.
+func (in *CouchbaseIndexTemplateSpec) DeepCopy() *CouchbaseIndexTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseIndexTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseQueryFunctionSet) DeepCopyInto(out *CouchbaseQueryFunctionSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseQueryFunctionSet.
+func (in *CouchbaseQueryFunctionSet) DeepCopy() *CouchbaseQueryFunctionSet {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseQueryFunctionSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseQueryFunctionSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseQueryFunctionSetList) DeepCopyInto(out *CouchbaseQueryFunctionSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CouchbaseQueryFunctionSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseQueryFunctionSetList.
+func (in *CouchbaseQueryFunctionSetList) DeepCopy() *CouchbaseQueryFunctionSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseQueryFunctionSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseQueryFunctionSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseQueryFunctionSetSpec) DeepCopyInto(out *CouchbaseQueryFunctionSetSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]QueryFunctionScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseQueryFunctionSetSpec.
+func (in *CouchbaseQueryFunctionSetSpec) DeepCopy() *CouchbaseQueryFunctionSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseQueryFunctionSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseQueryFunctionSetStatus) DeepCopyInto(out *CouchbaseQueryFunctionSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Libraries != nil {
+		in, out := &in.Libraries, &out.Libraries
+		*out = make([]AppliedQueryFunction, len(*in))
+		copy(*out, *in)
+	}
+	if in.Functions != nil {
+		in, out := &in.Functions, &out.Functions
+		*out = make([]AppliedQueryFunction, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseQueryFunctionSetStatus.
+func (in *CouchbaseQueryFunctionSetStatus) DeepCopy() *CouchbaseQueryFunctionSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseQueryFunctionSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseScope) DeepCopyInto(out *CouchbaseScope) {
+	*out = *in
+	if in.Collections != nil {
+		in, out := &in.Collections, &out.Collections
+		*out = make([]CouchbaseCollection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseScope.
+func (in *CouchbaseScope) DeepCopy() *CouchbaseScope {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseSearchIndexSet) DeepCopyInto(out *CouchbaseSearchIndexSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseSearchIndexSet.
+func (in *CouchbaseSearchIndexSet) DeepCopy() *CouchbaseSearchIndexSet {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseSearchIndexSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseSearchIndexSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseSearchIndexSetList) DeepCopyInto(out *CouchbaseSearchIndexSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CouchbaseSearchIndexSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseSearchIndexSetList.
+func (in *CouchbaseSearchIndexSetList) DeepCopy() *CouchbaseSearchIndexSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseSearchIndexSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CouchbaseSearchIndexSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseSearchIndexSetSpec) DeepCopyInto(out *CouchbaseSearchIndexSetSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]SearchIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseSearchIndexSetSpec.
+func (in *CouchbaseSearchIndexSetSpec) DeepCopy() *CouchbaseSearchIndexSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseSearchIndexSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CouchbaseSearchIndexSetStatus) DeepCopyInto(out *CouchbaseSearchIndexSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]SearchIndexStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.IndexCount != nil {
+		in, out := &in.IndexCount, &out.IndexCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CouchbaseSearchIndexSetStatus.
+func (in *CouchbaseSearchIndexSetStatus) DeepCopy() *CouchbaseSearchIndexSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CouchbaseSearchIndexSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventingBucketBinding) DeepCopyInto(out *EventingBucketBinding) {
+	*out = *in
+	in.EventingKeyspace.DeepCopyInto(&out.EventingKeyspace)
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingBucketBinding.
+func (in *EventingBucketBinding) DeepCopy() *EventingBucketBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(EventingBucketBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventingConstantBinding) DeepCopyInto(out *EventingConstantBinding) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingConstantBinding.
+func (in *EventingConstantBinding) DeepCopy() *EventingConstantBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(EventingConstantBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventingFunctionSettings) DeepCopyInto(out *EventingFunctionSettings) {
+	*out = *in
+	if in.StreamBoundary != nil {
+		in, out := &in.StreamBoundary, &out.StreamBoundary
+		*out = new(string)
+		**out = **in
+	}
+	if in.WorkerCount != nil {
+		in, out := &in.WorkerCount, &out.WorkerCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExecutionTimeoutSeconds != nil {
+		in, out := &in.ExecutionTimeoutSeconds, &out.ExecutionTimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.LanguageCompatibility != nil {
+		in, out := &in.LanguageCompatibility, &out.LanguageCompatibility
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingFunctionSettings.
+func (in *EventingFunctionSettings) DeepCopy() *EventingFunctionSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(EventingFunctionSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventingKeyspace) DeepCopyInto(out *EventingKeyspace) {
+	*out = *in
+	if in.ScopeName != nil {
+		in, out := &in.ScopeName, &out.ScopeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.CollectionName != nil {
+		in, out := &in.CollectionName, &out.CollectionName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingKeyspace.
+func (in *EventingKeyspace) DeepCopy() *EventingKeyspace {
+	if in == nil {
+		return nil
+	}
+	out := new(EventingKeyspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventingURLBinding) DeepCopyInto(out *EventingURLBinding) {
+	*out = *in
+	if in.AllowCookies != nil {
+		in, out := &in.AllowCookies, &out.AllowCookies
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ValidateSSLCertificate != nil {
+		in, out := &in.ValidateSSLCertificate, &out.ValidateSSLCertificate
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingURLBinding.
+func (in *EventingURLBinding) DeepCopy() *EventingURLBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(EventingURLBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalSecondaryIndex) DeepCopyInto(out *GlobalSecondaryIndex) {
+	*out = *in
+	if in.ScopeName != nil {
+		in, out := &in.ScopeName, &out.ScopeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.CollectionName != nil {
+		in, out := &in.CollectionName, &out.CollectionName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ScopeNames != nil {
+		in, out := &in.ScopeNames, &out.ScopeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CollectionNames != nil {
+		in, out := &in.CollectionNames, &out.CollectionNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IndexKey != nil {
+		in, out := &in.IndexKey, &out.IndexKey
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]GlobalSecondaryIndexKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(string)
+		**out = **in
+	}
+	if in.NumReplicas != nil {
+		in, out := &in.NumReplicas, &out.NumReplicas
+		*out = new(int)
+		**out = **in
+	}
+	if in.RetainDeletedXAttr != nil {
+		in, out := &in.RetainDeletedXAttr, &out.RetainDeletedXAttr
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(GlobalSecondaryIndexPartition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DropProtection != nil {
+		in, out := &in.DropProtection, &out.DropProtection
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalSecondaryIndex.
+func (in *GlobalSecondaryIndex) DeepCopy() *GlobalSecondaryIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalSecondaryIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalSecondaryIndexArrayKey) DeepCopyInto(out *GlobalSecondaryIndexArrayKey) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(string)
+		**out = **in
+	}
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(string)
+		**out = **in
+	}
+	if in.FlattenKeys != nil {
+		in, out := &in.FlattenKeys, &out.FlattenKeys
+		*out = make([]GlobalSecondaryIndexFlattenKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalSecondaryIndexArrayKey.
+func (in *GlobalSecondaryIndexArrayKey) DeepCopy() *GlobalSecondaryIndexArrayKey {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalSecondaryIndexArrayKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalSecondaryIndexFlattenKey) DeepCopyInto(out *GlobalSecondaryIndexFlattenKey) {
+	*out = *in
+	if in.Direction != nil {
+		in, out := &in.Direction, &out.Direction
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalSecondaryIndexFlattenKey.
+func (in *GlobalSecondaryIndexFlattenKey) DeepCopy() *GlobalSecondaryIndexFlattenKey {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalSecondaryIndexFlattenKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalSecondaryIndexKey) DeepCopyInto(out *GlobalSecondaryIndexKey) {
+	*out = *in
+	if in.Expression != nil {
+		in, out := &in.Expression, &out.Expression
+		*out = new(string)
+		**out = **in
+	}
+	if in.Direction != nil {
+		in, out := &in.Direction, &out.Direction
+		*out = new(string)
+		**out = **in
+	}
+	if in.IncludeMissing != nil {
+		in, out := &in.IncludeMissing, &out.IncludeMissing
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Array != nil {
+		in, out := &in.Array, &out.Array
+		*out = new(GlobalSecondaryIndexArrayKey)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalSecondaryIndexKey.
+func (in *GlobalSecondaryIndexKey) DeepCopy() *GlobalSecondaryIndexKey {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalSecondaryIndexKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalSecondaryIndexPartition) DeepCopyInto(out *GlobalSecondaryIndexPartition) {
+	*out = *in
+	if in.Expressions != nil {
+		in, out := &in.Expressions, &out.Expressions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(string)
+		**out = **in
+	}
+	if in.NumPartitions != nil {
+		in, out := &in.NumPartitions, &out.NumPartitions
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalSecondaryIndexPartition.
+func (in *GlobalSecondaryIndexPartition) DeepCopy() *GlobalSecondaryIndexPartition {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalSecondaryIndexPartition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexSetAdvice) DeepCopyInto(out *IndexSetAdvice) {
+	*out = *in
+	in.LastAdvised.DeepCopyInto(&out.LastAdvised)
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make([]QueryAdvice, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecommendedIndices != nil {
+		in, out := &in.RecommendedIndices, &out.RecommendedIndices
+		*out = make([]GlobalSecondaryIndex, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexSetAdvice.
+func (in *IndexSetAdvice) DeepCopy() *IndexSetAdvice {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexSetAdvice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexUsage) DeepCopyInto(out *IndexUsage) {
+	*out = *in
+	if in.LastScanTime != nil {
+		in, out := &in.LastScanTime, &out.LastScanTime
+		*out = (*in).DeepCopy()
+	}
+	in.TrackedSince.DeepCopyInto(&out.TrackedSince)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexUsage.
+func (in *IndexUsage) DeepCopy() *IndexUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingIndexDrop) DeepCopyInto(out *PendingIndexDrop) {
+	*out = *in
+	in.DropAfter.DeepCopyInto(&out.DropAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingIndexDrop.
+func (in *PendingIndexDrop) DeepCopy() *PendingIndexDrop {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingIndexDrop)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryAdvice) DeepCopyInto(out *QueryAdvice) {
+	*out = *in
+	if in.UsedIndices != nil {
+		in, out := &in.UsedIndices, &out.UsedIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecommendedIndices != nil {
+		in, out := &in.RecommendedIndices, &out.RecommendedIndices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryAdvice.
+func (in *QueryAdvice) DeepCopy() *QueryAdvice {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryAdvice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryFunction) DeepCopyInto(out *QueryFunction) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Expression != nil {
+		in, out := &in.Expression, &out.Expression
+		*out = new(string)
+		**out = **in
+	}
+	if in.JavaScript != nil {
+		in, out := &in.JavaScript, &out.JavaScript
+		*out = new(QueryFunctionJavaScript)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFunction.
+func (in *QueryFunction) DeepCopy() *QueryFunction {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryFunction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryFunctionJavaScript) DeepCopyInto(out *QueryFunctionJavaScript) {
+	*out = *in
+	if in.FunctionName != nil {
+		in, out := &in.FunctionName, &out.FunctionName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFunctionJavaScript.
+func (in *QueryFunctionJavaScript) DeepCopy() *QueryFunctionJavaScript {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryFunctionJavaScript)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryFunctionScope) DeepCopyInto(out *QueryFunctionScope) {
+	*out = *in
+	if in.Libraries != nil {
+		in, out := &in.Libraries, &out.Libraries
+		*out = make([]QueryJavaScriptLibrary, len(*in))
+		copy(*out, *in)
+	}
+	if in.Functions != nil {
+		in, out := &in.Functions, &out.Functions
+		*out = make([]QueryFunction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryFunctionScope.
+func (in *QueryFunctionScope) DeepCopy() *QueryFunctionScope {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryFunctionScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryJavaScriptLibrary) DeepCopyInto(out *QueryJavaScriptLibrary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryJavaScriptLibrary.
+func (in *QueryJavaScriptLibrary) DeepCopy() *QueryJavaScriptLibrary {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryJavaScriptLibrary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchIndex) DeepCopyInto(out *SearchIndex) {
+	*out = *in
+	if in.ScopeName != nil {
+		in, out := &in.ScopeName, &out.ScopeName
+		*out = new(string)
+		**out = **in
+	}
+	if in.CollectionNames != nil {
+		in, out := &in.CollectionNames, &out.CollectionNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DocConfig != nil {
+		in, out := &in.DocConfig, &out.DocConfig
+		*out = new(SearchIndexDocConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]SearchIndexTypeMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultMapping != nil {
+		in, out := &in.DefaultMapping, &out.DefaultMapping
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultAnalyzer != nil {
+		in, out := &in.DefaultAnalyzer, &out.DefaultAnalyzer
+		*out = new(string)
+		**out = **in
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NumReplicas != nil {
+		in, out := &in.NumReplicas, &out.NumReplicas
+		*out = new(int)
+		**out = **in
+	}
+	if in.NumPartitions != nil {
+		in, out := &in.NumPartitions, &out.NumPartitions
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchIndex.
+func (in *SearchIndex) DeepCopy() *SearchIndex {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchIndex)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchIndexDocConfig) DeepCopyInto(out *SearchIndexDocConfig) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(string)
+		**out = **in
+	}
+	if in.TypeField != nil {
+		in, out := &in.TypeField, &out.TypeField
+		*out = new(string)
+		**out = **in
+	}
+	if in.DocIDPrefixDelimiter != nil {
+		in, out := &in.DocIDPrefixDelimiter, &out.DocIDPrefixDelimiter
+		*out = new(string)
+		**out = **in
+	}
+	if in.DocIDRegexp != nil {
+		in, out := &in.DocIDRegexp, &out.DocIDRegexp
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchIndexDocConfig.
+func (in *SearchIndexDocConfig) DeepCopy() *SearchIndexDocConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchIndexDocConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchIndexStatus) DeepCopyInto(out *SearchIndexStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchIndexStatus.
+func (in *SearchIndexStatus) DeepCopy() *SearchIndexStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchIndexStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SearchIndexTypeMapping) DeepCopyInto(out *SearchIndexTypeMapping) {
+	*out = *in
+	if in.Mapping != nil {
+		in, out := &in.Mapping, &out.Mapping
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SearchIndexTypeMapping.
+func (in *SearchIndexTypeMapping) DeepCopy() *SearchIndexTypeMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(SearchIndexTypeMapping)
 	in.DeepCopyInto(out)
 	return out
 }
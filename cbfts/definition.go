@@ -0,0 +1,203 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbfts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+const (
+	defaultDocConfigMode = "scope.collection.type_field"
+	defaultTypeField     = "type"
+	defaultAnalyzer      = "standard"
+)
+
+// Builds the Search REST API definition of a full text search index on a bucket
+func BuildIndexDefinition(bucketName string, index couchbasev1beta1.SearchIndex) (*cbrest.SearchIndexDefinition, error) {
+	mode := defaultDocConfigMode
+	if index.DocConfig != nil {
+		mode = stringOrDefault(index.DocConfig.Mode, defaultDocConfigMode)
+	}
+
+	typePrefix := ""
+	if index.ScopeName != nil {
+		if !strings.HasPrefix(mode, "scope.collection.") {
+			return nil, fmt.Errorf("scopeName requires a scope.collection mode, found %s", mode)
+		}
+
+		typePrefix = *index.ScopeName + "."
+	} else if len(index.CollectionNames) > 0 {
+		return nil, errors.New("collectionNames requires scopeName")
+	}
+
+	types := map[string]interface{}{}
+	for _, typeMapping := range index.Types {
+		mapping, err := getDocumentMapping(typeMapping.Mapping, true)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", typeMapping.Name, err)
+		}
+
+		types[typePrefix+typeMapping.Name] = mapping
+	}
+
+	// Source collections without a type mapping are indexed dynamically
+	for _, collectionName := range index.CollectionNames {
+		if !hasCollectionMapping(index.Types, collectionName) {
+			types[typePrefix+collectionName] = map[string]interface{}{
+				"enabled": true,
+				"dynamic": true,
+			}
+		}
+	}
+
+	defaultMapping, err := getDocumentMapping(index.DefaultMapping, len(types) == 0)
+	if err != nil {
+		return nil, fmt.Errorf("default mapping: %w", err)
+	}
+
+	mapping := map[string]interface{}{
+		"default_analyzer":        stringOrDefault(index.DefaultAnalyzer, defaultAnalyzer),
+		"default_datetime_parser": "dateTimeOptional",
+		"default_field":           "_all",
+		"default_mapping":         defaultMapping,
+		"default_type":            "_default",
+		"index_dynamic":           true,
+		"store_dynamic":           false,
+		"type_field":              "_type",
+	}
+	if len(types) > 0 {
+		mapping["types"] = types
+	}
+	if index.Analysis != nil {
+		analysis, err := unmarshalObject(index.Analysis)
+		if err != nil {
+			return nil, fmt.Errorf("analysis: %w", err)
+		}
+
+		mapping["analysis"] = analysis
+	}
+
+	docConfig := map[string]interface{}{
+		"mode":       mode,
+		"type_field": defaultTypeField,
+	}
+	if index.DocConfig != nil {
+		docConfig["type_field"] = stringOrDefault(index.DocConfig.TypeField, defaultTypeField)
+
+		if index.DocConfig.DocIDPrefixDelimiter != nil {
+			docConfig["docid_prefix_delim"] = *index.DocConfig.DocIDPrefixDelimiter
+		}
+		if index.DocConfig.DocIDRegexp != nil {
+			docConfig["docid_regexp"] = *index.DocConfig.DocIDRegexp
+		}
+	}
+
+	planParams := map[string]interface{}{}
+	if index.NumReplicas != nil {
+		planParams["numReplicas"] = *index.NumReplicas
+	}
+	if index.NumPartitions != nil {
+		planParams["indexPartitions"] = *index.NumPartitions
+	}
+
+	return &cbrest.SearchIndexDefinition{
+		Type:       "fulltext-index",
+		Name:       index.Name,
+		SourceType: "gocbcore",
+		SourceName: bucketName,
+		PlanParams: planParams,
+		Params: map[string]interface{}{
+			"doc_config": docConfig,
+			"mapping":    mapping,
+			"store": map[string]interface{}{
+				"indexType": "scorch",
+			},
+		},
+	}, nil
+}
+
+// Gets a hash of an index definition, excluding the UUID, which changes whenever the definition changes
+func GetDefinitionHash(definition *cbrest.SearchIndexDefinition) string {
+	withoutUUID := *definition
+	withoutUUID.UUID = ""
+
+	// Maps are marshaled with sorted keys, so the result is stable
+	bytes, _ := json.Marshal(withoutUUID)
+
+	hash := sha256.Sum256(bytes)
+	return hex.EncodeToString(hash[:])
+}
+
+// Returns true if a type mapping selects the collection, named "collection" or "collection.type"
+func hasCollectionMapping(typeMappings []couchbasev1beta1.SearchIndexTypeMapping, collectionName string) bool {
+	for _, typeMapping := range typeMappings {
+		if typeMapping.Name == collectionName || strings.HasPrefix(typeMapping.Name, collectionName+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Gets a document mapping, defaulting to a dynamic mapping which is enabled if not otherwise specified
+func getDocumentMapping(raw *runtime.RawExtension, enabled bool) (map[string]interface{}, error) {
+	mapping := map[string]interface{}{}
+	if raw != nil {
+		var err error
+		if mapping, err = unmarshalObject(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := mapping["enabled"]; !ok {
+		mapping["enabled"] = enabled
+	}
+	if _, ok := mapping["dynamic"]; !ok {
+		mapping["dynamic"] = true
+	}
+
+	return mapping, nil
+}
+
+func unmarshalObject(raw *runtime.RawExtension) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if len(raw.Raw) > 0 {
+		if err := json.Unmarshal(raw.Raw, &result); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func stringOrDefault(value *string, defaultValue string) string {
+	if value != nil {
+		return *value
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,176 @@
+package cbfts
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("BuildIndexDefinition", func() {
+
+	It("should map types", func() {
+		// Arrange
+
+		numReplicas := 1
+		index := couchbasev1beta1.SearchIndex{
+			Name: "hotels",
+			Types: []couchbasev1beta1.SearchIndexTypeMapping{
+				{
+					Name:    "inventory.hotel",
+					Mapping: &runtime.RawExtension{Raw: []byte(`{"dynamic":false,"properties":{"name":{"enabled":true}}}`)},
+				},
+			},
+			NumReplicas: &numReplicas,
+		}
+
+		// Act
+
+		result, err := BuildIndexDefinition("travel", index)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.Name).To(Equal("hotels"))
+		Expect(result.SourceName).To(Equal("travel"))
+		Expect(result.PlanParams).To(Equal(map[string]interface{}{"numReplicas": 1}))
+
+		mapping := result.Params["mapping"].(map[string]interface{})
+		Expect(mapping["default_mapping"]).To(Equal(map[string]interface{}{"enabled": false, "dynamic": true}))
+		Expect(mapping["types"]).To(Equal(map[string]interface{}{
+			"inventory.hotel": map[string]interface{}{
+				"enabled":    true,
+				"dynamic":    false,
+				"properties": map[string]interface{}{"name": map[string]interface{}{"enabled": true}},
+			},
+		}))
+		Expect(result.Params["doc_config"]).To(Equal(map[string]interface{}{
+			"mode":       "scope.collection.type_field",
+			"type_field": "type",
+		}))
+	})
+
+	It("should enable the default mapping without types", func() {
+		// Act
+
+		result, err := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "all"})
+
+		// Assert
+
+		Expect(err).To(BeNil())
+
+		mapping := result.Params["mapping"].(map[string]interface{})
+		Expect(mapping["default_mapping"]).To(Equal(map[string]interface{}{"enabled": true, "dynamic": true}))
+		Expect(mapping).NotTo(HaveKey("types"))
+	})
+
+	It("should map source collections within the scope", func() {
+		// Arrange
+
+		index := couchbasev1beta1.SearchIndex{
+			Name:            "inventory",
+			ScopeName:       pointer.StringPtr("inventory"),
+			CollectionNames: []string{"hotel", "airline"},
+			Types: []couchbasev1beta1.SearchIndexTypeMapping{
+				{Name: "hotel.lodging"},
+			},
+		}
+
+		// Act
+
+		result, err := BuildIndexDefinition("travel", index)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+
+		mapping := result.Params["mapping"].(map[string]interface{})
+		Expect(mapping["types"]).To(Equal(map[string]interface{}{
+			"inventory.hotel.lodging": map[string]interface{}{"enabled": true, "dynamic": true},
+			"inventory.airline":       map[string]interface{}{"enabled": true, "dynamic": true},
+		}))
+		Expect(mapping["default_mapping"]).To(Equal(map[string]interface{}{"enabled": false, "dynamic": true}))
+	})
+
+	It("should require a scope for source collections", func() {
+		// Act
+
+		_, err := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "all", CollectionNames: []string{"hotel"}})
+
+		// Assert
+
+		Expect(err).To(MatchError("collectionNames requires scopeName"))
+	})
+
+	It("should require a scope.collection mode for a source scope", func() {
+		// Arrange
+
+		index := couchbasev1beta1.SearchIndex{
+			Name:      "all",
+			ScopeName: pointer.StringPtr("inventory"),
+			DocConfig: &couchbasev1beta1.SearchIndexDocConfig{Mode: pointer.StringPtr("type_field")},
+		}
+
+		// Act
+
+		_, err := BuildIndexDefinition("travel", index)
+
+		// Assert
+
+		Expect(err).To(MatchError("scopeName requires a scope.collection mode, found type_field"))
+	})
+
+	It("should error on invalid mappings", func() {
+		// Arrange
+
+		index := couchbasev1beta1.SearchIndex{
+			Name:     "hotels",
+			Analysis: &runtime.RawExtension{Raw: []byte(`[]`)},
+		}
+
+		// Act
+
+		_, err := BuildIndexDefinition("travel", index)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("GetDefinitionHash", func() {
+
+	It("should ignore the UUID", func() {
+		// Arrange
+
+		definition, _ := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "all"})
+		hash := GetDefinitionHash(definition)
+
+		// Act
+
+		definition.UUID = "abc"
+		result := GetDefinitionHash(definition)
+
+		// Assert
+
+		Expect(result).To(Equal(hash))
+	})
+
+	It("should change with the definition", func() {
+		// Arrange
+
+		definition, _ := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "all"})
+		hash := GetDefinitionHash(definition)
+
+		// Act
+
+		definition, _ = BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "all", DefaultAnalyzer: pointer.StringPtr("keyword")})
+		result := GetDefinitionHash(definition)
+
+		// Assert
+
+		Expect(result).NotTo(Equal(hash))
+	})
+})
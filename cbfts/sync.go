@@ -0,0 +1,142 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbfts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+// Command line argument which runs the operator as a search index sync Job
+const SyncCommand = "sync-search-indices"
+
+// Client for the Search REST API, implemented by cbrest.Client
+type SearchClient interface {
+	GetSearchIndex(ctx context.Context, name string) (*cbrest.SearchIndexDefinition, error)
+	PutSearchIndex(ctx context.Context, definition *cbrest.SearchIndexDefinition) (string, error)
+	DeleteSearchIndex(ctx context.Context, name string) error
+}
+
+// Request for a search index sync Job, holding the desired definitions and the indices tracked by the index set
+type SyncRequest struct {
+	Definitions []*cbrest.SearchIndexDefinition      `json:"definitions"`
+	Tracked     []couchbasev1beta1.SearchIndexStatus `json:"tracked,omitempty"`
+}
+
+// Creates or updates each index which is new, has a changed definition, or was changed externally, then drops
+// tracked indices which are no longer defined. Existing indices which aren't tracked are updated, adopting them.
+func Sync(ctx context.Context, client SearchClient, request SyncRequest, logger logr.Logger) error {
+	tracked := map[string]couchbasev1beta1.SearchIndexStatus{}
+	for _, v := range request.Tracked {
+		tracked[v.Name] = v
+	}
+
+	defined := map[string]bool{}
+	for _, definition := range request.Definitions {
+		defined[definition.Name] = true
+		hash := GetDefinitionHash(definition)
+
+		existing, err := client.GetSearchIndex(ctx, definition.Name)
+		if err != nil {
+			return fmt.Errorf("unable to get index %s: %w", definition.Name, err)
+		}
+
+		previous, ok := tracked[definition.Name]
+		if existing != nil && ok && previous.DefinitionHash == hash && (previous.UUID == "" || previous.UUID == existing.UUID) {
+			continue
+		}
+
+		if existing != nil {
+			// Updates must include the UUID of the definition being replaced
+			definition.UUID = existing.UUID
+		}
+
+		if _, err := client.PutSearchIndex(ctx, definition); err != nil {
+			return fmt.Errorf("unable to apply index %s: %w", definition.Name, err)
+		}
+
+		logger.Info("Applied search index", "index", definition.Name)
+	}
+
+	for _, v := range request.Tracked {
+		if defined[v.Name] {
+			continue
+		}
+
+		if err := client.DeleteSearchIndex(ctx, v.Name); err != nil {
+			return fmt.Errorf("unable to drop index %s: %w", v.Name, err)
+		}
+
+		logger.Info("Dropped search index", "index", v.Name)
+	}
+
+	return nil
+}
+
+// Updates the tracked indices once a sync Job finishes, using the definition hashes applied by the Job and the UUIDs
+// of the indices which now exist. After a successful sync every definition was applied and every removed index was
+// dropped. After a failed sync the progress is unknown, so indices with a new UUID are tracked without a hash to be
+// applied again, and removed indices are tracked until they no longer exist.
+func UpdateTrackedIndices(tracked []couchbasev1beta1.SearchIndexStatus, hashes map[string]string, uuids map[string]string,
+	succeeded bool) []couchbasev1beta1.SearchIndexStatus {
+
+	previous := map[string]couchbasev1beta1.SearchIndexStatus{}
+	for _, v := range tracked {
+		previous[v.Name] = v
+	}
+
+	result := []couchbasev1beta1.SearchIndexStatus{}
+	for name, hash := range hashes {
+		uuid, exists := uuids[name]
+		if !exists {
+			// Recreated by the next sync
+			continue
+		}
+
+		if succeeded {
+			result = append(result, couchbasev1beta1.SearchIndexStatus{Name: name, DefinitionHash: hash, UUID: uuid})
+		} else if v, ok := previous[name]; ok && v.UUID != "" && v.UUID == uuid {
+			result = append(result, v)
+		} else {
+			result = append(result, couchbasev1beta1.SearchIndexStatus{Name: name, UUID: uuid})
+		}
+	}
+
+	if !succeeded {
+		for _, v := range tracked {
+			if _, defined := hashes[v.Name]; defined {
+				continue
+			}
+
+			if _, exists := uuids[v.Name]; exists {
+				result = append(result, v)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
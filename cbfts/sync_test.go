@@ -0,0 +1,125 @@
+package cbfts
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+type fakeSearchClient struct {
+	indices map[string]*cbrest.SearchIndexDefinition
+	puts    []string
+	deletes []string
+}
+
+func (client *fakeSearchClient) GetSearchIndex(ctx context.Context, name string) (*cbrest.SearchIndexDefinition, error) {
+	return client.indices[name], nil
+}
+
+func (client *fakeSearchClient) PutSearchIndex(ctx context.Context, definition *cbrest.SearchIndexDefinition) (string, error) {
+	client.puts = append(client.puts, definition.Name+":"+definition.UUID)
+	return "new", nil
+}
+
+func (client *fakeSearchClient) DeleteSearchIndex(ctx context.Context, name string) error {
+	client.deletes = append(client.deletes, name)
+	return nil
+}
+
+var _ = Describe("Sync", func() {
+
+	It("should apply changed and adopted indices and drop removed indices", func() {
+		// Arrange
+
+		unchanged, _ := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "unchanged"})
+		changed, _ := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "changed"})
+		external, _ := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "external"})
+		adopted, _ := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "adopted"})
+		created, _ := BuildIndexDefinition("travel", couchbasev1beta1.SearchIndex{Name: "created"})
+
+		client := &fakeSearchClient{
+			indices: map[string]*cbrest.SearchIndexDefinition{
+				"unchanged": {Name: "unchanged", UUID: "u1"},
+				"changed":   {Name: "changed", UUID: "c1"},
+				"external":  {Name: "external", UUID: "e2"},
+				"adopted":   {Name: "adopted", UUID: "a1"},
+			},
+		}
+
+		request := SyncRequest{
+			Definitions: []*cbrest.SearchIndexDefinition{unchanged, changed, external, adopted, created},
+			Tracked: []couchbasev1beta1.SearchIndexStatus{
+				{Name: "unchanged", DefinitionHash: GetDefinitionHash(unchanged), UUID: "u1"},
+				{Name: "changed", DefinitionHash: "old", UUID: "c1"},
+				{Name: "external", DefinitionHash: GetDefinitionHash(external), UUID: "e1"},
+				{Name: "removed", DefinitionHash: "old", UUID: "r1"},
+			},
+		}
+
+		// Act
+
+		err := Sync(context.Background(), client, request, logr.Discard())
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(client.puts).To(Equal([]string{"changed:c1", "external:e2", "adopted:a1", "created:"}))
+		Expect(client.deletes).To(Equal([]string{"removed"}))
+	})
+})
+
+var _ = Describe("UpdateTrackedIndices", func() {
+
+	It("should track every definition after a successful sync", func() {
+		// Arrange
+
+		tracked := []couchbasev1beta1.SearchIndexStatus{
+			{Name: "changed", DefinitionHash: "old", UUID: "c1"},
+			{Name: "removed", DefinitionHash: "old", UUID: "r1"},
+		}
+		hashes := map[string]string{"changed": "new", "created": "new"}
+		uuids := map[string]string{"changed": "c2", "created": "n1"}
+
+		// Act
+
+		result := UpdateTrackedIndices(tracked, hashes, uuids, true)
+
+		// Assert
+
+		Expect(result).To(Equal([]couchbasev1beta1.SearchIndexStatus{
+			{Name: "changed", DefinitionHash: "new", UUID: "c2"},
+			{Name: "created", DefinitionHash: "new", UUID: "n1"},
+		}))
+	})
+
+	It("should reapply indices with unknown progress after a failed sync", func() {
+		// Arrange
+
+		tracked := []couchbasev1beta1.SearchIndexStatus{
+			{Name: "applied", DefinitionHash: "old", UUID: "a1"},
+			{Name: "pending", DefinitionHash: "old", UUID: "p1"},
+			{Name: "removed", DefinitionHash: "old", UUID: "r1"},
+			{Name: "dropped", DefinitionHash: "old", UUID: "d1"},
+		}
+		hashes := map[string]string{"applied": "new", "pending": "new", "created": "new", "missing": "new"}
+		uuids := map[string]string{"applied": "a2", "pending": "p1", "created": "n1", "removed": "r1"}
+
+		// Act
+
+		result := UpdateTrackedIndices(tracked, hashes, uuids, false)
+
+		// Assert
+
+		Expect(result).To(Equal([]couchbasev1beta1.SearchIndexStatus{
+			{Name: "applied", UUID: "a2"},
+			{Name: "created", UUID: "n1"},
+			{Name: "pending", DefinitionHash: "old", UUID: "p1"},
+			{Name: "removed", DefinitionHash: "old", UUID: "r1"},
+		}))
+	})
+})
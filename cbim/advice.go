@@ -0,0 +1,125 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"sort"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Advice from the Index Advisor for a single workload query
+type QueryAdviceResult struct {
+	// Name of the query
+	Name string
+	// CREATE INDEX statements for the existing indices the query would use
+	CurrentIndexes []string
+	// CREATE INDEX statements for the recommended indices
+	RecommendedIndexes []string
+	// Error advising the query, if any
+	Err error
+}
+
+// Summarizes the advice for the workload queries of an index set. Existing indices are only reported if they are
+// managed by the index set, and recommendations are only reported for the index set's bucket. The Index Advisor names
+// recommendations itself, so recommended indices are matched to the index set by normalized definition rather than
+// by name. Those which are already in the index set are reported as used, and the remainder are returned as global
+// secondary indices which may be added to the index set.
+func GetIndexSetAdvice(indexSet *couchbasev1beta1.CouchbaseIndexSet, indices []couchbasev1beta1.GlobalSecondaryIndex,
+	results []QueryAdviceResult) ([]couchbasev1beta1.QueryAdvice, []couchbasev1beta1.GlobalSecondaryIndex) {
+
+	managedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+	for _, index := range indexSet.Status.Indices {
+		if identifier, err := ParseIndexIdentifierString(index); err == nil {
+			managedIndexes[identifier] = true
+		}
+	}
+
+	queries := make([]couchbasev1beta1.QueryAdvice, 0, len(results))
+	recommended := map[GlobalSecondaryIndexIdentifier]couchbasev1beta1.GlobalSecondaryIndex{}
+	for _, result := range results {
+		advice := couchbasev1beta1.QueryAdvice{Name: result.Name}
+
+		if result.Err != nil {
+			advice.Error = result.Err.Error()
+			queries = append(queries, advice)
+			continue
+		}
+
+		for _, gsi := range parseAdvisedIndexes(indexSet.Spec.BucketName, result.CurrentIndexes) {
+			if identifier := GetIndexIdentifier(gsi); managedIndexes[identifier] {
+				advice.UsedIndices = append(advice.UsedIndices, identifier.ToString())
+			}
+		}
+
+		for _, gsi := range parseAdvisedIndexes(indexSet.Spec.BucketName, result.RecommendedIndexes) {
+			if defined, ok := findIndexDefinition(indices, gsi); ok {
+				advice.UsedIndices = append(advice.UsedIndices, GetIndexIdentifier(defined).ToString())
+				continue
+			}
+
+			identifier := GetIndexIdentifier(gsi)
+			advice.RecommendedIndices = append(advice.RecommendedIndices, identifier.ToString())
+			recommended[identifier] = gsi
+		}
+
+		queries = append(queries, advice)
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Name < queries[j].Name
+	})
+
+	recommendedIndices := make([]couchbasev1beta1.GlobalSecondaryIndex, 0, len(recommended))
+	for _, gsi := range recommended {
+		recommendedIndices = append(recommendedIndices, gsi)
+	}
+
+	sort.Slice(recommendedIndices, func(i, j int) bool {
+		return GetIndexIdentifier(recommendedIndices[i]).ToString() < GetIndexIdentifier(recommendedIndices[j]).ToString()
+	})
+
+	return queries, recommendedIndices
+}
+
+// Finds an index with the same normalized definition as the given index, regardless of name
+func findIndexDefinition(indices []couchbasev1beta1.GlobalSecondaryIndex,
+	gsi couchbasev1beta1.GlobalSecondaryIndex) (couchbasev1beta1.GlobalSecondaryIndex, bool) {
+
+	for _, defined := range indices {
+		if IndexDefinitionsEqual(defined, gsi) {
+			return defined, true
+		}
+	}
+
+	return couchbasev1beta1.GlobalSecondaryIndex{}, false
+}
+
+// Parses CREATE INDEX statements returned by the Index Advisor, ignoring indices on other buckets and statements
+// which can't be represented as a global secondary index, such as primary indices
+func parseAdvisedIndexes(bucketName string, statements []string) []couchbasev1beta1.GlobalSecondaryIndex {
+	result := []couchbasev1beta1.GlobalSecondaryIndex{}
+	for _, statement := range statements {
+		for _, imported := range ImportStatements(statement).Indices {
+			if imported.BucketName == bucketName {
+				result = append(result, imported.Index)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,128 @@
+package cbim
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("GetIndexSetAdvice", func() {
+
+	It("should summarize used and recommended indices", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				BucketName: "travel-sample",
+			},
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"def_type", "inventory.hotel.def_city"},
+			},
+		}
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "def_type", IndexKey: []string{"type"}},
+			{Name: "adv_name", IndexKey: []string{"name"}},
+		}
+
+		results := []QueryAdviceResult{
+			{
+				Name: "hotels-by-city",
+				CurrentIndexes: []string{
+					"CREATE INDEX def_city ON `travel-sample`.`inventory`.`hotel`(`city`)",
+					"CREATE INDEX unmanaged ON `travel-sample`.`inventory`.`hotel`(`country`)",
+				},
+				RecommendedIndexes: []string{
+					"CREATE INDEX adv_city_free ON `travel-sample`.`inventory`.`hotel`(`city`,`free_breakfast`)",
+					"CREATE INDEX adv_other ON `beer-sample`(`abv`)",
+				},
+			},
+			{
+				Name:               "by-name",
+				RecommendedIndexes: []string{"CREATE INDEX adv_name ON `travel-sample`(`name`)"},
+			},
+			{
+				Name: "broken",
+				Err:  errors.New("syntax error"),
+			},
+		}
+
+		// Act
+
+		queries, recommended := GetIndexSetAdvice(&indexSet, indices, results)
+
+		// Assert
+
+		Expect(queries).To(Equal([]couchbasev1beta1.QueryAdvice{
+			{Name: "broken", Error: "syntax error"},
+			{Name: "by-name", UsedIndices: []string{"adv_name"}},
+			{
+				Name:               "hotels-by-city",
+				UsedIndices:        []string{"inventory.hotel.def_city"},
+				RecommendedIndices: []string{"inventory.hotel.adv_city_free"},
+			},
+		}))
+		Expect(recommended).To(Equal([]couchbasev1beta1.GlobalSecondaryIndex{
+			{
+				Name:           "adv_city_free",
+				ScopeName:      pointer.StringPtr("inventory"),
+				CollectionName: pointer.StringPtr("hotel"),
+				IndexKey:       []string{"`city`", "`free_breakfast`"},
+			},
+		}))
+	})
+
+	It("should match recommendations by definition rather than name", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				BucketName: "travel-sample",
+			},
+		}
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{
+				Name:           "def_city",
+				ScopeName:      pointer.StringPtr("inventory"),
+				CollectionName: pointer.StringPtr("hotel"),
+				IndexKey:       []string{"city"},
+				Condition:      pointer.StringPtr("type = 'hotel'"),
+			},
+			{
+				Name:           "adv_country",
+				ScopeName:      pointer.StringPtr("inventory"),
+				CollectionName: pointer.StringPtr("hotel"),
+				IndexKey:       []string{"name"},
+			},
+		}
+
+		results := []QueryAdviceResult{
+			{
+				Name: "hotels",
+				RecommendedIndexes: []string{
+					"CREATE INDEX adv_city_type ON `travel-sample`.`inventory`.`hotel`(`city`) WHERE `type` = \"hotel\"",
+					"CREATE INDEX adv_country ON `travel-sample`.`inventory`.`hotel`(`country`)",
+				},
+			},
+		}
+
+		// Act
+
+		queries, recommended := GetIndexSetAdvice(&indexSet, indices, results)
+
+		// Assert
+
+		Expect(queries).To(Equal([]couchbasev1beta1.QueryAdvice{
+			{
+				Name:               "hotels",
+				UsedIndices:        []string{"inventory.hotel.def_city"},
+				RecommendedIndices: []string{"inventory.hotel.adv_country"},
+			},
+		}))
+		Expect(recommended).To(HaveLen(1))
+		Expect(recommended[0].IndexKey).To(Equal([]string{"`country`"}))
+	})
+})
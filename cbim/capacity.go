@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"regexp"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+// Default size in bytes of an index item, used when there are no existing indices on the bucket to measure
+const DefaultIndexItemSize int64 = 256
+
+// Matches array index keys, such as "DISTINCT ARRAY v FOR v IN schedule END" or "ALL categories"
+var arrayIndexKeyRegex = regexp.MustCompile(`(?i)^\s*(DISTINCT|ALL)\b`)
+
+// Estimated footprint of a set of indices in bytes
+type IndexFootprint struct {
+	Memory int64
+	Disk   int64
+}
+
+// Gets the indices which haven't been created by a previous sync
+func GetNewIndexes(indexSet *couchbasev1beta1.CouchbaseIndexSet, indices []couchbasev1beta1.GlobalSecondaryIndex) []couchbasev1beta1.GlobalSecondaryIndex {
+	appliedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+	for _, index := range indexSet.Status.Indices {
+		if identifier, err := ParseIndexIdentifierString(index); err == nil {
+			appliedIndexes[identifier] = true
+		}
+	}
+
+	result := []couchbasev1beta1.GlobalSecondaryIndex{}
+	for _, gsi := range indices {
+		if !appliedIndexes[GetIndexIdentifier(gsi)] {
+			result = append(result, gsi)
+		}
+	}
+
+	return result
+}
+
+// Returns true if the index has an array key, which indexes an item for each array element
+func IsArrayIndex(gsi couchbasev1beta1.GlobalSecondaryIndex) bool {
+	for _, key := range gsi.Keys {
+		if key.Array != nil {
+			return true
+		}
+	}
+
+	for _, key := range gsi.IndexKey {
+		if arrayIndexKeyRegex.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Estimates the memory and disk footprint of indices, including replicas. Each index is assumed to contain an item
+// for every document in its collection, which overestimates filtered indices, or arrayItemsPerDocument items for
+// array indices. Items are sized using the average item size of the existing indices on the bucket. Memory assumes
+// the index is fully resident.
+func EstimateIndexFootprint(indices []couchbasev1beta1.GlobalSecondaryIndex, collectionItems map[cbrest.CollectionKey]int64,
+	existing map[cbrest.IndexKey]*cbrest.IndexStats, arrayItemsPerDocument int64) IndexFootprint {
+
+	var items, dataSize, diskSize int64
+	for _, size := range existing {
+		if size.ItemsCount > 0 {
+			items += size.ItemsCount
+			dataSize += size.DataSize
+			diskSize += size.DiskSize
+		}
+	}
+
+	memoryPerItem, diskPerItem := DefaultIndexItemSize, DefaultIndexItemSize
+	if items > 0 && dataSize > 0 {
+		memoryPerItem = dataSize / items
+	}
+	if items > 0 && diskSize > 0 {
+		diskPerItem = diskSize / items
+	}
+
+	result := IndexFootprint{}
+	for _, gsi := range indices {
+		identifier := GetIndexIdentifier(gsi)
+		count := collectionItems[cbrest.CollectionKey{
+			ScopeName:      identifier.ScopeName,
+			CollectionName: identifier.CollectionName,
+		}]
+
+		if IsArrayIndex(gsi) {
+			count *= arrayItemsPerDocument
+		}
+
+		copies := int64(1)
+		if gsi.NumReplicas != nil {
+			copies += int64(*gsi.NumReplicas)
+		}
+
+		result.Memory += count * memoryPerItem * copies
+		result.Disk += count * diskPerItem * copies
+	}
+
+	return result
+}
+
+// Returns true if adding to the current usage would exceed the maximum percentage of the total. A total of zero is
+// treated as unknown and never exceeded.
+func ExceedsCapacity(used int64, additional int64, total int64, maxPercent int32) bool {
+	if total <= 0 {
+		return false
+	}
+
+	return (used+additional)*100 > total*int64(maxPercent)
+}
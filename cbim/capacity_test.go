@@ -0,0 +1,142 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+var _ = Describe("GetNewIndexes", func() {
+
+	It("should return indices which haven't been synced", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"existing", "inventory.airline.scoped"},
+			},
+		}
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "existing"},
+			{Name: "scoped", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("airline")},
+			{Name: "scoped", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("route")},
+		}
+
+		// Act
+
+		result := GetNewIndexes(&indexSet, indices)
+
+		// Assert
+
+		Expect(result).To(Equal(indices[2:]))
+	})
+})
+
+var _ = Describe("EstimateIndexFootprint", func() {
+
+	indices := []couchbasev1beta1.GlobalSecondaryIndex{
+		{Name: "def_type"},
+		{Name: "def_city", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("hotel"), NumReplicas: intPtr(1)},
+	}
+	collectionItems := map[cbrest.CollectionKey]int64{
+		{ScopeName: "_default", CollectionName: "_default"}: 1000,
+		{ScopeName: "inventory", CollectionName: "hotel"}:   500,
+	}
+
+	It("should size items using existing indices", func() {
+		// Arrange
+
+		existing := map[cbrest.IndexKey]*cbrest.IndexStats{
+			{Name: "a"}: {ItemsCount: 100, DataSize: 5000, DiskSize: 2000},
+			{Name: "b"}: {ItemsCount: 300, DataSize: 15000, DiskSize: 6000},
+			{Name: "c"}: {ItemsCount: 0, DataSize: 1000, DiskSize: 1000},
+		}
+
+		// Act
+
+		result := EstimateIndexFootprint(indices, collectionItems, existing, 10)
+
+		// Assert
+
+		Expect(result).To(Equal(IndexFootprint{
+			Memory: 1000*50 + 500*50*2,
+			Disk:   1000*20 + 500*20*2,
+		}))
+	})
+
+	It("should use the default item size without existing indices", func() {
+		// Act
+
+		result := EstimateIndexFootprint(indices[:1], collectionItems, nil, 10)
+
+		// Assert
+
+		Expect(result).To(Equal(IndexFootprint{
+			Memory: 1000 * DefaultIndexItemSize,
+			Disk:   1000 * DefaultIndexItemSize,
+		}))
+	})
+
+	It("should count multiple items per document for array indices", func() {
+		// Arrange
+
+		arrayIndices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "def_schedule", IndexKey: []string{"DISTINCT ARRAY s.day FOR s IN schedule END"}},
+			{Name: "def_tags", Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{Array: &couchbasev1beta1.GlobalSecondaryIndexArrayKey{Variable: "t", In: "tags"}},
+			}},
+		}
+
+		// Act
+
+		result := EstimateIndexFootprint(arrayIndices, collectionItems, nil, 5)
+
+		// Assert
+
+		Expect(result).To(Equal(IndexFootprint{
+			Memory: 2 * 1000 * 5 * DefaultIndexItemSize,
+			Disk:   2 * 1000 * 5 * DefaultIndexItemSize,
+		}))
+	})
+})
+
+var _ = Describe("IsArrayIndex", func() {
+
+	DescribeTable("should detect array keys",
+		func(indexKey string, expected bool) {
+			// Act
+
+			result := IsArrayIndex(couchbasev1beta1.GlobalSecondaryIndex{IndexKey: []string{"type", indexKey}})
+
+			// Assert
+
+			Expect(result).To(Equal(expected))
+		},
+		Entry("distinct array", "DISTINCT ARRAY v FOR v IN schedule END", true),
+		Entry("all shorthand", "all categories", true),
+		Entry("scalar", "city", false),
+		Entry("field starting with all", "allowed", false),
+	)
+})
+
+var _ = Describe("ExceedsCapacity", func() {
+
+	DescribeTable("should compare against the threshold",
+		func(used int64, additional int64, total int64, expected bool) {
+			// Act
+
+			result := ExceedsCapacity(used, additional, total, 90)
+
+			// Assert
+
+			Expect(result).To(Equal(expected))
+		},
+		Entry("at the threshold", int64(80), int64(10), int64(100), false),
+		Entry("past the threshold", int64(80), int64(11), int64(100), true),
+		Entry("unknown total", int64(80), int64(11), int64(0), false),
+	)
+})
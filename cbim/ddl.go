@@ -0,0 +1,171 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/sqlpp"
+)
+
+// Generates N1QL statements which create a set of indices, for review or disaster recovery. Indices are created
+// deferred, followed by a BUILD INDEX statement for each keyspace. Indices targeting multiple scopes or collections
+// must be expanded first, otherwise they are skipped with a comment.
+func GenerateDdl(bucketName string, indices []couchbasev1beta1.GlobalSecondaryIndex) (string, error) {
+	var sb strings.Builder
+
+	keyspaces := []string{}
+	builds := map[string][]string{}
+
+	for _, gsi := range indices {
+		if IsFanOutIndex(gsi) {
+			fmt.Fprintf(&sb, "-- Skipped index %s, it targets multiple scopes or collections which are expanded when synced\n",
+				gsi.Name)
+			continue
+		}
+
+		spec, err := createIndexSpec(&gsi)
+		if err != nil {
+			return "", err
+		}
+
+		identifier := GetIndexIdentifier(gsi)
+		keyspace := formatKeyspace(bucketName, identifier)
+
+		statement, err := formatCreateIndex(keyspace, spec)
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteString(statement)
+		sb.WriteString(";\n")
+
+		if _, ok := builds[keyspace]; !ok {
+			keyspaces = append(keyspaces, keyspace)
+		}
+		builds[keyspace] = append(builds[keyspace], sqlpp.EscapeIdentifier(gsi.Name))
+	}
+
+	for _, keyspace := range keyspaces {
+		fmt.Fprintf(&sb, "BUILD INDEX ON %s(%s);\n", keyspace, strings.Join(builds[keyspace], ", "))
+	}
+
+	return sb.String(), nil
+}
+
+// Parses N1QL CREATE INDEX statements into global secondary indices on a bucket. Unlike ImportStatements, any
+// statement or option which can't be represented is an error, as is an index on a different bucket.
+func ParseDdl(bucketName string, script string) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+	result := ImportStatements(script)
+	if len(result.Warnings) > 0 {
+		return nil, errors.New(strings.Join(result.Warnings, "; "))
+	}
+
+	indices := make([]couchbasev1beta1.GlobalSecondaryIndex, len(result.Indices))
+	for i, imported := range result.Indices {
+		if imported.BucketName != bucketName {
+			return nil, fmt.Errorf("index %s is on bucket %s, expected %s", imported.Index.Name, imported.BucketName, bucketName)
+		}
+
+		indices[i] = imported.Index
+	}
+
+	return indices, nil
+}
+
+// Parses the DDL statements of an index set, along with the contents of the ConfigMap it references, if any
+func ParseIndexSetDdl(indexSet *couchbasev1beta1.CouchbaseIndexSet, configMapScript string) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+	if indexSet.Spec.Ddl == nil {
+		return nil, nil
+	}
+
+	scripts := append([]string{}, indexSet.Spec.Ddl.Statements...)
+	if configMapScript != "" {
+		scripts = append(scripts, configMapScript)
+	}
+
+	// Separate on new lines so that a trailing line comment doesn't swallow the semicolon
+	return ParseDdl(indexSet.Spec.BucketName, strings.Join(scripts, "\n;\n"))
+}
+
+// Merges indices parsed from DDL with the other indices of an index set, returning an error if an index is defined
+// more than once
+func MergeIndices(indices []couchbasev1beta1.GlobalSecondaryIndex,
+	ddlIndices []couchbasev1beta1.GlobalSecondaryIndex) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+
+	result := make([]couchbasev1beta1.GlobalSecondaryIndex, 0, len(indices)+len(ddlIndices))
+	result = append(result, indices...)
+	result = append(result, ddlIndices...)
+
+	if err := checkDuplicateIndexes(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func formatCreateIndex(keyspace string, spec IndexSpec) (string, error) {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "CREATE INDEX %s ON %s(%s)", sqlpp.EscapeIdentifier(spec.Name), keyspace, strings.Join(*spec.IndexKey, ", "))
+
+	with := map[string]interface{}{
+		"defer_build": true,
+	}
+
+	if spec.Partition != nil {
+		fmt.Fprintf(&sb, " PARTITION BY HASH(%s)", strings.Join(spec.Partition.Expressions, ", "))
+
+		if spec.Partition.NumPartitions != nil {
+			with["num_partition"] = *spec.Partition.NumPartitions
+		}
+	}
+
+	if spec.Condition != nil && *spec.Condition != "" {
+		fmt.Fprintf(&sb, " WHERE %s", *spec.Condition)
+	}
+
+	if spec.NumReplicas != nil {
+		with["num_replica"] = *spec.NumReplicas
+	}
+	if spec.RetainDeletedXattr != nil && *spec.RetainDeletedXattr {
+		with["retain_deleted_xattr"] = true
+	}
+
+	// Map keys are marshaled in sorted order, so the output is stable
+	options, err := json.Marshal(with)
+	if err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(&sb, " WITH %s", options)
+
+	return sb.String(), nil
+}
+
+func formatKeyspace(bucketName string, identifier GlobalSecondaryIndexIdentifier) string {
+	if identifier.IsDefaultCollection() {
+		return sqlpp.EscapeIdentifier(bucketName)
+	}
+
+	return fmt.Sprintf("%s.%s.%s", sqlpp.EscapeIdentifier(bucketName), sqlpp.EscapeIdentifier(identifier.ScopeName),
+		sqlpp.EscapeIdentifier(identifier.CollectionName))
+}
@@ -0,0 +1,237 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("GenerateDdl", func() {
+
+	It("should render deferred creates and builds", func() {
+		// Arrange
+
+		strategy := "Hash"
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{
+				Name:     "def_type",
+				IndexKey: []string{"type"},
+			},
+			{
+				Name:               "def_route",
+				ScopeName:          pointer.StringPtr("inventory"),
+				CollectionName:     pointer.StringPtr("route"),
+				IndexKey:           []string{"airline", "distance DESC"},
+				Condition:          pointer.StringPtr("stops = 0"),
+				NumReplicas:        intPtr(1),
+				RetainDeletedXAttr: pointer.BoolPtr(true),
+				Partition: &couchbasev1beta1.GlobalSecondaryIndexPartition{
+					Expressions:   []string{"airline"},
+					Strategy:      &strategy,
+					NumPartitions: intPtr(8),
+				},
+			},
+			{
+				Name:     "def_name",
+				IndexKey: []string{"name"},
+			},
+		}
+
+		// Act
+
+		result, err := GenerateDdl("travel-sample", indices)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal("CREATE INDEX `def_type` ON `travel-sample`(type) WITH {\"defer_build\":true};\n" +
+			"CREATE INDEX `def_route` ON `travel-sample`.`inventory`.`route`(airline, distance DESC) " +
+			"PARTITION BY HASH(airline) WHERE stops = 0 " +
+			"WITH {\"defer_build\":true,\"num_partition\":8,\"num_replica\":1,\"retain_deleted_xattr\":true};\n" +
+			"CREATE INDEX `def_name` ON `travel-sample`(name) WITH {\"defer_build\":true};\n" +
+			"BUILD INDEX ON `travel-sample`(`def_type`, `def_name`);\n" +
+			"BUILD INDEX ON `travel-sample`.`inventory`.`route`(`def_route`);\n"))
+	})
+
+	It("should skip fan-out indices", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{
+				Name:            "def_type",
+				ScopeNames:      []string{"tenant_*"},
+				CollectionNames: []string{"docs"},
+				IndexKey:        []string{"type"},
+			},
+		}
+
+		// Act
+
+		result, err := GenerateDdl("travel-sample", indices)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(HavePrefix("-- Skipped index def_type"))
+		Expect(result).NotTo(ContainSubstring("CREATE"))
+	})
+
+	It("should escape backticks in identifiers", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{
+				Name:     "def_type",
+				IndexKey: []string{"type"},
+			},
+		}
+
+		// Act
+
+		result, err := GenerateDdl("odd`bucket", indices)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal("CREATE INDEX `def_type` ON `odd``bucket`(type) WITH {\"defer_build\":true};\n" +
+			"BUILD INDEX ON `odd``bucket`(`def_type`);\n"))
+	})
+
+	It("should round trip through import", func() {
+		// Arrange
+
+		strategy := "Hash"
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{
+				Name:           "def_route",
+				ScopeName:      pointer.StringPtr("inventory"),
+				CollectionName: pointer.StringPtr("route"),
+				IndexKey:       []string{"airline", "distance DESC"},
+				Condition:      pointer.StringPtr("stops = 0"),
+				NumReplicas:    intPtr(2),
+				Partition: &couchbasev1beta1.GlobalSecondaryIndexPartition{
+					Expressions:   []string{"airline"},
+					Strategy:      &strategy,
+					NumPartitions: intPtr(8),
+				},
+			},
+		}
+
+		ddl, err := GenerateDdl("travel-sample", indices)
+		Expect(err).To(BeNil())
+
+		// Act
+
+		result := ImportStatements(ddl)
+
+		// Assert
+
+		Expect(result.Warnings).To(BeEmpty())
+		Expect(result.Indices).To(Equal([]ImportedIndex{
+			{BucketName: "travel-sample", Index: indices[0]},
+		}))
+	})
+
+	It("should fail on invalid indices", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "empty"},
+		}
+
+		// Act
+
+		_, err := GenerateDdl("travel-sample", indices)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("ParseIndexSetDdl", func() {
+
+	It("should parse inline and ConfigMap statements", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				BucketName: "travel-sample",
+				Ddl: &couchbasev1beta1.CouchbaseIndexSetDdl{
+					Statements: []string{
+						"CREATE INDEX def_type ON `travel-sample`(type) -- by type",
+						"CREATE INDEX def_name ON `travel-sample`(name) WITH {\"num_replica\": 1};",
+					},
+				},
+			},
+		}
+
+		// Act
+
+		result, err := ParseIndexSetDdl(&indexSet, "CREATE INDEX def_city ON `travel-sample`.inventory.hotel(city);\n"+
+			"BUILD INDEX ON `travel-sample`.inventory.hotel(def_city);")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal([]couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "def_type", IndexKey: []string{"type"}},
+			{Name: "def_name", IndexKey: []string{"name"}, NumReplicas: intPtr(1)},
+			{
+				Name:           "def_city",
+				ScopeName:      pointer.StringPtr("inventory"),
+				CollectionName: pointer.StringPtr("hotel"),
+				IndexKey:       []string{"city"},
+			},
+		}))
+	})
+
+	DescribeTable("should reject statements which can't be represented",
+		func(statement string) {
+			// Arrange
+
+			indexSet := couchbasev1beta1.CouchbaseIndexSet{
+				Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+					BucketName: "travel-sample",
+					Ddl: &couchbasev1beta1.CouchbaseIndexSetDdl{
+						Statements: []string{statement},
+					},
+				},
+			}
+
+			// Act
+
+			_, err := ParseIndexSetDdl(&indexSet, "")
+
+			// Assert
+
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("primary indices", "CREATE PRIMARY INDEX ON `travel-sample`"),
+		Entry("other buckets", "CREATE INDEX def_type ON beer(type)"),
+		Entry("other statements", "DROP INDEX `travel-sample`.def_type"),
+		Entry("syntax errors", "CREATE INDEX def_type ON `travel-sample`(type"),
+		Entry("node placement", "CREATE INDEX def_type ON `travel-sample`(type) WITH {\"nodes\": [\"a:8091\"]}"),
+	)
+})
+
+var _ = Describe("MergeIndices", func() {
+
+	It("should reject duplicate indices", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{{Name: "def_type", IndexKey: []string{"type"}}}
+		ddlIndices := []couchbasev1beta1.GlobalSecondaryIndex{{Name: "def_type", IndexKey: []string{"kind"}}}
+
+		// Act
+
+		_, err := MergeIndices(indices, ddlIndices)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,499 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/sqlpp"
+)
+
+// Index imported from an existing definition, along with the bucket it belongs to
+type ImportedIndex struct {
+	BucketName string
+	Index      couchbasev1beta1.GlobalSecondaryIndex
+}
+
+// Indices imported from an existing definition, along with warnings for anything which could not be represented
+type ImportResult struct {
+	Indices  []ImportedIndex
+	Warnings []string
+}
+
+func (result *ImportResult) warn(format string, args ...interface{}) {
+	result.Warnings = append(result.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Appends the indices and warnings from another result
+func (result *ImportResult) Merge(other ImportResult) {
+	result.Indices = append(result.Indices, other.Indices...)
+	result.Warnings = append(result.Warnings, other.Warnings...)
+}
+
+// Imports a couchbase-index-manager YAML spec, which may contain multiple documents. The spec doesn't include the
+// bucket, so it must be supplied.
+func ImportIndexSpecs(spec []byte, bucketName string) (ImportResult, error) {
+	result := ImportResult{}
+
+	for _, document := range splitYamlDocuments(string(spec)) {
+		fields := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(document), &fields); err != nil {
+			return ImportResult{}, err
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		if documentType, ok := fields["type"].(string); ok && documentType != "index" {
+			result.warn("skipped %s document %v, only index documents are supported", documentType, fields["name"])
+			continue
+		}
+
+		indexSpec := IndexSpec{}
+		if err := yaml.UnmarshalStrict([]byte(document), &indexSpec); err != nil {
+			result.warn("unable to read index %v: %s", fields["name"], err)
+			continue
+		}
+
+		result.importIndexSpec(bucketName, indexSpec)
+	}
+
+	return result, nil
+}
+
+// Splits a YAML stream into documents delimited by "---" lines
+func splitYamlDocuments(stream string) []string {
+	documents := []string{}
+
+	var sb strings.Builder
+	for _, line := range strings.Split(stream, "\n") {
+		if strings.TrimRight(line, " \t\r") == "---" {
+			documents = append(documents, sb.String())
+			sb.Reset()
+			continue
+		}
+
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	return append(documents, sb.String())
+}
+
+// Imports a script of CREATE INDEX statements. BUILD INDEX statements are ignored since indices are always built.
+func ImportStatements(script string) ImportResult {
+	result := ImportResult{}
+
+	for _, statement := range sqlpp.SplitStatements(script) {
+		statementType := sqlpp.GetStatementType(statement)
+		if statementType == "BUILD INDEX" {
+			continue
+		}
+
+		if !strings.HasPrefix(statementType, "CREATE") || !strings.HasSuffix(statementType, "INDEX") {
+			result.warn("skipped unsupported statement: %s", summarizeStatement(statement))
+			continue
+		}
+
+		createIndex, err := sqlpp.ParseCreateIndex(statement)
+		if err != nil {
+			result.warn("unable to parse statement %s: %s", summarizeStatement(statement), err)
+			continue
+		}
+
+		indexSpec, ok := result.convertCreateIndex(createIndex)
+		if ok {
+			result.importIndexSpec(createIndex.BucketName, indexSpec)
+		}
+	}
+
+	return result
+}
+
+// Row from system:indexes
+type systemIndex struct {
+	Name       string                 `json:"name"`
+	BucketId   string                 `json:"bucket_id"`
+	ScopeId    string                 `json:"scope_id"`
+	KeyspaceId string                 `json:"keyspace_id"`
+	IndexKey   []string               `json:"index_key"`
+	Condition  string                 `json:"condition"`
+	IsPrimary  bool                   `json:"is_primary"`
+	Partition  string                 `json:"partition"`
+	Using      string                 `json:"using"`
+	With       map[string]interface{} `json:"with"`
+	Metadata   struct {
+		NumReplica *int `json:"num_replica"`
+	} `json:"metadata"`
+}
+
+// Imports the JSON results of a system:indexes query. The results may be from "SELECT * FROM system:indexes", which
+// nests each row in an "indexes" property, or "SELECT RAW i FROM system:indexes AS i". The full query response
+// including the "results" property is also accepted.
+func ImportSystemIndexes(data []byte) (ImportResult, error) {
+	var rows []json.RawMessage
+	if err := json.Unmarshal(data, &rows); err != nil {
+		response := struct {
+			Results *[]json.RawMessage `json:"results"`
+		}{}
+		if json.Unmarshal(data, &response) != nil || response.Results == nil {
+			return ImportResult{}, errors.New("expected an array of system:indexes rows or a query response")
+		}
+
+		rows = *response.Results
+	}
+
+	result := ImportResult{}
+	for _, row := range rows {
+		wrapper := struct {
+			Indexes *json.RawMessage `json:"indexes"`
+		}{}
+		if json.Unmarshal(row, &wrapper) == nil && wrapper.Indexes != nil {
+			row = *wrapper.Indexes
+		}
+
+		index := systemIndex{}
+		if err := json.Unmarshal(row, &index); err != nil {
+			return ImportResult{}, err
+		}
+
+		bucketName, indexSpec, ok := result.convertSystemIndex(index)
+		if ok {
+			result.importIndexSpec(bucketName, indexSpec)
+		}
+	}
+
+	return result, nil
+}
+
+func (result *ImportResult) convertSystemIndex(index systemIndex) (string, IndexSpec, bool) {
+	if index.Using != "" && !strings.EqualFold(index.Using, "gsi") {
+		result.warn("skipped index %s, only GSI indexes are supported, found %s", index.Name, index.Using)
+		return "", IndexSpec{}, false
+	}
+
+	if hasVectorKey(index.IndexKey) {
+		result.warn("skipped vector index %s, vector indices aren't supported", index.Name)
+		return "", IndexSpec{}, false
+	}
+
+	indexSpec := IndexSpec{
+		Name: index.Name,
+	}
+
+	// The bucket_id is only present for indices on collections, otherwise the keyspace is the bucket
+	bucketName := index.KeyspaceId
+	if index.BucketId != "" {
+		bucketName = index.BucketId
+		indexSpec.Scope = &index.ScopeId
+		indexSpec.Collection = &index.KeyspaceId
+	}
+
+	if index.IsPrimary {
+		indexSpec.IsPrimary = &index.IsPrimary
+	}
+	if len(index.IndexKey) > 0 {
+		indexSpec.IndexKey = &index.IndexKey
+	}
+	if index.Condition != "" {
+		indexSpec.Condition = &index.Condition
+	}
+	if index.Metadata.NumReplica != nil && *index.Metadata.NumReplica > 0 {
+		indexSpec.NumReplicas = index.Metadata.NumReplica
+	}
+
+	if index.Partition != "" {
+		strategy, expressions, err := sqlpp.ParsePartition(index.Partition)
+		if err != nil {
+			result.warn("skipped index %s, unable to parse partition %s: %s", index.Name, index.Partition, err)
+			return "", IndexSpec{}, false
+		}
+
+		indexSpec.Partition = &PartitionSpec{Expressions: expressions, Strategy: &strategy}
+	}
+
+	if !result.applyWith(&indexSpec, index.With) {
+		return "", IndexSpec{}, false
+	}
+
+	return bucketName, indexSpec, true
+}
+
+func (result *ImportResult) convertCreateIndex(statement *sqlpp.CreateIndexStatement) (IndexSpec, bool) {
+	if statement.IsVector || hasVectorKey(statement.IndexKey) {
+		result.warn("skipped vector index %s, vector indices aren't supported", statement.Name)
+		return IndexSpec{}, false
+	}
+
+	indexSpec := IndexSpec{
+		Name:       statement.Name,
+		Scope:      statement.ScopeName,
+		Collection: statement.CollectionName,
+		Condition:  statement.Condition,
+	}
+
+	if statement.IsPrimary {
+		indexSpec.IsPrimary = &statement.IsPrimary
+	}
+	if len(statement.IndexKey) > 0 {
+		indexSpec.IndexKey = &statement.IndexKey
+	}
+	if statement.PartitionStrategy != nil {
+		indexSpec.Partition = &PartitionSpec{
+			Expressions: statement.PartitionExpressions,
+			Strategy:    statement.PartitionStrategy,
+		}
+	}
+
+	if !result.applyWith(&indexSpec, statement.With) {
+		return IndexSpec{}, false
+	}
+
+	return indexSpec, true
+}
+
+// Applies options from the WITH clause of CREATE INDEX, returns false if the index can't be imported
+func (result *ImportResult) applyWith(indexSpec *IndexSpec, with map[string]interface{}) bool {
+	names := make([]string, 0, len(with))
+	for name := range with {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := with[name]
+
+		var err error
+		switch name {
+		case "num_replica":
+			indexSpec.NumReplicas, err = getIntOption(value)
+		case "num_partition":
+			if indexSpec.Partition == nil {
+				result.warn("index %s: ignored num_partition on an index which is not partitioned", indexSpec.Name)
+				continue
+			}
+			indexSpec.Partition.NumPartitions, err = getIntOption(value)
+		case "retain_deleted_xattr":
+			retain, ok := value.(bool)
+			if !ok {
+				err = errors.New("expected a boolean")
+			}
+			indexSpec.RetainDeletedXattr = &retain
+		case "nodes":
+			nodes := []string{}
+			if values, ok := value.([]interface{}); ok {
+				for _, node := range values {
+					nodes = append(nodes, fmt.Sprint(node))
+				}
+			} else {
+				nodes = append(nodes, fmt.Sprint(value))
+			}
+			indexSpec.Nodes = &nodes
+		case "defer_build":
+			// Indices are always built by the operator
+		default:
+			result.warn("index %s: ignored unsupported option %s", indexSpec.Name, name)
+		}
+
+		if err != nil {
+			result.warn("skipped index %s, invalid %s: %s", indexSpec.Name, name, err)
+			return false
+		}
+	}
+
+	return true
+}
+
+func getIntOption(value interface{}) (*int, error) {
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) {
+		return nil, errors.New("expected an integer")
+	}
+
+	result := int(number)
+	return &result, nil
+}
+
+var vectorKeyRegex = regexp.MustCompile(`(?i)\s+VECTOR$`)
+
+func hasVectorKey(indexKey []string) bool {
+	for _, key := range indexKey {
+		if vectorKeyRegex.MatchString(strings.TrimSpace(key)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Shortens a statement for warning messages
+func summarizeStatement(statement string) string {
+	statement = strings.Join(strings.Fields(statement), " ")
+	if len(statement) > 60 {
+		return statement[:57] + "..."
+	}
+
+	return statement
+}
+
+// Converts a couchbase-index-manager index spec to a GlobalSecondaryIndex, adding it to the result. Anything which
+// can't be represented is reported as a warning.
+func (result *ImportResult) importIndexSpec(bucketName string, spec IndexSpec) {
+	name := spec.Name
+	if name == "" {
+		name = "<unnamed>"
+	}
+
+	switch {
+	case spec.Lifecycle != nil && spec.Lifecycle.Drop != nil && *spec.Lifecycle.Drop:
+		result.warn("skipped index %s, it is marked to be dropped", name)
+		return
+	case spec.IsPrimary != nil && *spec.IsPrimary:
+		result.warn("skipped primary index %s on bucket %s, primary indices are not supported", name, bucketName)
+		return
+	case spec.Name == "":
+		result.warn("skipped an index on bucket %s without a name", bucketName)
+		return
+	case bucketName == "":
+		result.warn("skipped index %s, the bucket is unknown", name)
+		return
+	}
+
+	gsi := couchbasev1beta1.GlobalSecondaryIndex{
+		Name:               spec.Name,
+		Condition:          spec.Condition,
+		NumReplicas:        spec.NumReplicas,
+		RetainDeletedXAttr: spec.RetainDeletedXattr,
+	}
+
+	if spec.Scope != nil && spec.Collection != nil && (*spec.Scope != defaultScopeName || *spec.Collection != defaultCollectionName) {
+		gsi.ScopeName = spec.Scope
+		gsi.CollectionName = spec.Collection
+	}
+
+	if spec.Partition != nil {
+		gsi.Partition = &couchbasev1beta1.GlobalSecondaryIndexPartition{
+			Expressions:   spec.Partition.Expressions,
+			NumPartitions: spec.Partition.NumPartitions,
+		}
+
+		if spec.Partition.Strategy != nil {
+			if !strings.EqualFold(*spec.Partition.Strategy, StrategyHash) {
+				result.warn("skipped index %s, unsupported partition strategy %s", name, *spec.Partition.Strategy)
+				return
+			}
+
+			strategy := "Hash"
+			gsi.Partition.Strategy = &strategy
+		}
+	}
+
+	if spec.Nodes != nil || (spec.ManualReplica != nil && *spec.ManualReplica) {
+		result.warn("index %s: ignored node placement, replicas are placed by the index service", name)
+	}
+
+	indexKey := []string{}
+	if spec.IndexKey != nil {
+		indexKey = *spec.IndexKey
+	}
+
+	if hasVectorKey(indexKey) {
+		result.warn("skipped vector index %s, vector indices aren't supported", name)
+		return
+	}
+
+	if len(indexKey) > 0 {
+		gsi.IndexKey = indexKey
+	}
+
+	if err := ValidateIndex(gsi); err != nil {
+		result.warn("skipped index %s: %s", name, err)
+		return
+	}
+
+	result.Indices = append(result.Indices, ImportedIndex{
+		BucketName: bucketName,
+		Index:      gsi,
+	})
+}
+
+var invalidNameCharsRegex = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Groups imported indices into an index set for each bucket, sorted by bucket name. Index sets are named after the
+// bucket, with an optional prefix. Duplicate indices with the same normalized definition are skipped, conflicting
+// definitions are reported as warnings, keeping the first definition.
+func GroupIndexSets(indices []ImportedIndex, namePrefix string) ([]couchbasev1beta1.CouchbaseIndexSet, []string) {
+	buckets := map[string][]couchbasev1beta1.GlobalSecondaryIndex{}
+	seen := map[string]map[GlobalSecondaryIndexIdentifier]couchbasev1beta1.GlobalSecondaryIndex{}
+	warnings := []string{}
+
+	for _, imported := range indices {
+		identifiers, ok := seen[imported.BucketName]
+		if !ok {
+			identifiers = map[GlobalSecondaryIndexIdentifier]couchbasev1beta1.GlobalSecondaryIndex{}
+			seen[imported.BucketName] = identifiers
+		}
+
+		identifier := GetIndexIdentifier(imported.Index)
+		if existing, ok := identifiers[identifier]; ok {
+			if !IndexDefinitionsEqual(existing, imported.Index) {
+				warnings = append(warnings, fmt.Sprintf("skipped conflicting definition of index %s on bucket %s", identifier.ToString(), imported.BucketName))
+			}
+			continue
+		}
+		identifiers[identifier] = imported.Index
+
+		buckets[imported.BucketName] = append(buckets[imported.BucketName], imported.Index)
+	}
+
+	bucketNames := make([]string, 0, len(buckets))
+	for bucketName := range buckets {
+		bucketNames = append(bucketNames, bucketName)
+	}
+	sort.Strings(bucketNames)
+
+	result := make([]couchbasev1beta1.CouchbaseIndexSet, len(bucketNames))
+	for i, bucketName := range bucketNames {
+		name := strings.Trim(invalidNameCharsRegex.ReplaceAllString(strings.ToLower(namePrefix+bucketName), "-"), "-")
+
+		result[i] = couchbasev1beta1.CouchbaseIndexSet{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: couchbasev1beta1.GroupVersion.String(),
+				Kind:       "CouchbaseIndexSet",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				BucketName: bucketName,
+				Indices:    buckets[bucketName],
+			},
+		}
+	}
+
+	return result, warnings
+}
@@ -0,0 +1,295 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("ImportIndexSpecs", func() {
+
+	It("should convert index specs", func() {
+		// Arrange
+
+		spec := []byte(`type: index
+name: def_route
+scope: inventory
+collection: route
+index_key:
+- airline
+- distance
+condition: stops = 0
+num_replica: 1
+retain_deleted_xattr: true
+partition:
+  exprs:
+  - airline
+  strategy: hash
+  num_partition: 8
+---
+type: override
+name: def_route
+num_replica: 2
+---
+type: index
+name: old_index
+index_key:
+- name
+lifecycle:
+  drop: true
+`)
+
+		// Act
+
+		result, err := ImportIndexSpecs(spec, "travel-sample")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+
+		strategy := "Hash"
+		Expect(result.Indices).To(Equal([]ImportedIndex{
+			{
+				BucketName: "travel-sample",
+				Index: couchbasev1beta1.GlobalSecondaryIndex{
+					Name:               "def_route",
+					ScopeName:          pointer.StringPtr("inventory"),
+					CollectionName:     pointer.StringPtr("route"),
+					IndexKey:           []string{"airline", "distance"},
+					Condition:          pointer.StringPtr("stops = 0"),
+					NumReplicas:        intPtr(1),
+					RetainDeletedXAttr: pointer.BoolPtr(true),
+					Partition: &couchbasev1beta1.GlobalSecondaryIndexPartition{
+						Expressions:   []string{"airline"},
+						Strategy:      &strategy,
+						NumPartitions: intPtr(8),
+					},
+				},
+			},
+		}))
+		Expect(result.Warnings).To(HaveLen(2))
+	})
+
+	It("should warn on unknown properties", func() {
+		// Act
+
+		result, err := ImportIndexSpecs([]byte("type: index\nname: a\nunknown: b\n"), "travel-sample")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.Indices).To(BeEmpty())
+		Expect(result.Warnings).To(HaveLen(1))
+	})
+
+	It("should fail on malformed YAML", func() {
+		// Act
+
+		_, err := ImportIndexSpecs([]byte("type: [index\n"), "travel-sample")
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("ImportStatements", func() {
+
+	It("should convert CREATE INDEX statements", func() {
+		// Arrange
+
+		script := `
+CREATE PRIMARY INDEX ON ` + "`travel-sample`" + `;
+CREATE INDEX def_route ON ` + "`travel-sample`" + `.inventory.route(airline, distance)
+	PARTITION BY HASH(airline)
+	WHERE stops = 0
+	WITH {"num_replica": 1, "num_partition": 8, "defer_build": true};
+BUILD INDEX ON ` + "`travel-sample`" + `(def_route);
+CREATE INDEX def_type ON beer(type);
+`
+
+		// Act
+
+		result := ImportStatements(script)
+
+		// Assert
+
+		strategy := "Hash"
+		Expect(result.Indices).To(Equal([]ImportedIndex{
+			{
+				BucketName: "travel-sample",
+				Index: couchbasev1beta1.GlobalSecondaryIndex{
+					Name:           "def_route",
+					ScopeName:      pointer.StringPtr("inventory"),
+					CollectionName: pointer.StringPtr("route"),
+					IndexKey:       []string{"airline", "distance"},
+					Condition:      pointer.StringPtr("stops = 0"),
+					NumReplicas:    intPtr(1),
+					Partition: &couchbasev1beta1.GlobalSecondaryIndexPartition{
+						Expressions:   []string{"airline"},
+						Strategy:      &strategy,
+						NumPartitions: intPtr(8),
+					},
+				},
+			},
+			{
+				BucketName: "beer",
+				Index: couchbasev1beta1.GlobalSecondaryIndex{
+					Name:     "def_type",
+					IndexKey: []string{"type"},
+				},
+			},
+		}))
+		Expect(result.Warnings).To(HaveLen(1))
+		Expect(result.Warnings[0]).To(ContainSubstring("primary"))
+	})
+
+	It("should skip vector indices", func() {
+		// Arrange
+
+		script := "CREATE VECTOR INDEX hotel_vec ON hotels(embedding VECTOR) INCLUDE (city) " +
+			"WITH {'dimension': 384, 'similarity': 'cosine', 'description': 'IVF,SQ8'};" +
+			"CREATE INDEX composite_vec ON hotels(city, embedding VECTOR) WITH {'dimension': 384}"
+
+		// Act
+
+		result := ImportStatements(script)
+
+		// Assert
+
+		Expect(result.Indices).To(BeEmpty())
+		Expect(result.Warnings).To(HaveLen(2))
+		Expect(result.Warnings[0]).To(ContainSubstring("skipped vector index hotel_vec"))
+		Expect(result.Warnings[1]).To(ContainSubstring("skipped vector index composite_vec"))
+	})
+
+	It("should warn on invalid statements", func() {
+		// Act
+
+		result := ImportStatements("CREATE INDEX a ON b(c) USING VIEW; DROP INDEX b.d")
+
+		// Assert
+
+		Expect(result.Indices).To(BeEmpty())
+		Expect(result.Warnings).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("ImportSystemIndexes", func() {
+
+	It("should convert query results", func() {
+		// Arrange
+
+		data := []byte(`{"results": [
+	{"indexes": {
+		"name": "def_route", "bucket_id": "travel-sample", "scope_id": "inventory", "keyspace_id": "route",
+		"index_key": ["` + "`airline`" + `"], "condition": "(` + "`stops`" + ` = 0)", "partition": "HASH(` + "`airline`" + `)",
+		"using": "gsi", "metadata": {"num_replica": 1}
+	}},
+	{"indexes": {
+		"name": "#primary", "keyspace_id": "beer", "is_primary": true, "using": "gsi"
+	}}
+]}`)
+
+		// Act
+
+		result, err := ImportSystemIndexes(data)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+
+		strategy := "Hash"
+		Expect(result.Indices).To(Equal([]ImportedIndex{
+			{
+				BucketName: "travel-sample",
+				Index: couchbasev1beta1.GlobalSecondaryIndex{
+					Name:           "def_route",
+					ScopeName:      pointer.StringPtr("inventory"),
+					CollectionName: pointer.StringPtr("route"),
+					IndexKey:       []string{"`airline`"},
+					Condition:      pointer.StringPtr("(`stops` = 0)"),
+					NumReplicas:    intPtr(1),
+					Partition: &couchbasev1beta1.GlobalSecondaryIndexPartition{
+						Expressions: []string{"`airline`"},
+						Strategy:    &strategy,
+					},
+				},
+			},
+		}))
+		Expect(result.Warnings).To(HaveLen(1))
+	})
+
+	It("should convert plain rows", func() {
+		// Arrange
+
+		data := []byte(`[{"name": "def_type", "keyspace_id": "beer", "index_key": ["type"], "using": "gsi"}]`)
+
+		// Act
+
+		result, err := ImportSystemIndexes(data)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.Indices).To(HaveLen(1))
+		Expect(result.Indices[0].BucketName).To(Equal("beer"))
+	})
+})
+
+var _ = Describe("GroupIndexSets", func() {
+
+	It("should group by bucket", func() {
+		// Arrange
+
+		indices := []ImportedIndex{
+			{BucketName: "travel-sample", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "b", IndexKey: []string{"b"}}},
+			{BucketName: "beer", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "a", IndexKey: []string{"a"}}},
+			{BucketName: "travel-sample", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "c", IndexKey: []string{"c"}}},
+			{BucketName: "travel-sample", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "b", IndexKey: []string{"d"}}},
+		}
+
+		// Act
+
+		result, warnings := GroupIndexSets(indices, "prod_")
+
+		// Assert
+
+		Expect(warnings).To(HaveLen(1))
+		Expect(result).To(HaveLen(2))
+
+		Expect(result[0].Name).To(Equal("prod-beer"))
+		Expect(result[0].Spec.BucketName).To(Equal("beer"))
+		Expect(result[0].Spec.Indices).To(HaveLen(1))
+
+		Expect(result[1].Name).To(Equal("prod-travel-sample"))
+		Expect(result[1].Spec.BucketName).To(Equal("travel-sample"))
+		Expect(result[1].Spec.Indices).To(HaveLen(2))
+		Expect(result[1].Spec.Indices[0].IndexKey).To(Equal([]string{"b"}))
+	})
+
+	It("should skip duplicates with equivalent definitions", func() {
+		// Arrange
+
+		indices := []ImportedIndex{
+			{BucketName: "travel-sample", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "def_type", IndexKey: []string{"type"}, Condition: pointer.StringPtr("type = 'airline'")}},
+			{BucketName: "travel-sample", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "def_type", IndexKey: []string{"`type`"}, Condition: pointer.StringPtr("(`type` = \"airline\")")}},
+		}
+
+		// Act
+
+		result, warnings := GroupIndexSets(indices, "")
+
+		// Assert
+
+		Expect(warnings).To(BeEmpty())
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Spec.Indices).To(HaveLen(1))
+	})
+})
+
+func intPtr(value int) *int {
+	return &value
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"fmt"
+	"strings"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Gets the index key of an index as SQL++ expressions, rendering structured keys if present
+func GetIndexKey(gsi couchbasev1beta1.GlobalSecondaryIndex) []string {
+	if len(gsi.Keys) == 0 {
+		return gsi.IndexKey
+	}
+
+	result := make([]string, len(gsi.Keys))
+	for i, key := range gsi.Keys {
+		result[i] = renderKey(key)
+	}
+
+	return result
+}
+
+func renderKey(key couchbasev1beta1.GlobalSecondaryIndexKey) string {
+	var sb strings.Builder
+
+	if key.Array != nil {
+		mode := "DISTINCT"
+		if key.Array.Mode != nil && *key.Array.Mode == "All" {
+			mode = "ALL"
+		}
+
+		sb.WriteString(mode)
+		sb.WriteString(" ARRAY ")
+
+		if len(key.Array.FlattenKeys) > 0 {
+			flattenKeys := make([]string, len(key.Array.FlattenKeys))
+			for i, flattenKey := range key.Array.FlattenKeys {
+				flattenKeys[i] = flattenKey.Expression + renderDirection(flattenKey.Direction)
+			}
+
+			sb.WriteString("FLATTEN_KEYS(")
+			sb.WriteString(strings.Join(flattenKeys, ", "))
+			sb.WriteString(")")
+		} else if key.Expression != nil {
+			sb.WriteString(*key.Expression)
+		}
+
+		sb.WriteString(" FOR ")
+		sb.WriteString(key.Array.Variable)
+		sb.WriteString(" IN ")
+		sb.WriteString(key.Array.In)
+		if key.Array.When != nil && *key.Array.When != "" {
+			sb.WriteString(" WHEN ")
+			sb.WriteString(*key.Array.When)
+		}
+		sb.WriteString(" END")
+	} else if key.Expression != nil {
+		sb.WriteString(*key.Expression)
+	}
+
+	if key.IncludeMissing != nil && *key.IncludeMissing {
+		sb.WriteString(" INCLUDE MISSING")
+	}
+
+	sb.WriteString(renderDirection(key.Direction))
+
+	return sb.String()
+}
+
+func renderDirection(direction *string) string {
+	if direction != nil && *direction == "Descending" {
+		return " DESC"
+	}
+
+	return ""
+}
+
+// Validates the keys of each index, returning an error describing the first invalid index
+func ValidateIndices(indices []couchbasev1beta1.GlobalSecondaryIndex) error {
+	for _, gsi := range indices {
+		if err := ValidateIndex(gsi); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validates the keys of an index, including structured keys
+func ValidateIndex(gsi couchbasev1beta1.GlobalSecondaryIndex) error {
+	if err := validateKeys(gsi); err != nil {
+		return err
+	}
+
+	if len(GetIndexKey(gsi)) == 0 {
+		return fmt.Errorf("index %s must have at least one index key", gsi.Name)
+	}
+
+	return nil
+}
+
+// Validates the structured keys of an index
+func validateKeys(gsi couchbasev1beta1.GlobalSecondaryIndex) error {
+	if len(gsi.Keys) == 0 {
+		return nil
+	}
+
+	if len(gsi.IndexKey) > 0 {
+		return fmt.Errorf("index %s may not have both indexKey and keys", gsi.Name)
+	}
+
+	hasArray := false
+	for i, key := range gsi.Keys {
+		hasExpression := key.Expression != nil && strings.TrimSpace(*key.Expression) != ""
+
+		if key.IncludeMissing != nil && *key.IncludeMissing && i > 0 {
+			return fmt.Errorf("index %s may only include missing values on the leading key", gsi.Name)
+		}
+
+		if key.Array == nil {
+			if !hasExpression {
+				return fmt.Errorf("key %d of index %s must have an expression", i+1, gsi.Name)
+			}
+
+			continue
+		}
+
+		if hasArray {
+			return fmt.Errorf("index %s may only have one array key", gsi.Name)
+		}
+		hasArray = true
+
+		hasFlattenKeys := len(key.Array.FlattenKeys) > 0
+		if hasExpression == hasFlattenKeys {
+			return fmt.Errorf("array key %d of index %s must have exactly one of expression or flattenKeys", i+1, gsi.Name)
+		}
+
+		if strings.TrimSpace(key.Array.Variable) == "" || strings.TrimSpace(key.Array.In) == "" {
+			return fmt.Errorf("array key %d of index %s must have a variable and an array expression", i+1, gsi.Name)
+		}
+
+		for _, flattenKey := range key.Array.FlattenKeys {
+			if strings.TrimSpace(flattenKey.Expression) == "" {
+				return fmt.Errorf("flattened keys of index %s must have an expression", gsi.Name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("GetIndexKey", func() {
+
+	It("should return the string form unchanged", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name:     "example",
+			IndexKey: []string{"type", "name DESC"},
+		}
+
+		// Act
+
+		result := GetIndexKey(gsi)
+
+		// Assert
+
+		Expect(result).To(Equal([]string{"type", "name DESC"}))
+	})
+
+	It("should render direction and include missing", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "example",
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{Expression: pointer.StringPtr("type"), IncludeMissing: pointer.BoolPtr(true), Direction: pointer.StringPtr("Descending")},
+				{Expression: pointer.StringPtr("name"), Direction: pointer.StringPtr("Ascending")},
+			},
+		}
+
+		// Act
+
+		result := GetIndexKey(gsi)
+
+		// Assert
+
+		Expect(result).To(Equal([]string{"type INCLUDE MISSING DESC", "name"}))
+	})
+
+	It("should render array keys", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "example",
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{
+					Expression: pointer.StringPtr("s.flight"),
+					Array: &couchbasev1beta1.GlobalSecondaryIndexArrayKey{
+						Mode:     pointer.StringPtr("All"),
+						Variable: "s",
+						In:       "schedule",
+						When:     pointer.StringPtr("s.day = 1"),
+					},
+				},
+			},
+		}
+
+		// Act
+
+		result := GetIndexKey(gsi)
+
+		// Assert
+
+		Expect(result).To(Equal([]string{"ALL ARRAY s.flight FOR s IN schedule WHEN s.day = 1 END"}))
+	})
+
+	It("should render flattened array keys", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "example",
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{
+					Array: &couchbasev1beta1.GlobalSecondaryIndexArrayKey{
+						Variable: "s",
+						In:       "schedule",
+						FlattenKeys: []couchbasev1beta1.GlobalSecondaryIndexFlattenKey{
+							{Expression: "s.day", Direction: pointer.StringPtr("Descending")},
+							{Expression: "s.flight"},
+						},
+					},
+				},
+				{Expression: pointer.StringPtr("airline")},
+			},
+		}
+
+		// Act
+
+		result := GetIndexKey(gsi)
+
+		// Assert
+
+		Expect(result).To(Equal([]string{
+			"DISTINCT ARRAY FLATTEN_KEYS(s.day DESC, s.flight) FOR s IN schedule END",
+			"airline",
+		}))
+	})
+})
+
+var _ = Describe("validateKeys", func() {
+
+	It("should reject both forms", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name:     "example",
+			IndexKey: []string{"type"},
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{Expression: pointer.StringPtr("name")},
+			},
+		}
+
+		// Act
+
+		err := validateKeys(gsi)
+
+		// Assert
+
+		Expect(err).To(MatchError("index example may not have both indexKey and keys"))
+	})
+
+	It("should reject include missing after the leading key", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "example",
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{Expression: pointer.StringPtr("type")},
+				{Expression: pointer.StringPtr("name"), IncludeMissing: pointer.BoolPtr(true)},
+			},
+		}
+
+		// Act
+
+		err := validateKeys(gsi)
+
+		// Assert
+
+		Expect(err).To(MatchError("index example may only include missing values on the leading key"))
+	})
+
+	It("should reject multiple array keys", func() {
+		// Arrange
+
+		array := &couchbasev1beta1.GlobalSecondaryIndexArrayKey{
+			Variable: "v",
+			In:       "items",
+		}
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "example",
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{Expression: pointer.StringPtr("v.a"), Array: array},
+				{Expression: pointer.StringPtr("v.b"), Array: array},
+			},
+		}
+
+		// Act
+
+		err := validateKeys(gsi)
+
+		// Assert
+
+		Expect(err).To(MatchError("index example may only have one array key"))
+	})
+
+	It("should reject array keys with an expression and flattened keys", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "example",
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{
+					Expression: pointer.StringPtr("v.a"),
+					Array: &couchbasev1beta1.GlobalSecondaryIndexArrayKey{
+						Variable:    "v",
+						In:          "items",
+						FlattenKeys: []couchbasev1beta1.GlobalSecondaryIndexFlattenKey{{Expression: "v.b"}},
+					},
+				},
+			},
+		}
+
+		// Act
+
+		err := validateKeys(gsi)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("ValidateIndex", func() {
+
+	It("should reject an index without keys", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "example",
+		}
+
+		// Act
+
+		err := ValidateIndex(gsi)
+
+		// Assert
+
+		Expect(err).To(MatchError("index example must have at least one index key"))
+	})
+
+	It("should accept structured keys", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "example",
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{Expression: pointer.StringPtr("type")},
+			},
+		}
+
+		// Act
+
+		err := ValidateIndex(gsi)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+	})
+})
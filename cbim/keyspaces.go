@@ -0,0 +1,150 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Returns true if the index targets multiple scopes or collections
+func IsFanOutIndex(gsi couchbasev1beta1.GlobalSecondaryIndex) bool {
+	return len(gsi.ScopeNames) > 0 || len(gsi.CollectionNames) > 0
+}
+
+// Expands indices which target multiple scopes or collections into a separate index for each matching collection.
+// The existing collections are supplied as a map of scope names to collection names.
+func ExpandKeyspaces(indices []couchbasev1beta1.GlobalSecondaryIndex,
+	collections map[string][]string) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+
+	scopeNames := make([]string, 0, len(collections))
+	for scopeName := range collections {
+		scopeNames = append(scopeNames, scopeName)
+	}
+	sort.Strings(scopeNames)
+
+	result := make([]couchbasev1beta1.GlobalSecondaryIndex, 0, len(indices))
+	for _, gsi := range indices {
+		if !IsFanOutIndex(gsi) {
+			result = append(result, gsi)
+			continue
+		}
+
+		if gsi.ScopeName != nil && len(gsi.ScopeNames) > 0 {
+			return nil, fmt.Errorf("index %s may not have both scopeName and scopeNames", gsi.Name)
+		}
+		if gsi.CollectionName != nil && len(gsi.CollectionNames) > 0 {
+			return nil, fmt.Errorf("index %s may not have both collectionName and collectionNames", gsi.Name)
+		}
+
+		scopePatterns := gsi.ScopeNames
+		if len(scopePatterns) == 0 {
+			scopePatterns = []string{defaultedName(gsi.ScopeName)}
+		}
+		collectionPatterns := gsi.CollectionNames
+		if len(collectionPatterns) == 0 {
+			collectionPatterns = []string{defaultedName(gsi.CollectionName)}
+		}
+
+		for _, scopeName := range scopeNames {
+			if matched, err := matchesAny(scopePatterns, scopeName); err != nil {
+				return nil, fmt.Errorf("index %s: %w", gsi.Name, err)
+			} else if !matched {
+				continue
+			}
+
+			collectionNames := append([]string{}, collections[scopeName]...)
+			sort.Strings(collectionNames)
+
+			for _, collectionName := range collectionNames {
+				if matched, err := matchesAny(collectionPatterns, collectionName); err != nil {
+					return nil, fmt.Errorf("index %s: %w", gsi.Name, err)
+				} else if !matched {
+					continue
+				}
+
+				expanded := gsi.DeepCopy()
+				expanded.ScopeName = pointer.StringPtr(scopeName)
+				expanded.CollectionName = pointer.StringPtr(collectionName)
+				expanded.ScopeNames = nil
+				expanded.CollectionNames = nil
+
+				result = append(result, *expanded)
+			}
+		}
+	}
+
+	if err := checkDuplicateIndexes(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Returns the collections referenced by indices which do not exist, in "scope.collection" format. The existing
+// collections are supplied as a map of scope names to collection names.
+func GetMissingKeyspaces(indices []couchbasev1beta1.GlobalSecondaryIndex, collections map[string][]string) []string {
+	missing := map[string]bool{}
+	for _, gsi := range indices {
+		if IsFanOutIndex(gsi) {
+			continue
+		}
+
+		scopeName := defaultedName(gsi.ScopeName)
+		collectionName := defaultedName(gsi.CollectionName)
+
+		found := false
+		for _, name := range collections[scopeName] {
+			if name == collectionName {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			missing[scopeName+"."+collectionName] = true
+		}
+	}
+
+	result := make([]string, 0, len(missing))
+	for keyspace := range missing {
+		result = append(result, keyspace)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func matchesAny(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern %s", pattern)
+		}
+
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
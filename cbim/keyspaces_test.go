@@ -0,0 +1,135 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("ExpandKeyspaces", func() {
+
+	collections := map[string][]string{
+		"_default": {"_default"},
+		"tenant_b": {"orders", "users"},
+		"tenant_a": {"users", "orders"},
+	}
+
+	It("should leave other indices unchanged", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "idx", ScopeName: pointer.StringPtr("missing")},
+		}
+
+		// Act
+
+		result, err := ExpandKeyspaces(indices, collections)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(indices))
+	})
+
+	It("should expand matching scopes and collections", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "idx", ScopeNames: []string{"tenant_*"}, CollectionName: pointer.StringPtr("orders")},
+		}
+
+		// Act
+
+		result, err := ExpandKeyspaces(indices, collections)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal([]couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "idx", ScopeName: pointer.StringPtr("tenant_a"), CollectionName: pointer.StringPtr("orders")},
+			{Name: "idx", ScopeName: pointer.StringPtr("tenant_b"), CollectionName: pointer.StringPtr("orders")},
+		}))
+	})
+
+	It("should expand collections in the default scope", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "idx", CollectionNames: []string{"*"}},
+		}
+
+		// Act
+
+		result, err := ExpandKeyspaces(indices, collections)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal([]couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "idx", ScopeName: pointer.StringPtr("_default"), CollectionName: pointer.StringPtr("_default")},
+		}))
+	})
+
+	It("should reject both scopeName and scopeNames", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "idx", ScopeName: pointer.StringPtr("tenant_a"), ScopeNames: []string{"tenant_*"}},
+		}
+
+		// Act
+
+		_, err := ExpandKeyspaces(indices, collections)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should reject duplicates after expansion", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "idx", ScopeName: pointer.StringPtr("tenant_a"), CollectionName: pointer.StringPtr("users")},
+			{Name: "idx", ScopeNames: []string{"tenant_*"}, CollectionName: pointer.StringPtr("users")},
+		}
+
+		// Act
+
+		_, err := ExpandKeyspaces(indices, collections)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("GetMissingKeyspaces", func() {
+
+	collections := map[string][]string{
+		"_default":  {"_default"},
+		"inventory": {"airline"},
+	}
+
+	It("should return collections which do not exist", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "default"},
+			{Name: "airline", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("airline")},
+			{Name: "route", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("route")},
+			{Name: "route2", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("route")},
+			{Name: "hotel", ScopeName: pointer.StringPtr("lodging"), CollectionName: pointer.StringPtr("hotel")},
+		}
+
+		// Act
+
+		result := GetMissingKeyspaces(indices, collections)
+
+		// Assert
+
+		Expect(result).To(Equal([]string{"inventory.route", "lodging.hotel"}))
+	})
+})
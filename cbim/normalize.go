@@ -0,0 +1,156 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/sqlpp"
+)
+
+// Returns true if two indices have the same definition once normalized: the same keyspace, index keys, condition,
+// and partition keys. Names, replicas, and other options aren't compared.
+func IndexDefinitionsEqual(a couchbasev1beta1.GlobalSecondaryIndex, b couchbasev1beta1.GlobalSecondaryIndex) bool {
+	identifierA, identifierB := GetIndexIdentifier(a), GetIndexIdentifier(b)
+	if identifierA.ScopeName != identifierB.ScopeName || identifierA.CollectionName != identifierB.CollectionName {
+		return false
+	}
+
+	if !listsEqual(GetIndexKey(a), GetIndexKey(b), sqlpp.IndexKeysEqual) {
+		return false
+	}
+
+	if !conditionsEqual(a.Condition, b.Condition) {
+		return false
+	}
+
+	if (a.Partition == nil) != (b.Partition == nil) {
+		return false
+	}
+
+	return a.Partition == nil || listsEqual(a.Partition.Expressions, b.Partition.Expressions, sqlpp.ExpressionsEqual)
+}
+
+// Returns a copy of the index with its index keys, condition, and partition keys normalized, so
+// that indices which differ only in the formatting of expressions are equal. Expressions which can't be parsed are
+// left unchanged.
+func NormalizeIndex(gsi couchbasev1beta1.GlobalSecondaryIndex) couchbasev1beta1.GlobalSecondaryIndex {
+	if gsi.IndexKey != nil {
+		gsi.IndexKey = normalizeList(gsi.IndexKey, sqlpp.NormalizeIndexKey)
+	}
+	if gsi.Condition != nil {
+		condition := normalize(*gsi.Condition, sqlpp.NormalizeExpression)
+		gsi.Condition = &condition
+	}
+	if gsi.Partition != nil {
+		partition := *gsi.Partition
+		partition.Expressions = normalizeList(partition.Expressions, sqlpp.NormalizeExpression)
+		gsi.Partition = &partition
+	}
+
+	return gsi
+}
+
+// Normalizes the expressions within a couchbase-index-manager spec, so that specs which differ only in the
+// formatting of expressions are equal. Documents which can't be read are left unchanged.
+func NormalizeIndexSpecs(spec string) string {
+	var sb strings.Builder
+
+	for _, document := range splitYamlDocuments(spec) {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+
+		indexSpec := IndexSpec{}
+		if err := yaml.UnmarshalStrict([]byte(document), &indexSpec); err != nil {
+			if sb.Len() > 0 {
+				sb.WriteString("---\n")
+			}
+			sb.WriteString(strings.TrimRight(document, "\n") + "\n")
+			continue
+		}
+
+		if indexSpec.IndexKey != nil {
+			indexKey := normalizeList(*indexSpec.IndexKey, sqlpp.NormalizeIndexKey)
+			indexSpec.IndexKey = &indexKey
+		}
+		if indexSpec.Condition != nil {
+			condition := normalize(*indexSpec.Condition, sqlpp.NormalizeExpression)
+			indexSpec.Condition = &condition
+		}
+		if indexSpec.Partition != nil {
+			indexSpec.Partition.Expressions = normalizeList(indexSpec.Partition.Expressions, sqlpp.NormalizeExpression)
+		}
+
+		if err := addIndexSpec(&sb, indexSpec); err != nil {
+			return spec
+		}
+	}
+
+	return sb.String()
+}
+
+func conditionsEqual(a *string, b *string) bool {
+	var conditionA, conditionB string
+	if a != nil {
+		conditionA = strings.TrimSpace(*a)
+	}
+	if b != nil {
+		conditionB = strings.TrimSpace(*b)
+	}
+
+	if conditionA == "" || conditionB == "" {
+		return conditionA == conditionB
+	}
+
+	return sqlpp.ExpressionsEqual(conditionA, conditionB)
+}
+
+func listsEqual(a []string, b []string, equal func(string, string) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !equal(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Normalizes a value, leaving it unchanged if it can't be parsed
+func normalize(value string, normalizer func(string) (string, error)) string {
+	if normalized, err := normalizer(value); err == nil {
+		return normalized
+	}
+
+	return value
+}
+
+func normalizeList(values []string, normalizer func(string) (string, error)) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = normalize(value, normalizer)
+	}
+
+	return result
+}
@@ -0,0 +1,163 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("IndexDefinitionsEqual", func() {
+
+	It("should ignore formatting and names", func() {
+		// Arrange
+
+		a := couchbasev1beta1.GlobalSecondaryIndex{
+			Name:      "def_city",
+			IndexKey:  []string{"city", "country DESC"},
+			Condition: pointer.StringPtr("type = 'hotel'"),
+		}
+		b := couchbasev1beta1.GlobalSecondaryIndex{
+			Name:      "adv_city_country",
+			IndexKey:  []string{"`city`", "(`country`) desc"},
+			Condition: pointer.StringPtr("(`type` = \"hotel\")"),
+		}
+
+		// Act
+
+		result := IndexDefinitionsEqual(a, b)
+
+		// Assert
+
+		Expect(result).To(BeTrue())
+	})
+
+	It("should compare structured keys with index keys", func() {
+		// Arrange
+
+		a := couchbasev1beta1.GlobalSecondaryIndex{
+			Name:     "def_city",
+			IndexKey: []string{"city"},
+		}
+		b := couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "def_city",
+			Keys: []couchbasev1beta1.GlobalSecondaryIndexKey{
+				{Expression: pointer.StringPtr("`city`")},
+			},
+		}
+
+		// Act
+
+		result := IndexDefinitionsEqual(a, b)
+
+		// Assert
+
+		Expect(result).To(BeTrue())
+	})
+
+	DescribeTable("should detect differences",
+		func(b couchbasev1beta1.GlobalSecondaryIndex) {
+			// Arrange
+
+			a := couchbasev1beta1.GlobalSecondaryIndex{
+				Name:      "def_city",
+				IndexKey:  []string{"city"},
+				Condition: pointer.StringPtr("type = 'hotel'"),
+			}
+
+			// Act
+
+			result := IndexDefinitionsEqual(a, b)
+
+			// Assert
+
+			Expect(result).To(BeFalse())
+		},
+		Entry("keys", couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "def_city", IndexKey: []string{"country"}, Condition: pointer.StringPtr("type = 'hotel'"),
+		}),
+		Entry("direction", couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "def_city", IndexKey: []string{"city DESC"}, Condition: pointer.StringPtr("type = 'hotel'"),
+		}),
+		Entry("condition", couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "def_city", IndexKey: []string{"city"}, Condition: pointer.StringPtr("type = 'airline'"),
+		}),
+		Entry("no condition", couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "def_city", IndexKey: []string{"city"},
+		}),
+		Entry("keyspace", couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "def_city", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("hotel"),
+			IndexKey: []string{"city"}, Condition: pointer.StringPtr("type = 'hotel'"),
+		}),
+		Entry("partition", couchbasev1beta1.GlobalSecondaryIndex{
+			Name: "def_city", IndexKey: []string{"city"}, Condition: pointer.StringPtr("type = 'hotel'"),
+			Partition: &couchbasev1beta1.GlobalSecondaryIndexPartition{Expressions: []string{"meta().id"}},
+		}),
+	)
+})
+
+var _ = Describe("NormalizeIndex", func() {
+
+	It("should normalize expressions without modifying the original", func() {
+		// Arrange
+
+		gsi := couchbasev1beta1.GlobalSecondaryIndex{
+			Name:      "def_city",
+			IndexKey:  []string{"city", "country DESC"},
+			Condition: pointer.StringPtr("type = 'hotel'"),
+			Partition: &couchbasev1beta1.GlobalSecondaryIndexPartition{
+				Expressions: []string{"city"},
+			},
+		}
+
+		// Act
+
+		result := NormalizeIndex(gsi)
+
+		// Assert
+
+		Expect(result.Name).To(Equal("def_city"))
+		Expect(result.IndexKey).To(Equal([]string{"`city`", "`country` DESC"}))
+		Expect(*result.Condition).To(Equal("(`type` = \"hotel\")"))
+		Expect(result.Partition.Expressions).To(Equal([]string{"`city`"}))
+		Expect(gsi.IndexKey).To(Equal([]string{"city", "country DESC"}))
+		Expect(*gsi.Condition).To(Equal("type = 'hotel'"))
+		Expect(gsi.Partition.Expressions).To(Equal([]string{"city"}))
+	})
+})
+
+var _ = Describe("NormalizeIndexSpecs", func() {
+
+	It("should normalize expressions", func() {
+		// Arrange
+
+		spec := "index_key:\n- city\n- country DESC\nname: def_city\ncondition: type = 'hotel'\n" +
+			"---\nname: def_old\nlifecycle:\n  drop: true\n"
+
+		// Act
+
+		result := NormalizeIndexSpecs(spec)
+
+		// Assert
+
+		Expect(result).To(Equal("condition: (`type` = \"hotel\")\n" +
+			"index_key:\n- '`city`'\n- '`country` DESC'\nname: def_city\n" +
+			"---\nlifecycle:\n  drop: true\nname: def_old\n"))
+	})
+
+	It("should leave unknown documents unchanged", func() {
+		// Arrange
+
+		spec := "type: nodeMap\nmap:\n  a: b\n"
+
+		// Act
+
+		result := NormalizeIndexSpecs(spec)
+
+		// Assert
+
+		Expect(result).To(Equal(spec))
+	})
+})
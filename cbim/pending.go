@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Determines which removed indices are waiting for the drop grace period to elapse. Pending drops retain their
+// original deadline, newly removed indices receive a deadline based on the grace period, and indices which have
+// been re-added to the index set are no longer pending.
+func GetPendingDrops(indexSet *couchbasev1beta1.CouchbaseIndexSet, indices []couchbasev1beta1.GlobalSecondaryIndex, now time.Time) []couchbasev1beta1.PendingIndexDrop {
+	gracePeriod := indexSet.Spec.DropGracePeriodSeconds
+	if indexSet.GetDeletionTimestamp() != nil || gracePeriod == nil || *gracePeriod <= 0 {
+		return nil
+	}
+
+	definedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+	for _, gsi := range indices {
+		definedIndexes[GetIndexIdentifier(gsi)] = true
+	}
+
+	existingDeadlines := getPendingDropDeadlines(indexSet)
+	protectedIndexes := GetProtectedIndexes(indexSet, indices)
+
+	result := []couchbasev1beta1.PendingIndexDrop{}
+	for _, index := range indexSet.Status.Indices {
+		if identifier, err := ParseIndexIdentifierString(index); err == nil {
+			if definedIndexes[identifier] || protectedIndexes[identifier] {
+				continue
+			}
+
+			deadline, ok := existingDeadlines[identifier]
+			if !ok {
+				deadline = now.Add(time.Duration(*gracePeriod) * time.Second)
+			}
+
+			result = append(result, couchbasev1beta1.PendingIndexDrop{
+				Name:      identifier.ToString(),
+				DropAfter: metav1.NewTime(deadline),
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func getPendingDropDeadlines(indexSet *couchbasev1beta1.CouchbaseIndexSet) map[GlobalSecondaryIndexIdentifier]time.Time {
+	deadlines := map[GlobalSecondaryIndexIdentifier]time.Time{}
+
+	for _, pendingDrop := range indexSet.Status.PendingDrops {
+		if identifier, err := ParseIndexIdentifierString(pendingDrop.Name); err == nil {
+			deadlines[identifier] = pendingDrop.DropAfter.Time
+		}
+	}
+
+	return deadlines
+}
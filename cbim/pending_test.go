@@ -0,0 +1,147 @@
+package cbim
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("GetPendingDrops", func() {
+
+	now := time.Date(2021, 8, 23, 0, 0, 0, 0, time.UTC)
+
+	It("should return nothing without a grace period", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"removed"},
+			},
+		}
+
+		// Act
+
+		result := GetPendingDrops(&indexSet, indexSet.Spec.Indices, now)
+
+		// Assert
+
+		Expect(result).To(BeEmpty())
+	})
+
+	It("should add removed indices with a deadline", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Indices: []couchbasev1beta1.GlobalSecondaryIndex{
+					{Name: "kept"},
+				},
+				DropGracePeriodSeconds: pointer.Int64Ptr(3600),
+			},
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"kept", "removed"},
+			},
+		}
+
+		// Act
+
+		result := GetPendingDrops(&indexSet, indexSet.Spec.Indices, now)
+
+		// Assert
+
+		Expect(result).To(Equal([]couchbasev1beta1.PendingIndexDrop{
+			{Name: "removed", DropAfter: metav1.NewTime(now.Add(time.Hour))},
+		}))
+	})
+
+	It("should retain existing deadlines", func() {
+		// Arrange
+
+		deadline := metav1.NewTime(now.Add(time.Minute))
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				DropGracePeriodSeconds: pointer.Int64Ptr(3600),
+			},
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"removed"},
+				PendingDrops: []couchbasev1beta1.PendingIndexDrop{
+					{Name: "removed", DropAfter: deadline},
+				},
+			},
+		}
+
+		// Act
+
+		result := GetPendingDrops(&indexSet, indexSet.Spec.Indices, now)
+
+		// Assert
+
+		Expect(result).To(Equal([]couchbasev1beta1.PendingIndexDrop{
+			{Name: "removed", DropAfter: deadline},
+		}))
+	})
+
+	It("should cancel drops for re-added indices", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Indices: []couchbasev1beta1.GlobalSecondaryIndex{
+					{Name: "readded"},
+				},
+				DropGracePeriodSeconds: pointer.Int64Ptr(3600),
+			},
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"readded"},
+				PendingDrops: []couchbasev1beta1.PendingIndexDrop{
+					{Name: "readded", DropAfter: metav1.NewTime(now)},
+				},
+			},
+		}
+
+		// Act
+
+		result := GetPendingDrops(&indexSet, indexSet.Spec.Indices, now)
+
+		// Assert
+
+		Expect(result).To(BeEmpty())
+	})
+})
+
+var _ = Describe("GenerateYaml with pending drops", func() {
+
+	It("should hold drops until the deadline", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"expired", "pending"},
+				PendingDrops: []couchbasev1beta1.PendingIndexDrop{
+					{Name: "expired", DropAfter: metav1.NewTime(time.Now().Add(-time.Minute))},
+					{Name: "pending", DropAfter: metav1.NewTime(time.Now().Add(time.Hour))},
+				},
+			},
+		}
+
+		// Act
+
+		result := GenerateResult{}
+		_, err := GenerateYaml(&indexSet, indexSet.Spec.Indices, &result)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.DeletingIndexes).To(Equal([]GlobalSecondaryIndexIdentifier{
+			{Name: "expired", ScopeName: "_default", CollectionName: "_default"},
+		}))
+		Expect(result.PendingIndexes).To(Equal([]GlobalSecondaryIndexIdentifier{
+			{Name: "pending", ScopeName: "_default", CollectionName: "_default"},
+		}))
+	})
+})
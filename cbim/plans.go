@@ -0,0 +1,46 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import "strings"
+
+// Determines if a query plan uses the expected index, given the indices it scans. The expected index is in
+// "scope.collection.name" format, or just "name" to match an index of that name in any collection.
+func PlanUsesIndex(used []GlobalSecondaryIndexIdentifier, expectedIndex string) bool {
+	if !strings.Contains(expectedIndex, ".") {
+		for _, identifier := range used {
+			if identifier.Name == expectedIndex {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	expected, err := ParseIndexIdentifierString(expectedIndex)
+	if err != nil {
+		return false
+	}
+
+	for _, identifier := range used {
+		if identifier == expected {
+			return true
+		}
+	}
+
+	return false
+}
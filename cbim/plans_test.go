@@ -0,0 +1,33 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PlanUsesIndex", func() {
+
+	used := []GlobalSecondaryIndexIdentifier{
+		{ScopeName: "inventory", CollectionName: "hotel", Name: "def_city"},
+		{ScopeName: "_default", CollectionName: "_default", Name: "def_type"},
+	}
+
+	DescribeTable("should match the expected index",
+		func(expectedIndex string, expected bool) {
+			// Act
+
+			result := PlanUsesIndex(used, expectedIndex)
+
+			// Assert
+
+			Expect(result).To(Equal(expected))
+		},
+		Entry("name in any collection", "def_city", true),
+		Entry("qualified name", "inventory.hotel.def_city", true),
+		Entry("qualified name in another collection", "inventory.airline.def_city", false),
+		Entry("default collection", "_default._default.def_type", true),
+		Entry("unused index", "def_name", false),
+		Entry("invalid name", "inventory.def_city", false),
+	)
+})
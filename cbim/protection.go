@@ -0,0 +1,76 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"sort"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Determines which indices are protected from being dropped. Defined indices are protected based on their
+// DropProtection flag. Indices which were previously protected remain protected after they are removed from the
+// index set, until they are listed in LiftDropProtection or are no longer managed by the index set.
+func GetProtectedIndexes(indexSet *couchbasev1beta1.CouchbaseIndexSet, indices []couchbasev1beta1.GlobalSecondaryIndex) map[GlobalSecondaryIndexIdentifier]bool {
+	protectedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+
+	definedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+	for _, gsi := range indices {
+		identifier := GetIndexIdentifier(gsi)
+		definedIndexes[identifier] = true
+
+		if gsi.DropProtection != nil && *gsi.DropProtection {
+			protectedIndexes[identifier] = true
+		}
+	}
+
+	managedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+	for _, index := range indexSet.Status.Indices {
+		if identifier, err := ParseIndexIdentifierString(index); err == nil {
+			managedIndexes[identifier] = true
+		}
+	}
+
+	liftedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+	for _, index := range indexSet.Spec.LiftDropProtection {
+		if identifier, err := ParseIndexIdentifierString(index); err == nil {
+			liftedIndexes[identifier] = true
+		}
+	}
+
+	for _, index := range indexSet.Status.ProtectedIndices {
+		if identifier, err := ParseIndexIdentifierString(index); err == nil {
+			if !definedIndexes[identifier] && managedIndexes[identifier] && !liftedIndexes[identifier] {
+				protectedIndexes[identifier] = true
+			}
+		}
+	}
+
+	return protectedIndexes
+}
+
+// Returns a sorted list of index identifier strings
+func ToSortedStrings(identifiers map[GlobalSecondaryIndexIdentifier]bool) []string {
+	result := make([]string, 0, len(identifiers))
+	for identifier := range identifiers {
+		result = append(result, identifier.ToString())
+	}
+
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,173 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("GetProtectedIndexes", func() {
+
+	It("should protect indices in the spec with drop protection", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Indices: []couchbasev1beta1.GlobalSecondaryIndex{
+					{Name: "protected", DropProtection: pointer.BoolPtr(true)},
+					{Name: "unprotected"},
+				},
+			},
+		}
+
+		// Act
+
+		result := GetProtectedIndexes(&indexSet, indexSet.Spec.Indices)
+
+		// Assert
+
+		Expect(ToSortedStrings(result)).To(Equal([]string{"protected"}))
+	})
+
+	It("should retain protection after removal from the spec", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices:          []string{"protected"},
+				ProtectedIndices: []string{"protected"},
+			},
+		}
+
+		// Act
+
+		result := GetProtectedIndexes(&indexSet, indexSet.Spec.Indices)
+
+		// Assert
+
+		Expect(ToSortedStrings(result)).To(Equal([]string{"protected"}))
+	})
+
+	It("should lift protection when listed", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				LiftDropProtection: []string{"scope.collection.protected"},
+			},
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices:          []string{"scope.collection.protected"},
+				ProtectedIndices: []string{"scope.collection.protected"},
+			},
+		}
+
+		// Act
+
+		result := GetProtectedIndexes(&indexSet, indexSet.Spec.Indices)
+
+		// Assert
+
+		Expect(result).To(BeEmpty())
+	})
+
+	It("should lift protection when disabled in the spec", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Indices: []couchbasev1beta1.GlobalSecondaryIndex{
+					{Name: "protected", DropProtection: pointer.BoolPtr(false)},
+				},
+			},
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices:          []string{"protected"},
+				ProtectedIndices: []string{"protected"},
+			},
+		}
+
+		// Act
+
+		result := GetProtectedIndexes(&indexSet, indexSet.Spec.Indices)
+
+		// Assert
+
+		Expect(result).To(BeEmpty())
+	})
+
+	It("should forget protection for unmanaged indices", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				ProtectedIndices: []string{"protected"},
+			},
+		}
+
+		// Act
+
+		result := GetProtectedIndexes(&indexSet, indexSet.Spec.Indices)
+
+		// Assert
+
+		Expect(result).To(BeEmpty())
+	})
+})
+
+var _ = Describe("GenerateYaml", func() {
+
+	It("should not drop protected indices", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices:          []string{"protected", "unprotected"},
+				ProtectedIndices: []string{"protected"},
+			},
+		}
+
+		// Act
+
+		result := GenerateResult{}
+		yaml, err := GenerateYaml(&indexSet, indexSet.Spec.Indices, &result)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.DeletingIndexes).To(Equal([]GlobalSecondaryIndexIdentifier{
+			{Name: "unprotected", ScopeName: "_default", CollectionName: "_default"},
+		}))
+		Expect(result.ProtectedIndexes).To(Equal([]GlobalSecondaryIndexIdentifier{
+			{Name: "protected", ScopeName: "_default", CollectionName: "_default"},
+		}))
+		Expect(yaml).NotTo(ContainSubstring("name: protected"))
+	})
+
+	It("should not drop in use indices", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices:      []string{"inuse", "unused"},
+				InUseIndices: []string{"inuse"},
+			},
+		}
+
+		// Act
+
+		result := GenerateResult{}
+		yaml, err := GenerateYaml(&indexSet, indexSet.Spec.Indices, &result)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.DeletingIndexes).To(Equal([]GlobalSecondaryIndexIdentifier{
+			{Name: "unused", ScopeName: "_default", CollectionName: "_default"},
+		}))
+		Expect(result.InUseIndexes).To(Equal([]GlobalSecondaryIndexIdentifier{
+			{Name: "inuse", ScopeName: "_default", CollectionName: "_default"},
+		}))
+		Expect(yaml).NotTo(ContainSubstring("name: inuse"))
+	})
+})
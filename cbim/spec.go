@@ -18,6 +18,7 @@ package cbim
 
 import (
 	"strings"
+	"time"
 
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/yaml"
@@ -25,38 +26,116 @@ import (
 	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
 )
 
-func GenerateYaml(indexSet *couchbasev1beta1.CouchbaseIndexSet, deletingIndexes *[]GlobalSecondaryIndexIdentifier) (string, error) {
-	var sb strings.Builder
+// Details about the indices affected by a generated index spec
+type GenerateResult struct {
+	// Indices which will be dropped
+	DeletingIndexes []GlobalSecondaryIndexIdentifier
+	// Indices which were removed but will not be dropped because they are drop protected
+	ProtectedIndexes []GlobalSecondaryIndexIdentifier
+	// Indices which were removed but will not be dropped until the drop grace period elapses
+	PendingIndexes []GlobalSecondaryIndexIdentifier
+	// Indices which were removed but will not be dropped because they were recently used
+	InUseIndexes []GlobalSecondaryIndexIdentifier
+}
 
-	definedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+// Generates the couchbase-index-manager spec for a set of indices, including drops for any removed indices
+func GenerateYaml(indexSet *couchbasev1beta1.CouchbaseIndexSet, indices []couchbasev1beta1.GlobalSecondaryIndex, result *GenerateResult) (string, error) {
+	var sb strings.Builder
 
 	if indexSet.GetDeletionTimestamp() == nil {
 		// Only create indices if we're not deleting the index set
-		// If we are deleting, this will leave definedIndexNames empty so all indices are deleted
+		// If we are deleting, GetRemovedIndexes will treat all indices as removed
 
-		for _, gsi := range indexSet.Spec.Indices {
-			definedIndexes[GetIndexIdentifier(gsi)] = true
+		for _, gsi := range indices {
+			spec, err := createIndexSpec(&gsi)
+			if err != nil {
+				return "", err
+			}
 
-			if err := addIndexSpec(&sb, createIndexSpec(&gsi)); err != nil {
+			if err := addIndexSpec(&sb, spec); err != nil {
 				return "", err
 			}
 		}
 	}
 
-	*deletingIndexes = []GlobalSecondaryIndexIdentifier{}
+	*result = GetRemovedIndexes(indexSet, indices)
+
+	if len(indexSet.Status.InUseIndices) > 0 {
+		// Hold back drops of indices which were found to be in use
+		inUseIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+		for _, index := range indexSet.Status.InUseIndices {
+			if indexIdentifier, err := ParseIndexIdentifierString(index); err == nil {
+				inUseIndexes[indexIdentifier] = true
+			}
+		}
+
+		deletingIndexes := []GlobalSecondaryIndexIdentifier{}
+		for _, indexIdentifier := range result.DeletingIndexes {
+			if inUseIndexes[indexIdentifier] {
+				result.InUseIndexes = append(result.InUseIndexes, indexIdentifier)
+			} else {
+				deletingIndexes = append(deletingIndexes, indexIdentifier)
+			}
+		}
+		result.DeletingIndexes = deletingIndexes
+	}
+
+	for _, indexIdentifier := range result.DeletingIndexes {
+		if err := addIndexSpec(&sb, createIndexDeleteSpec(indexIdentifier)); err != nil {
+			return "", err
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// Determines which managed indices have been removed from the index set, and whether they are ready to be dropped.
+// This does not consider whether the indices are in use.
+func GetRemovedIndexes(indexSet *couchbasev1beta1.CouchbaseIndexSet, indices []couchbasev1beta1.GlobalSecondaryIndex) GenerateResult {
+	definedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+	if indexSet.GetDeletionTimestamp() == nil {
+		// If we are deleting, this will leave definedIndexes empty so all indices are removed
+		for _, gsi := range indices {
+			definedIndexes[GetIndexIdentifier(gsi)] = true
+		}
+	}
+
+	protectedIndexes := GetProtectedIndexes(indexSet, indices)
+
+	pendingDrops := map[GlobalSecondaryIndexIdentifier]time.Time{}
+	if indexSet.GetDeletionTimestamp() == nil {
+		// Drops are never delayed when deleting the index set
+		pendingDrops = getPendingDropDeadlines(indexSet)
+	}
+	now := time.Now()
+
+	result := GenerateResult{
+		DeletingIndexes:  []GlobalSecondaryIndexIdentifier{},
+		ProtectedIndexes: []GlobalSecondaryIndexIdentifier{},
+		PendingIndexes:   []GlobalSecondaryIndexIdentifier{},
+		InUseIndexes:     []GlobalSecondaryIndexIdentifier{},
+	}
 	for _, index := range indexSet.Status.Indices {
 		if indexIdentifier, err := ParseIndexIdentifierString(index); err == nil {
 			if !definedIndexes[indexIdentifier] {
-				*deletingIndexes = append(*deletingIndexes, indexIdentifier)
+				if protectedIndexes[indexIdentifier] {
+					// Leave the index in place, it will remain in the status until protection is lifted
+					result.ProtectedIndexes = append(result.ProtectedIndexes, indexIdentifier)
+					continue
+				}
 
-				if err := addIndexSpec(&sb, createIndexDeleteSpec(indexIdentifier)); err != nil {
-					return "", err
+				if deadline, ok := pendingDrops[indexIdentifier]; ok && now.Before(deadline) {
+					// Leave the index in place until the grace period elapses
+					result.PendingIndexes = append(result.PendingIndexes, indexIdentifier)
+					continue
 				}
+
+				result.DeletingIndexes = append(result.DeletingIndexes, indexIdentifier)
 			}
 		}
 	}
 
-	return sb.String(), nil
+	return result
 }
 
 func addIndexSpec(sb *strings.Builder, spec IndexSpec) error {
@@ -73,17 +152,25 @@ func addIndexSpec(sb *strings.Builder, spec IndexSpec) error {
 	return nil
 }
 
-func createIndexSpec(gsi *couchbasev1beta1.GlobalSecondaryIndex) IndexSpec {
-	return IndexSpec{
+func createIndexSpec(gsi *couchbasev1beta1.GlobalSecondaryIndex) (IndexSpec, error) {
+	if err := ValidateIndex(*gsi); err != nil {
+		return IndexSpec{}, err
+	}
+
+	indexKey := GetIndexKey(*gsi)
+
+	spec := IndexSpec{
 		Name:               gsi.Name,
 		Scope:              gsi.ScopeName,
 		Collection:         gsi.CollectionName,
-		IndexKey:           &gsi.IndexKey,
+		IndexKey:           &indexKey,
 		Condition:          gsi.Condition,
 		NumReplicas:        gsi.NumReplicas,
 		RetainDeletedXattr: gsi.RetainDeletedXAttr,
 		Partition:          mapPartition(gsi.Partition),
 	}
+
+	return spec, nil
 }
 
 func mapPartition(partition *couchbasev1beta1.GlobalSecondaryIndexPartition) *PartitionSpec {
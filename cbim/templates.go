@@ -0,0 +1,204 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var (
+	templateParameterRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	indexNameRegex         = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9#_\-]*$`)
+)
+
+// Expands the templates instantiated by an index set into concrete indices, returning them along with the indices
+// defined directly on the index set. Templates are looked up by name.
+func ExpandIndices(indexSet *couchbasev1beta1.CouchbaseIndexSet,
+	templates map[string]*couchbasev1beta1.CouchbaseIndexTemplate) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+
+	result := make([]couchbasev1beta1.GlobalSecondaryIndex, 0, len(indexSet.Spec.Indices))
+	result = append(result, indexSet.Spec.Indices...)
+
+	for _, instance := range indexSet.Spec.Templates {
+		template, ok := templates[instance.TemplateName]
+		if !ok {
+			return nil, fmt.Errorf("index template %s is not found", instance.TemplateName)
+		}
+
+		indices, err := instantiateTemplate(template, instance)
+		if err != nil {
+			return nil, fmt.Errorf("index template %s: %w", instance.TemplateName, err)
+		}
+
+		result = append(result, indices...)
+	}
+
+	if err := checkDuplicateIndexes(result); err != nil {
+		if len(indexSet.Spec.Templates) > 0 {
+			return nil, fmt.Errorf("%w, use a parameter in the template's index names to instantiate it more than once", err)
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func checkDuplicateIndexes(indices []couchbasev1beta1.GlobalSecondaryIndex) error {
+	definedIndexes := map[GlobalSecondaryIndexIdentifier]bool{}
+	for _, gsi := range indices {
+		if IsFanOutIndex(gsi) {
+			// Checked after expansion
+			continue
+		}
+
+		identifier := GetIndexIdentifier(gsi)
+		if definedIndexes[identifier] {
+			return fmt.Errorf("index %s is defined more than once", identifier.ToString())
+		}
+
+		definedIndexes[identifier] = true
+	}
+
+	return nil
+}
+
+// Returns the revisions of the templates instantiated by an index set, sorted by name
+func GetAppliedTemplates(indexSet *couchbasev1beta1.CouchbaseIndexSet,
+	templates map[string]*couchbasev1beta1.CouchbaseIndexTemplate) []couchbasev1beta1.AppliedIndexTemplate {
+
+	names := map[string]bool{}
+	for _, instance := range indexSet.Spec.Templates {
+		names[instance.TemplateName] = true
+	}
+
+	result := []couchbasev1beta1.AppliedIndexTemplate{}
+	for name := range names {
+		if template, ok := templates[name]; ok {
+			result = append(result, couchbasev1beta1.AppliedIndexTemplate{
+				Name:       name,
+				Generation: template.Generation,
+			})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func instantiateTemplate(template *couchbasev1beta1.CouchbaseIndexTemplate,
+	instance couchbasev1beta1.CouchbaseIndexTemplateInstance) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+
+	parameters := map[string]string{}
+	for _, parameter := range template.Spec.Parameters {
+		if value, ok := instance.Parameters[parameter.Name]; ok {
+			parameters[parameter.Name] = value
+		} else if parameter.Default != nil {
+			parameters[parameter.Name] = *parameter.Default
+		} else {
+			return nil, fmt.Errorf("parameter %s is required", parameter.Name)
+		}
+	}
+
+	for name := range instance.Parameters {
+		if _, ok := parameters[name]; !ok {
+			return nil, fmt.Errorf("parameter %s is not defined", name)
+		}
+	}
+
+	var substitutionErr error
+	substitute := func(value string) string {
+		return templateParameterRegex.ReplaceAllStringFunc(value, func(match string) string {
+			name := templateParameterRegex.FindStringSubmatch(match)[1]
+
+			if value, ok := parameters[name]; ok {
+				return value
+			}
+
+			substitutionErr = fmt.Errorf("parameter %s is not defined", name)
+			return match
+		})
+	}
+
+	result := make([]couchbasev1beta1.GlobalSecondaryIndex, len(template.Spec.Indices))
+	for i, templateIndex := range template.Spec.Indices {
+		gsi := templateIndex.DeepCopy()
+
+		if instance.ScopeName != nil {
+			gsi.ScopeName = instance.ScopeName
+			gsi.ScopeNames = nil
+		}
+		if instance.CollectionName != nil {
+			gsi.CollectionName = instance.CollectionName
+			gsi.CollectionNames = nil
+		}
+
+		gsi.Name = substitute(gsi.Name)
+		for j, v := range gsi.IndexKey {
+			gsi.IndexKey[j] = substitute(v)
+		}
+		for j := range gsi.Keys {
+			substituteKey(&gsi.Keys[j], substitute)
+		}
+		if gsi.Condition != nil {
+			condition := substitute(*gsi.Condition)
+			gsi.Condition = &condition
+		}
+		if gsi.Partition != nil {
+			for j, v := range gsi.Partition.Expressions {
+				gsi.Partition.Expressions[j] = substitute(v)
+			}
+		}
+
+		if substitutionErr != nil {
+			return nil, substitutionErr
+		}
+
+		if !indexNameRegex.MatchString(gsi.Name) {
+			return nil, fmt.Errorf("index name %s is invalid", gsi.Name)
+		}
+
+		result[i] = *gsi
+	}
+
+	return result, nil
+}
+
+func substituteKey(key *couchbasev1beta1.GlobalSecondaryIndexKey, substitute func(string) string) {
+	if key.Expression != nil {
+		expression := substitute(*key.Expression)
+		key.Expression = &expression
+	}
+
+	if key.Array != nil {
+		key.Array.In = substitute(key.Array.In)
+		if key.Array.When != nil {
+			when := substitute(*key.Array.When)
+			key.Array.When = &when
+		}
+		for i, v := range key.Array.FlattenKeys {
+			key.Array.FlattenKeys[i].Expression = substitute(v.Expression)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package cbim
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("ExpandIndices", func() {
+
+	template := couchbasev1beta1.CouchbaseIndexTemplate{
+		Spec: couchbasev1beta1.CouchbaseIndexTemplateSpec{
+			Parameters: []couchbasev1beta1.CouchbaseIndexTemplateParameter{
+				{Name: "type"},
+				{Name: "dateField", Default: pointer.StringPtr("createdAt")},
+			},
+			Indices: []couchbasev1beta1.GlobalSecondaryIndex{
+				{
+					Name:      "by_type_and_date",
+					IndexKey:  []string{"type", "${dateField}"},
+					Condition: pointer.StringPtr("type = '${type}'"),
+				},
+			},
+		},
+	}
+	templates := map[string]*couchbasev1beta1.CouchbaseIndexTemplate{
+		"template": &template,
+	}
+
+	It("should substitute parameters", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Indices: []couchbasev1beta1.GlobalSecondaryIndex{
+					{Name: "direct", IndexKey: []string{"id"}},
+				},
+				Templates: []couchbasev1beta1.CouchbaseIndexTemplateInstance{
+					{
+						TemplateName:   "template",
+						ScopeName:      pointer.StringPtr("inventory"),
+						CollectionName: pointer.StringPtr("airline"),
+						Parameters: map[string]string{
+							"type": "airline",
+						},
+					},
+				},
+			},
+		}
+
+		// Act
+
+		result, err := ExpandIndices(&indexSet, templates)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal([]couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "direct", IndexKey: []string{"id"}},
+			{
+				Name:           "by_type_and_date",
+				ScopeName:      pointer.StringPtr("inventory"),
+				CollectionName: pointer.StringPtr("airline"),
+				IndexKey:       []string{"type", "createdAt"},
+				Condition:      pointer.StringPtr("type = 'airline'"),
+			},
+		}))
+		Expect(template.Spec.Indices[0].IndexKey).To(Equal([]string{"type", "${dateField}"}))
+	})
+
+	It("should error on missing parameters", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Templates: []couchbasev1beta1.CouchbaseIndexTemplateInstance{
+					{TemplateName: "template"},
+				},
+			},
+		}
+
+		// Act
+
+		_, err := ExpandIndices(&indexSet, templates)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should error on missing templates", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Templates: []couchbasev1beta1.CouchbaseIndexTemplateInstance{
+					{TemplateName: "missing"},
+				},
+			},
+		}
+
+		// Act
+
+		_, err := ExpandIndices(&indexSet, templates)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should error on duplicate indices", func() {
+		// Arrange
+
+		instance := couchbasev1beta1.CouchbaseIndexTemplateInstance{
+			TemplateName: "template",
+			Parameters: map[string]string{
+				"type": "airline",
+			},
+		}
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Templates: []couchbasev1beta1.CouchbaseIndexTemplateInstance{instance, instance},
+			},
+		}
+
+		// Act
+
+		_, err := ExpandIndices(&indexSet, templates)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should substitute parameters in index names", func() {
+		// Arrange
+
+		namedTemplate := couchbasev1beta1.CouchbaseIndexTemplate{
+			Spec: couchbasev1beta1.CouchbaseIndexTemplateSpec{
+				Parameters: []couchbasev1beta1.CouchbaseIndexTemplateParameter{
+					{Name: "type"},
+				},
+				Indices: []couchbasev1beta1.GlobalSecondaryIndex{
+					{
+						Name:      "by_${type}",
+						IndexKey:  []string{"id"},
+						Condition: pointer.StringPtr("type = '${type}'"),
+					},
+				},
+			},
+		}
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Templates: []couchbasev1beta1.CouchbaseIndexTemplateInstance{
+					{TemplateName: "named", Parameters: map[string]string{"type": "airline"}},
+					{TemplateName: "named", Parameters: map[string]string{"type": "hotel"}},
+				},
+			},
+		}
+
+		// Act
+
+		result, err := ExpandIndices(&indexSet, map[string]*couchbasev1beta1.CouchbaseIndexTemplate{
+			"named": &namedTemplate,
+		})
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Name).To(Equal("by_airline"))
+		Expect(result[1].Name).To(Equal("by_hotel"))
+	})
+
+	It("should error on invalid index names", func() {
+		// Arrange
+
+		namedTemplate := couchbasev1beta1.CouchbaseIndexTemplate{
+			Spec: couchbasev1beta1.CouchbaseIndexTemplateSpec{
+				Parameters: []couchbasev1beta1.CouchbaseIndexTemplateParameter{
+					{Name: "type"},
+				},
+				Indices: []couchbasev1beta1.GlobalSecondaryIndex{
+					{Name: "by_${type}", IndexKey: []string{"id"}},
+				},
+			},
+		}
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				Templates: []couchbasev1beta1.CouchbaseIndexTemplateInstance{
+					{TemplateName: "named", Parameters: map[string]string{"type": "air line"}},
+				},
+			},
+		}
+
+		// Act
+
+		_, err := ExpandIndices(&indexSet, map[string]*couchbasev1beta1.CouchbaseIndexTemplate{
+			"named": &namedTemplate,
+		})
+
+		// Assert
+
+		Expect(err).To(MatchError("index template named: index name by_air line is invalid"))
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbim
+
+import (
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+// Default number of seconds without a scan before an index is reported as unused, 30 days
+const DefaultUnusedAfterSeconds int64 = 30 * 24 * 60 * 60
+
+// Builds the usage of each index managed by the index set from the observed statistics. Indices retain the time they
+// were first tracked from the existing status, and are unused if they haven't been scanned within the configured
+// period, measured from when they were first tracked if they have never been scanned.
+func GetIndexUsage(indexSet *couchbasev1beta1.CouchbaseIndexSet, stats map[cbrest.IndexKey]*cbrest.IndexStats, now time.Time) []couchbasev1beta1.IndexUsage {
+	unusedAfter := DefaultUnusedAfterSeconds
+	if tracking := indexSet.Spec.UsageTracking; tracking != nil && tracking.UnusedAfterSeconds != nil {
+		unusedAfter = *tracking.UnusedAfterSeconds
+	}
+	cutoff := now.Add(-time.Duration(unusedAfter) * time.Second)
+
+	trackedSince := map[GlobalSecondaryIndexIdentifier]metav1.Time{}
+	for _, usage := range indexSet.Status.IndexUsage {
+		if identifier, err := ParseIndexIdentifierString(usage.Name); err == nil {
+			trackedSince[identifier] = usage.TrackedSince
+		}
+	}
+
+	result := []couchbasev1beta1.IndexUsage{}
+	for _, index := range indexSet.Status.Indices {
+		identifier, err := ParseIndexIdentifierString(index)
+		if err != nil {
+			continue
+		}
+
+		usage := couchbasev1beta1.IndexUsage{
+			Name:         identifier.ToString(),
+			TrackedSince: metav1.NewTime(now),
+		}
+		if since, ok := trackedSince[identifier]; ok {
+			usage.TrackedSince = since
+		}
+
+		lastUsed := usage.TrackedSince.Time
+		if indexStats, ok := stats[cbrest.IndexKey{
+			ScopeName:      identifier.ScopeName,
+			CollectionName: identifier.CollectionName,
+			Name:           identifier.Name,
+		}]; ok {
+			usage.NumRequests = indexStats.NumRequests
+			usage.ItemsCount = indexStats.ItemsCount
+
+			if !indexStats.LastScanTime.IsZero() {
+				lastScanTime := metav1.NewTime(indexStats.LastScanTime)
+				usage.LastScanTime = &lastScanTime
+				lastUsed = indexStats.LastScanTime
+			}
+		}
+
+		usage.Unused = !lastUsed.After(cutoff)
+
+		result = append(result, usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// Gets the names of unused indices from index usage
+func GetUnusedIndexNames(usage []couchbasev1beta1.IndexUsage) []string {
+	result := []string{}
+	for _, v := range usage {
+		if v.Unused {
+			result = append(result, v.Name)
+		}
+	}
+
+	return result
+}
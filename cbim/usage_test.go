@@ -0,0 +1,99 @@
+package cbim
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+var _ = Describe("GetIndexUsage", func() {
+
+	now := time.Date(2021, 8, 23, 0, 0, 0, 0, time.UTC)
+
+	It("should report statistics for managed indices", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				UsageTracking: &couchbasev1beta1.CouchbaseIndexSetUsageTracking{
+					UnusedAfterSeconds: pointer.Int64Ptr(86400),
+				},
+			},
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"recent", "inventory.airline.stale"},
+			},
+		}
+
+		stats := map[cbrest.IndexKey]*cbrest.IndexStats{
+			{ScopeName: "_default", CollectionName: "_default", Name: "recent"}: {
+				LastScanTime: now.Add(-time.Hour),
+				NumRequests:  5,
+				ItemsCount:   10,
+			},
+			{ScopeName: "inventory", CollectionName: "airline", Name: "stale"}: {
+				LastScanTime: now.Add(-48 * time.Hour),
+				NumRequests:  1,
+				ItemsCount:   20,
+			},
+			{ScopeName: "_default", CollectionName: "_default", Name: "unmanaged"}: {},
+		}
+
+		// Act
+
+		result := GetIndexUsage(&indexSet, stats, now)
+
+		// Assert
+
+		recentScan := metav1.NewTime(now.Add(-time.Hour))
+		staleScan := metav1.NewTime(now.Add(-48 * time.Hour))
+		Expect(result).To(Equal([]couchbasev1beta1.IndexUsage{
+			{
+				Name:         "inventory.airline.stale",
+				LastScanTime: &staleScan,
+				NumRequests:  1,
+				ItemsCount:   20,
+				TrackedSince: metav1.NewTime(now),
+				Unused:       true,
+			},
+			{
+				Name:         "recent",
+				LastScanTime: &recentScan,
+				NumRequests:  5,
+				ItemsCount:   10,
+				TrackedSince: metav1.NewTime(now),
+			},
+		}))
+	})
+
+	It("should measure indices which were never scanned from when they were first tracked", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"old", "new"},
+				IndexUsage: []couchbasev1beta1.IndexUsage{
+					{Name: "old", TrackedSince: metav1.NewTime(now.Add(-31 * 24 * time.Hour))},
+					{Name: "removed", TrackedSince: metav1.NewTime(now.Add(-31 * 24 * time.Hour))},
+				},
+			},
+		}
+
+		// Act
+
+		result := GetIndexUsage(&indexSet, nil, now)
+
+		// Assert
+
+		Expect(result).To(Equal([]couchbasev1beta1.IndexUsage{
+			{Name: "new", TrackedSince: metav1.NewTime(now)},
+			{Name: "old", TrackedSince: metav1.NewTime(now.Add(-31 * 24 * time.Hour)), Unused: true},
+		}))
+		Expect(GetUnusedIndexNames(result)).To(Equal([]string{"old"}))
+	})
+})
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Recommendations from the Index Advisor for a single query
+type IndexAdvice struct {
+	// CREATE INDEX statements for the existing indices the query would use
+	CurrentIndexes []string
+	// CREATE INDEX statements for the recommended indices, including covering indices
+	RecommendedIndexes []string
+}
+
+type adviseResult struct {
+	Advice struct {
+		AdviseInfo struct {
+			CurrentIndexes     []adviseIndex   `json:"current_indexes"`
+			RecommendedIndexes json.RawMessage `json:"recommended_indexes"`
+		} `json:"adviseinfo"`
+	} `json:"advice"`
+}
+
+type adviseRecommendations struct {
+	Indexes         []adviseIndex `json:"indexes"`
+	CoveringIndexes []adviseIndex `json:"covering_indexes"`
+}
+
+type adviseIndex struct {
+	IndexStatement string `json:"index_statement"`
+}
+
+// Runs a query through the Index Advisor using the ADVISE statement
+func (client *Client) Advise(ctx context.Context, query string) (*IndexAdvice, error) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+
+	results, err := client.Query(ctx, "ADVISE "+query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no advice returned")
+	}
+
+	result := adviseResult{}
+	if err := json.Unmarshal(results[0], &result); err != nil {
+		return nil, err
+	}
+
+	advice := &IndexAdvice{}
+	for _, index := range result.Advice.AdviseInfo.CurrentIndexes {
+		advice.CurrentIndexes = append(advice.CurrentIndexes, index.IndexStatement)
+	}
+
+	// When there are no recommendations the advisor returns a message string instead of an object
+	recommendations := adviseRecommendations{}
+	if json.Unmarshal(result.Advice.AdviseInfo.RecommendedIndexes, &recommendations) == nil {
+		for _, index := range append(recommendations.Indexes, recommendations.CoveringIndexes...) {
+			advice.RecommendedIndexes = append(advice.RecommendedIndexes, index.IndexStatement)
+		}
+	}
+
+	return advice, nil
+}
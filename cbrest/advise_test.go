@@ -0,0 +1,68 @@
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.Advise", func() {
+
+	It("should return current and recommended indices", func() {
+		// Arrange
+
+		var request queryRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			_, _ = w.Write([]byte(`{"status":"success","results":[{
+				"#operator":"Advise",
+				"advice":{"#operator":"IndexAdvice","adviseinfo":{
+					"current_indexes":[{"index_statement":"CREATE INDEX def_type ON ` + "`default`(`type`)" + `","keyspace_alias":"default"}],
+					"recommended_indexes":{
+						"indexes":[{"index_statement":"CREATE INDEX adv_city ON ` + "`default`(`city`)" + `","keyspace_alias":"default"}],
+						"covering_indexes":[{"index_statement":"CREATE INDEX adv_city_name ON ` + "`default`(`city`,`name`)" + `","keyspace_alias":"default"}]
+					}
+				}},
+				"query":"SELECT name FROM default WHERE city = 'Paris'"
+			}]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).Advise(context.Background(), "SELECT name FROM default WHERE city = 'Paris';")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(request.Statement).To(Equal("ADVISE SELECT name FROM default WHERE city = 'Paris'"))
+		Expect(*result).To(Equal(IndexAdvice{
+			CurrentIndexes:     []string{"CREATE INDEX def_type ON `default`(`type`)"},
+			RecommendedIndexes: []string{"CREATE INDEX adv_city ON `default`(`city`)", "CREATE INDEX adv_city_name ON `default`(`city`,`name`)"},
+		}))
+	})
+
+	It("should handle no recommendations", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"status":"success","results":[{
+				"advice":{"adviseinfo":{"recommended_indexes":"No index recommendation at this time."}}
+			}]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).Advise(context.Background(), "SELECT 1")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(*result).To(Equal(IndexAdvice{}))
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Executes a SQL++ for Analytics statement with optional positional arguments, returning the results
+func (client *Client) AnalyticsQuery(ctx context.Context, statement string, args ...interface{}) ([]json.RawMessage, error) {
+	return client.executeStatement(ctx, ServiceAnalytics, "/analytics/service", statement, args)
+}
+
+func getAnalyticsLinkPath(dataverseName string, name string) string {
+	return fmt.Sprintf("/analytics/link/%s/%s", url.PathEscape(dataverseName), url.PathEscape(name))
+}
+
+// Creates or updates an Analytics link within a dataverse. The settings include the link type, such as
+// "couchbase" or "s3", and the settings specific to that type.
+func (client *Client) PutAnalyticsLink(ctx context.Context, dataverseName string, name string, settings url.Values, exists bool) error {
+	method := http.MethodPost
+	if exists {
+		method = http.MethodPut
+	}
+
+	return client.DoForm(ctx, method, ServiceAnalytics, getAnalyticsLinkPath(dataverseName, name), settings, nil)
+}
+
+// Deletes an Analytics link within a dataverse, succeeding if the link does not exist
+func (client *Client) DeleteAnalyticsLink(ctx context.Context, dataverseName string, name string) error {
+	_, err := client.Do(ctx, http.MethodDelete, ServiceAnalytics, getAnalyticsLinkPath(dataverseName, name), nil, "", nil)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.AnalyticsQuery", func() {
+
+	It("should return results", func() {
+		// Arrange
+
+		var request queryRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/analytics/service"))
+
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			_, _ = w.Write([]byte(`{"status":"success","results":["Default.airlines"]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).AnalyticsQuery(context.Background(), "SELECT VALUE d.DatasetName FROM Metadata.`Dataset` d")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(1))
+		Expect(request.Statement).To(Equal("SELECT VALUE d.DatasetName FROM Metadata.`Dataset` d"))
+	})
+
+	It("should return analytics errors", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"fatal","errors":[{"code":24045,"msg":"Cannot find dataset with name airlines"}]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		_, err := newTestClient(server).AnalyticsQuery(context.Background(), "DROP DATASET airlines")
+
+		// Assert
+
+		var queryErr *QueryError
+		Expect(errors.As(err, &queryErr)).To(BeTrue())
+		Expect(queryErr.Code).To(Equal(24045))
+	})
+})
+
+var _ = Describe("Client.PutAnalyticsLink", func() {
+
+	It("should create new links", func() {
+		// Arrange
+
+		var (
+			method string
+			form   url.Values
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/analytics/link/Default/remote"))
+
+			method = r.Method
+			_ = r.ParseForm()
+			form = r.PostForm
+		}))
+		defer server.Close()
+
+		// Act
+
+		err := newTestClient(server).PutAnalyticsLink(context.Background(), "Default", "remote", url.Values{
+			"type":     {"couchbase"},
+			"hostname": {"remote.example.com"},
+		}, false)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(method).To(Equal(http.MethodPost))
+		Expect(form.Get("hostname")).To(Equal("remote.example.com"))
+	})
+
+	It("should update existing links", func() {
+		// Arrange
+
+		var method string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+		}))
+		defer server.Close()
+
+		// Act
+
+		err := newTestClient(server).PutAnalyticsLink(context.Background(), "Default", "remote", url.Values{}, true)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(method).To(Equal(http.MethodPut))
+	})
+})
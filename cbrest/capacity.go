@@ -0,0 +1,185 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	metricIndexMemoryUsedTotal = "index_memory_used_total"
+	metricCollectionItemCount  = "kv_collection_item_count"
+)
+
+// Uniquely identifies a collection within a bucket
+type CollectionKey struct {
+	ScopeName      string
+	CollectionName string
+}
+
+// Capacity of the index service across the cluster
+type IndexServiceCapacity struct {
+	// Memory quota of the index service in bytes, summed across index nodes
+	MemoryQuota int64
+	// Memory used by the index service in bytes, summed across index nodes
+	MemoryUsed int64
+	// Total size in bytes of the disks holding index data, summed across index nodes
+	DiskTotal int64
+	// Space used in bytes on the disks holding index data, summed across index nodes
+	DiskUsed int64
+}
+
+type poolsDefaultResponse struct {
+	IndexMemoryQuota int64 `json:"indexMemoryQuota"`
+	Nodes            []struct {
+		Hostname string   `json:"hostname"`
+		Services []string `json:"services"`
+	} `json:"nodes"`
+}
+
+type nodeStorageResponse struct {
+	Storage struct {
+		Hdd []struct {
+			IndexPath string `json:"index_path"`
+		} `json:"hdd"`
+	} `json:"storage"`
+	AvailableStorage struct {
+		Hdd []struct {
+			Path         string  `json:"path"`
+			SizeKBytes   int64   `json:"sizeKBytes"`
+			UsagePercent float64 `json:"usagePercent"`
+		} `json:"hdd"`
+	} `json:"availableStorage"`
+}
+
+// Gets the memory quota and usage of the index service, along with the size and usage of the disks holding index data
+// on each index node
+func (client *Client) GetIndexServiceCapacity(ctx context.Context) (*IndexServiceCapacity, error) {
+	pool := poolsDefaultResponse{}
+	if err := client.DoJSON(ctx, http.MethodGet, ServiceManagement, "/pools/default", nil, nil, &pool); err != nil {
+		return nil, err
+	}
+
+	result := &IndexServiceCapacity{}
+
+	for _, node := range pool.Nodes {
+		isIndexNode := false
+		for _, service := range node.Services {
+			if service == "index" {
+				isIndexNode = true
+				break
+			}
+		}
+
+		if !isIndexNode {
+			continue
+		}
+
+		// The quota is reported in MiB per index node
+		result.MemoryQuota += pool.IndexMemoryQuota * 1024 * 1024
+
+		diskTotal, diskUsed, err := client.getIndexDiskUsage(ctx, node.Hostname)
+		if err != nil {
+			return nil, err
+		}
+
+		result.DiskTotal += diskTotal
+		result.DiskUsed += diskUsed
+	}
+
+	series, err := client.getStatsRange(ctx, metricIndexMemoryUsedTotal, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range series {
+		if value, ok := getLatestValue(v.Values); ok {
+			result.MemoryUsed += int64(value)
+		}
+	}
+
+	return result, nil
+}
+
+// Gets the size and usage in bytes of the disk holding index data on a node, which is the mounted disk with the
+// longest path containing the index path
+func (client *Client) getIndexDiskUsage(ctx context.Context, hostname string) (int64, int64, error) {
+	// The hostname includes the management port, which is replaced based on the connection string
+	host := hostname
+	if splitHost, _, err := net.SplitHostPort(hostname); err == nil {
+		host = splitHost
+	}
+
+	storage := nodeStorageResponse{}
+	if err := client.doHostJSON(ctx, host, http.MethodGet, ServiceManagement, "/nodes/self", &storage); err != nil {
+		return 0, 0, err
+	}
+
+	if len(storage.Storage.Hdd) == 0 {
+		return 0, 0, nil
+	}
+	indexPath := storage.Storage.Hdd[0].IndexPath
+
+	var total, used int64
+	matchedPath := ""
+	for _, disk := range storage.AvailableStorage.Hdd {
+		if !isPathWithin(indexPath, disk.Path) || len(disk.Path) <= len(matchedPath) {
+			continue
+		}
+
+		matchedPath = disk.Path
+		total = disk.SizeKBytes * 1024
+		used = int64(float64(total) * disk.UsagePercent / 100)
+	}
+
+	return total, used, nil
+}
+
+func isPathWithin(path string, parent string) bool {
+	if parent == "" {
+		return false
+	}
+
+	return path == parent || strings.HasPrefix(path, strings.TrimSuffix(parent, "/")+"/")
+}
+
+// Gets the number of items in each collection of a bucket, summed across data nodes
+func (client *Client) GetCollectionItemCounts(ctx context.Context, bucketName string) (map[CollectionKey]int64, error) {
+	series, err := client.getStatsRange(ctx, metricCollectionItemCount, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[CollectionKey]int64{}
+	for _, v := range series {
+		value, ok := getLatestValue(v.Values)
+		if !ok {
+			continue
+		}
+
+		key := CollectionKey{
+			ScopeName:      getDefaultedLabel(v.Metric, "scope"),
+			CollectionName: getDefaultedLabel(v.Metric, "collection"),
+		}
+		result[key] += int64(value)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package cbrest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.GetIndexServiceCapacity", func() {
+
+	It("should sum the quota and usage across index nodes", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/pools/default":
+				_, _ = w.Write([]byte(`{
+					"indexMemoryQuota": 512,
+					"nodes": [
+						{"hostname": "127.0.0.1:8091", "services": ["index", "n1ql"]},
+						{"hostname": "127.0.0.1:8091", "services": ["index"]},
+						{"hostname": "kv.example:8091", "services": ["kv"]}
+					]
+				}`))
+			case "/nodes/self":
+				_, _ = w.Write([]byte(`{
+					"storage": {"hdd": [{"path": "/data", "index_path": "/mnt/index/data"}]},
+					"availableStorage": {"hdd": [
+						{"path": "/", "sizeKBytes": 1000, "usagePercent": 10},
+						{"path": "/mnt/index", "sizeKBytes": 100, "usagePercent": 40},
+						{"path": "/mnt/ind", "sizeKBytes": 500, "usagePercent": 50}
+					]}
+				}`))
+			case "/pools/default/stats/range/" + metricIndexMemoryUsedTotal:
+				Expect(r.URL.Query().Get("bucket")).To(BeEmpty())
+				_, _ = w.Write([]byte(`{"data":[
+					{"metric":{"instance":"index","nodes":["a"]},"values":[[1,"1000"]]},
+					{"metric":{"instance":"index","nodes":["b"]},"values":[[1,"2000"]]}
+				]}`))
+			}
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetIndexServiceCapacity(context.Background())
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(*result).To(Equal(IndexServiceCapacity{
+			MemoryQuota: 2 * 512 * 1024 * 1024,
+			MemoryUsed:  3000,
+			DiskTotal:   2 * 100 * 1024,
+			DiskUsed:    2 * 40 * 1024,
+		}))
+	})
+})
+
+var _ = Describe("Client.GetCollectionItemCounts", func() {
+
+	It("should sum items across nodes", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/pools/default/stats/range/" + metricCollectionItemCount))
+			Expect(r.URL.Query().Get("bucket")).To(Equal("default"))
+
+			_, _ = w.Write([]byte(`{"data":[
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","nodes":["a"]},"values":[[1,"10"]]},
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","nodes":["b"]},"values":[[1,"15"]]},
+				{"metric":{"bucket":"default","scope":"_default","collection":"_default"},"values":[[1,"3"]]}
+			]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetCollectionItemCounts(context.Background(), "default")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(map[CollectionKey]int64{
+			{ScopeName: "inventory", CollectionName: "airline"}: 25,
+			{ScopeName: "_default", CollectionName: "_default"}: 3,
+		}))
+	})
+})
@@ -0,0 +1,276 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Identifies a Couchbase Server service which exposes a REST API
+type Service int
+
+const (
+	ServiceManagement Service = iota
+	ServiceQuery
+	ServiceSearch
+	ServiceAnalytics
+	ServiceEventing
+)
+
+var defaultPorts = map[Service]int{
+	ServiceManagement: 8091,
+	ServiceQuery:      8093,
+	ServiceSearch:     8094,
+	ServiceAnalytics:  8095,
+	ServiceEventing:   8096,
+}
+
+var defaultTLSPorts = map[Service]int{
+	ServiceManagement: 18091,
+	ServiceQuery:      18093,
+	ServiceSearch:     18094,
+	ServiceAnalytics:  18095,
+	ServiceEventing:   18096,
+}
+
+// Error returned when a Couchbase REST API responds with an unsuccessful status code
+type Error struct {
+	StatusCode int
+	Body       string
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("couchbase returned status %d: %s", err.StatusCode, err.Body)
+}
+
+// Returns true if the error is a Couchbase REST API error with a 404 status code
+func IsNotFound(err error) bool {
+	var restErr *Error
+	return errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound
+}
+
+// Client for the Couchbase Server REST APIs
+type Client struct {
+	scheme     string
+	hosts      []string
+	ports      map[Service]int
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// Creates a new client from a "couchbase://" or "couchbases://" connection string. Requests are sent
+// to each host in the connection string in turn until one can be reached.
+func NewClient(connectionString string, username string, password string) (*Client, error) {
+	client := Client{
+		scheme:   "http",
+		ports:    defaultPorts,
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout: time.Minute,
+		},
+	}
+
+	var hostList string
+	if strings.HasPrefix(connectionString, "couchbases://") {
+		client.scheme = "https"
+		client.ports = defaultTLSPorts
+		hostList = strings.TrimPrefix(connectionString, "couchbases://")
+	} else if strings.HasPrefix(connectionString, "couchbase://") {
+		hostList = strings.TrimPrefix(connectionString, "couchbase://")
+	} else {
+		return nil, fmt.Errorf("invalid connection string: %s", connectionString)
+	}
+
+	// Discard any path or query string
+	if i := strings.IndexAny(hostList, "/?"); i >= 0 {
+		hostList = hostList[:i]
+	}
+
+	for _, host := range strings.Split(hostList, ",") {
+		// Discard any port, it refers to the key/value service
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+
+		if host != "" {
+			client.hosts = append(client.hosts, host)
+		}
+	}
+
+	if len(client.hosts) == 0 {
+		return nil, fmt.Errorf("invalid connection string: %s", connectionString)
+	}
+
+	return &client, nil
+}
+
+// Verifies the cluster's certificate using PEM encoded CA certificates, rather than the system roots, when connecting
+// with "couchbases://"
+func (client *Client) SetCACertificates(pem []byte) error {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return errors.New("no valid CA certificates found")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs: pool,
+	}
+	client.httpClient.Transport = transport
+
+	return nil
+}
+
+func (client *Client) getURL(host string, service Service, path string, query url.Values) string {
+	result := url.URL{
+		Scheme: client.scheme,
+		Host:   fmt.Sprintf("%s:%d", host, client.ports[service]),
+		Path:   path,
+	}
+
+	if query != nil {
+		result.RawQuery = query.Encode()
+	}
+
+	return result.String()
+}
+
+// Sends a request to a service, returning the response body. Non-2xx responses are returned as an *Error.
+func (client *Client) Do(ctx context.Context, method string, service Service, path string, query url.Values, contentType string, body []byte) ([]byte, error) {
+	var lastErr error
+
+	for _, host := range client.hosts {
+		responseBody, sent, err := client.doHost(ctx, host, method, service, path, query, contentType, body)
+		if !sent {
+			// Try the next host
+			lastErr = err
+			continue
+		}
+
+		return responseBody, err
+	}
+
+	return nil, lastErr
+}
+
+// Sends a request to a service on a specific host, unmarshaling the JSON response into result if it is not nil
+func (client *Client) doHostJSON(ctx context.Context, host string, method string, service Service, path string, result interface{}) error {
+	responseBody, _, err := client.doHost(ctx, host, method, service, path, nil, "", nil)
+	if err != nil {
+		return err
+	}
+
+	if result != nil {
+		return json.Unmarshal(responseBody, result)
+	}
+
+	return nil
+}
+
+// Sends a request to a service on a specific host, also returning false if the host couldn't be reached
+func (client *Client) doHost(ctx context.Context, host string, method string, service Service, path string, query url.Values,
+	contentType string, body []byte) ([]byte, bool, error) {
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = strings.NewReader(string(body))
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, client.getURL(host, service, path, query), bodyReader)
+	if err != nil {
+		return nil, true, err
+	}
+
+	request.SetBasicAuth(client.username, client.password)
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, true, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return responseBody, true, &Error{
+			StatusCode: response.StatusCode,
+			Body:       string(responseBody),
+		}
+	}
+
+	return responseBody, true, nil
+}
+
+// Sends a request to a service, unmarshaling the JSON response into result if it is not nil
+func (client *Client) DoJSON(ctx context.Context, method string, service Service, path string, query url.Values, body interface{}, result interface{}) error {
+	var (
+		requestBody []byte
+		contentType string
+	)
+	if body != nil {
+		var err error
+		if requestBody, err = json.Marshal(body); err != nil {
+			return err
+		}
+
+		contentType = "application/json"
+	}
+
+	responseBody, err := client.Do(ctx, method, service, path, query, contentType, requestBody)
+	if err != nil {
+		return err
+	}
+
+	if result != nil {
+		return json.Unmarshal(responseBody, result)
+	}
+
+	return nil
+}
+
+// Sends a form encoded request to a service, unmarshaling the JSON response into result if it is not nil
+func (client *Client) DoForm(ctx context.Context, method string, service Service, path string, form url.Values, result interface{}) error {
+	responseBody, err := client.Do(ctx, method, service, path, nil, "application/x-www-form-urlencoded", []byte(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	if result != nil {
+		return json.Unmarshal(responseBody, result)
+	}
+
+	return nil
+}
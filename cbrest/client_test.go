@@ -0,0 +1,150 @@
+package cbrest
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func newTestClient(server *httptest.Server) *Client {
+	serverURL, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverURL.Port())
+
+	return &Client{
+		scheme: "http",
+		hosts:  []string{serverURL.Hostname()},
+		ports: map[Service]int{
+			ServiceManagement: port,
+			ServiceQuery:      port,
+			ServiceSearch:     port,
+			ServiceAnalytics:  port,
+			ServiceEventing:   port,
+		},
+		username:   "user",
+		password:   "password",
+		httpClient: server.Client(),
+	}
+}
+
+var _ = Describe("NewClient", func() {
+
+	It("should parse hosts", func() {
+		// Act
+
+		client, err := NewClient("couchbase://host1,host2:11210/?opt=1", "user", "password")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(client.hosts).To(Equal([]string{"host1", "host2"}))
+		Expect(client.getURL("host1", ServiceQuery, "/query/service", nil)).To(Equal("http://host1:8093/query/service"))
+	})
+
+	It("should use TLS ports for couchbases", func() {
+		// Act
+
+		client, err := NewClient("couchbases://host1", "user", "password")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(client.getURL("host1", ServiceManagement, "/pools", nil)).To(Equal("https://host1:18091/pools"))
+	})
+
+	It("should error on invalid connection strings", func() {
+		// Act
+
+		_, err := NewClient("http://host1", "user", "password")
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("Client.SetCACertificates", func() {
+
+	It("should verify the server using the CA certificates", func() {
+		// Arrange
+
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		client := newTestClient(server)
+		client.scheme = "https"
+		client.httpClient = &http.Client{}
+
+		caCertificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		// Act
+
+		err := client.SetCACertificates(caCertificate)
+		_, doErr := client.Do(context.Background(), http.MethodGet, ServiceManagement, "/pools", nil, "", nil)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(doErr).To(BeNil())
+	})
+
+	It("should error without valid certificates", func() {
+		// Arrange
+
+		client, _ := NewClient("couchbases://host1", "user", "password")
+
+		// Act
+
+		err := client.SetCACertificates([]byte("invalid"))
+
+		// Assert
+
+		Expect(err).To(MatchError("no valid CA certificates found"))
+	})
+})
+
+var _ = Describe("Client.Do", func() {
+
+	It("should return an Error for unsuccessful responses", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+		}))
+		defer server.Close()
+
+		// Act
+
+		_, err := newTestClient(server).Do(context.Background(), http.MethodGet, ServiceManagement, "/missing", nil, "", nil)
+
+		// Assert
+
+		Expect(IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should send credentials", func() {
+		// Arrange
+
+		var username, password string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, _ = r.BasicAuth()
+		}))
+		defer server.Close()
+
+		// Act
+
+		_, err := newTestClient(server).Do(context.Background(), http.MethodGet, ServiceManagement, "/pools", nil, "", nil)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(username).To(Equal("user"))
+		Expect(password).To(Equal("password"))
+	})
+})
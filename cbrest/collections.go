@@ -0,0 +1,81 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Collection within a bucket's collection manifest
+type CollectionManifestCollection struct {
+	Name   string `json:"name"`
+	MaxTTL int    `json:"maxTTL,omitempty"`
+}
+
+// Scope within a bucket's collection manifest
+type CollectionManifestScope struct {
+	Name        string                         `json:"name"`
+	Collections []CollectionManifestCollection `json:"collections"`
+}
+
+// Collection manifest for a bucket, listing all scopes and collections
+type CollectionManifest struct {
+	UID    string                    `json:"uid"`
+	Scopes []CollectionManifestScope `json:"scopes"`
+}
+
+// Gets the collection manifest for a bucket
+func (client *Client) GetCollectionManifest(ctx context.Context, bucketName string) (*CollectionManifest, error) {
+	manifest := CollectionManifest{}
+	if err := client.DoJSON(ctx, http.MethodGet, ServiceManagement,
+		fmt.Sprintf("/pools/default/buckets/%s/scopes", url.PathEscape(bucketName)), nil, nil, &manifest); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+// Creates a scope within a bucket
+func (client *Client) CreateScope(ctx context.Context, bucketName string, scopeName string) error {
+	return client.DoForm(ctx, http.MethodPost, ServiceManagement,
+		fmt.Sprintf("/pools/default/buckets/%s/scopes", url.PathEscape(bucketName)),
+		url.Values{"name": {scopeName}}, nil)
+}
+
+// Creates a collection within a scope. A maxTTL of zero uses the bucket's max TTL.
+func (client *Client) CreateCollection(ctx context.Context, bucketName string, scopeName string, collectionName string, maxTTL int) error {
+	form := url.Values{"name": {collectionName}}
+	if maxTTL != 0 {
+		form.Set("maxTTL", strconv.Itoa(maxTTL))
+	}
+
+	return client.DoForm(ctx, http.MethodPost, ServiceManagement,
+		fmt.Sprintf("/pools/default/buckets/%s/scopes/%s/collections", url.PathEscape(bucketName), url.PathEscape(scopeName)),
+		form, nil)
+}
+
+// Updates the max TTL of an existing collection, requires Couchbase Server 7.2 or later
+func (client *Client) UpdateCollection(ctx context.Context, bucketName string, scopeName string, collectionName string, maxTTL int) error {
+	return client.DoForm(ctx, http.MethodPatch, ServiceManagement,
+		fmt.Sprintf("/pools/default/buckets/%s/scopes/%s/collections/%s",
+			url.PathEscape(bucketName), url.PathEscape(scopeName), url.PathEscape(collectionName)),
+		url.Values{"maxTTL": {strconv.Itoa(maxTTL)}}, nil)
+}
@@ -0,0 +1,65 @@
+package cbrest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.GetCollectionManifest", func() {
+
+	It("should parse scopes and collections", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/pools/default/buckets/default/scopes"))
+
+			_, _ = w.Write([]byte(`{"uid":"2","scopes":[
+				{"name":"inventory","uid":"8","collections":[{"name":"airline","uid":"9","maxTTL":60}]}
+			]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetCollectionManifest(context.Background(), "default")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.Scopes).To(Equal([]CollectionManifestScope{
+			{Name: "inventory", Collections: []CollectionManifestCollection{{Name: "airline", MaxTTL: 60}}},
+		}))
+	})
+})
+
+var _ = Describe("Client.CreateCollection", func() {
+
+	It("should post the collection", func() {
+		// Arrange
+
+		var body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/pools/default/buckets/default/scopes/inventory/collections"))
+
+			bytes, _ := ioutil.ReadAll(r.Body)
+			body = string(bytes)
+			_, _ = w.Write([]byte(`{"uid":"3"}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		err := newTestClient(server).CreateCollection(context.Background(), "default", "inventory", "airline", 60)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(body).To(Equal("maxTTL=60&name=airline"))
+	})
+})
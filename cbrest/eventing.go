@@ -0,0 +1,177 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Composite deployment statuses of an Eventing function
+const (
+	EventingStatusUndeployed  = "undeployed"
+	EventingStatusDeploying   = "deploying"
+	EventingStatusDeployed    = "deployed"
+	EventingStatusUndeploying = "undeploying"
+	EventingStatusPaused      = "paused"
+	EventingStatusPausing     = "pausing"
+)
+
+// Eventing function definition, as accepted and returned by the Eventing REST API
+type EventingFunction struct {
+	Name          string                 `json:"appname"`
+	Code          string                 `json:"appcode"`
+	DepCfg        EventingDepCfg         `json:"depcfg"`
+	Settings      map[string]interface{} `json:"settings"`
+	FunctionScope *EventingFunctionScope `json:"function_scope,omitempty"`
+}
+
+// Keyspaces and bindings of an Eventing function
+type EventingDepCfg struct {
+	SourceBucket       string                    `json:"source_bucket"`
+	SourceScope        string                    `json:"source_scope,omitempty"`
+	SourceCollection   string                    `json:"source_collection,omitempty"`
+	MetadataBucket     string                    `json:"metadata_bucket"`
+	MetadataScope      string                    `json:"metadata_scope,omitempty"`
+	MetadataCollection string                    `json:"metadata_collection,omitempty"`
+	Buckets            []EventingBucketBinding   `json:"buckets,omitempty"`
+	URLs               []EventingURLBinding      `json:"curl,omitempty"`
+	Constants          []EventingConstantBinding `json:"constants,omitempty"`
+}
+
+// Binds an alias in the function code to a keyspace
+type EventingBucketBinding struct {
+	Alias          string `json:"alias"`
+	BucketName     string `json:"bucket_name"`
+	ScopeName      string `json:"scope_name,omitempty"`
+	CollectionName string `json:"collection_name,omitempty"`
+	// Either "r" or "rw"
+	Access string `json:"access"`
+}
+
+// Binds an alias in the function code to a URL for use with curl()
+type EventingURLBinding struct {
+	Alias                  string `json:"value"`
+	Hostname               string `json:"hostname"`
+	AuthType               string `json:"auth_type"`
+	AllowCookies           bool   `json:"allow_cookies"`
+	ValidateSSLCertificate bool   `json:"validate_ssl_certificate"`
+}
+
+// Binds an alias in the function code to a constant JavaScript literal
+type EventingConstantBinding struct {
+	Alias   string `json:"value"`
+	Literal string `json:"literal"`
+}
+
+// Scope which owns an Eventing function, "*" for admin managed functions
+type EventingFunctionScope struct {
+	BucketName string `json:"bucket"`
+	ScopeName  string `json:"scope"`
+}
+
+type eventingStatusResponse struct {
+	Apps []struct {
+		Name            string `json:"name"`
+		CompositeStatus string `json:"composite_status"`
+	} `json:"apps"`
+}
+
+// Returns true if the error indicates that an Eventing function does not exist. Some versions of Couchbase Server
+// respond with a status code other than 404.
+func isEventingFunctionNotFound(err error) bool {
+	var restErr *Error
+	return IsNotFound(err) ||
+		(errors.As(err, &restErr) && strings.Contains(restErr.Body, "ERR_APP_NOT_FOUND"))
+}
+
+func getEventingFunctionPath(name string) string {
+	return fmt.Sprintf("/api/v1/functions/%s", url.PathEscape(name))
+}
+
+// Gets an Eventing function definition, returns nil if the function does not exist
+func (client *Client) GetEventingFunction(ctx context.Context, name string) (*EventingFunction, error) {
+	result := EventingFunction{}
+	if err := client.DoJSON(ctx, http.MethodGet, ServiceEventing, getEventingFunctionPath(name), nil, nil, &result); err != nil {
+		if isEventingFunctionNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Creates or updates an Eventing function. Deployed functions must be paused or undeployed before being updated.
+func (client *Client) PutEventingFunction(ctx context.Context, function *EventingFunction) error {
+	return client.DoJSON(ctx, http.MethodPost, ServiceEventing, getEventingFunctionPath(function.Name), nil, function, nil)
+}
+
+// Deletes an undeployed Eventing function, succeeds if the function does not exist
+func (client *Client) DeleteEventingFunction(ctx context.Context, name string) error {
+	if _, err := client.Do(ctx, http.MethodDelete, ServiceEventing, getEventingFunctionPath(name), nil, "", nil); err != nil && !isEventingFunctionNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Gets the composite deployment status of an Eventing function, returns an empty string if the function does not exist
+func (client *Client) GetEventingFunctionStatus(ctx context.Context, name string) (string, error) {
+	response := eventingStatusResponse{}
+	if err := client.DoJSON(ctx, http.MethodGet, ServiceEventing, "/api/v1/status", nil, nil, &response); err != nil {
+		return "", err
+	}
+
+	for _, app := range response.Apps {
+		if app.Name == name {
+			return app.CompositeStatus, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Starts deploying an Eventing function, the deployment completes asynchronously
+func (client *Client) DeployEventingFunction(ctx context.Context, name string) error {
+	return client.postEventingFunctionAction(ctx, name, "deploy")
+}
+
+// Starts undeploying an Eventing function, the undeployment completes asynchronously
+func (client *Client) UndeployEventingFunction(ctx context.Context, name string) error {
+	return client.postEventingFunctionAction(ctx, name, "undeploy")
+}
+
+// Starts pausing a deployed Eventing function, the pause completes asynchronously
+func (client *Client) PauseEventingFunction(ctx context.Context, name string) error {
+	return client.postEventingFunctionAction(ctx, name, "pause")
+}
+
+// Resumes a paused Eventing function, the resume completes asynchronously
+func (client *Client) ResumeEventingFunction(ctx context.Context, name string) error {
+	return client.postEventingFunctionAction(ctx, name, "resume")
+}
+
+func (client *Client) postEventingFunctionAction(ctx context.Context, name string, action string) error {
+	_, err := client.Do(ctx, http.MethodPost, ServiceEventing, getEventingFunctionPath(name)+"/"+action, nil, "", nil)
+	return err
+}
@@ -0,0 +1,181 @@
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.GetEventingFunction", func() {
+
+	It("should return the function definition", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/api/v1/functions/audit"))
+
+			_, _ = w.Write([]byte(`{"appname":"audit","appcode":"function OnUpdate(doc, meta) {}","depcfg":{"source_bucket":"travel","metadata_bucket":"meta"},"settings":{"worker_count":1}}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetEventingFunction(context.Background(), "audit")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.Code).To(Equal("function OnUpdate(doc, meta) {}"))
+		Expect(result.DepCfg.SourceBucket).To(Equal("travel"))
+		Expect(result.DepCfg.MetadataBucket).To(Equal("meta"))
+	})
+
+	It("should return nil for missing functions", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"name":"ERR_APP_NOT_FOUND_TS","code":27,"description":"Application not found"}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetEventingFunction(context.Background(), "audit")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(BeNil())
+	})
+})
+
+var _ = Describe("Client.PutEventingFunction", func() {
+
+	It("should post the definition", func() {
+		// Arrange
+
+		var body map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.URL.Path).To(Equal("/api/v1/functions/audit"))
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		}))
+		defer server.Close()
+
+		// Act
+
+		err := newTestClient(server).PutEventingFunction(context.Background(), &EventingFunction{
+			Name: "audit",
+			Code: "function OnUpdate(doc, meta) {}",
+			DepCfg: EventingDepCfg{
+				SourceBucket:   "travel",
+				MetadataBucket: "meta",
+				Buckets: []EventingBucketBinding{
+					{Alias: "dst", BucketName: "travel", ScopeName: "audit", CollectionName: "log", Access: "rw"},
+				},
+			},
+			Settings: map[string]interface{}{},
+		})
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(body["appname"]).To(Equal("audit"))
+		Expect(body["depcfg"]).To(HaveKeyWithValue("source_bucket", "travel"))
+		Expect(body["depcfg"]).To(HaveKey("buckets"))
+	})
+})
+
+var _ = Describe("Client.DeleteEventingFunction", func() {
+
+	It("should succeed for missing functions", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodDelete))
+
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"name":"ERR_APP_NOT_FOUND_TS","code":27}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		err := newTestClient(server).DeleteEventingFunction(context.Background(), "audit")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("Client.GetEventingFunctionStatus", func() {
+
+	It("should return the composite status", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/api/v1/status"))
+
+			_, _ = w.Write([]byte(`{"apps":[{"name":"other","composite_status":"undeployed"},{"name":"audit","composite_status":"deployed"}],"num_eventing_nodes":1}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetEventingFunctionStatus(context.Background(), "audit")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(EventingStatusDeployed))
+	})
+
+	It("should return empty for missing functions", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"apps":[],"num_eventing_nodes":1}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetEventingFunctionStatus(context.Background(), "audit")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Client.DeployEventingFunction", func() {
+
+	It("should post the deploy action", func() {
+		// Arrange
+
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			path = r.URL.Path
+		}))
+		defer server.Close()
+
+		// Act
+
+		err := newTestClient(server).DeployEventingFunction(context.Background(), "audit")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(path).To(Equal("/api/v1/functions/audit/deploy"))
+	})
+})
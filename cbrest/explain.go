@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Explains a query using the EXPLAIN statement, returning the indices scanned by the query plan
+func (client *Client) Explain(ctx context.Context, statement string) ([]IndexKey, error) {
+	statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+
+	results, err := client.Query(ctx, "EXPLAIN "+statement)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no plan returned")
+	}
+
+	var plan interface{}
+	if err := json.Unmarshal(results[0], &plan); err != nil {
+		return nil, err
+	}
+
+	result := []IndexKey{}
+	addPlanIndexes(plan, map[IndexKey]bool{}, &result)
+
+	return result, nil
+}
+
+// Walks a query plan for scan operators, such as IndexScan3 or the scans within an IntersectScan, adding each index
+// they scan once. Scans of a bucket's default collection don't include a scope.
+func addPlanIndexes(node interface{}, found map[IndexKey]bool, result *[]IndexKey) {
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		_, isOperator := typed["#operator"].(string)
+		indexName, hasIndex := typed["index"].(string)
+		if isOperator && hasIndex {
+			key := IndexKey{
+				ScopeName:      "_default",
+				CollectionName: "_default",
+				Name:           indexName,
+			}
+			if scopeName, ok := typed["scope"].(string); ok && scopeName != "" {
+				key.ScopeName = scopeName
+				key.CollectionName, _ = typed["keyspace"].(string)
+			}
+
+			if !found[key] {
+				found[key] = true
+				*result = append(*result, key)
+			}
+		}
+
+		for _, child := range typed {
+			addPlanIndexes(child, found, result)
+		}
+
+	case []interface{}:
+		for _, child := range typed {
+			addPlanIndexes(child, found, result)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.Explain", func() {
+
+	It("should return the scanned indices", func() {
+		// Arrange
+
+		var request queryRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			_, _ = w.Write([]byte(`{"status":"success","results":[{
+				"plan":{"#operator":"Sequence","~children":[
+					{"#operator":"IntersectScan","scans":[
+						{"#operator":"IndexScan3","bucket":"travel-sample","scope":"inventory","keyspace":"hotel","index":"def_city"},
+						{"#operator":"IndexScan3","bucket":"travel-sample","scope":"inventory","keyspace":"hotel","index":"def_country"}
+					]},
+					{"#operator":"IndexScan3","keyspace":"travel-sample","index":"def_type"},
+					{"#operator":"Fetch","keyspace":"hotel"}
+				]},
+				"text":"SELECT 1"
+			}]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).Explain(context.Background(), "SELECT name FROM `travel-sample`.inventory.hotel WHERE city = 'Paris';")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(request.Statement).To(Equal("EXPLAIN SELECT name FROM `travel-sample`.inventory.hotel WHERE city = 'Paris'"))
+		Expect(result).To(ConsistOf(
+			IndexKey{ScopeName: "inventory", CollectionName: "hotel", Name: "def_city"},
+			IndexKey{ScopeName: "inventory", CollectionName: "hotel", Name: "def_country"},
+			IndexKey{ScopeName: "_default", CollectionName: "_default", Name: "def_type"},
+		))
+	})
+})
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Error returned by the Query or Analytics service when a statement fails
+type QueryError struct {
+	Code    int    `json:"code"`
+	Message string `json:"msg"`
+}
+
+func (err *QueryError) Error() string {
+	return fmt.Sprintf("query error %d: %s", err.Code, err.Message)
+}
+
+type queryRequest struct {
+	Statement string        `json:"statement"`
+	Args      []interface{} `json:"args,omitempty"`
+}
+
+type queryResponse struct {
+	Status  string            `json:"status"`
+	Results []json.RawMessage `json:"results"`
+	Errors  []QueryError      `json:"errors"`
+}
+
+// Executes a SQL++ statement with optional positional arguments, returning the results
+func (client *Client) Query(ctx context.Context, statement string, args ...interface{}) ([]json.RawMessage, error) {
+	return client.executeStatement(ctx, ServiceQuery, "/query/service", statement, args)
+}
+
+// Executes a statement using a service which follows the Query service request and response format
+func (client *Client) executeStatement(ctx context.Context, service Service, path string, statement string, args []interface{}) ([]json.RawMessage, error) {
+	response := queryResponse{}
+	err := client.DoJSON(ctx, http.MethodPost, service, path, nil, queryRequest{
+		Statement: statement,
+		Args:      args,
+	}, &response)
+
+	var restErr *Error
+	if errors.As(err, &restErr) {
+		// Prefer the error details from the service
+		if json.Unmarshal([]byte(restErr.Body), &response) != nil || len(response.Errors) == 0 {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if len(response.Errors) > 0 {
+		return nil, &response.Errors[0]
+	}
+
+	return response.Results, nil
+}
+
+// JavaScript library used by SQL++ user-defined functions
+type JavaScriptLibrary struct {
+	Name       string `json:"name"`
+	BucketName string `json:"bucket"`
+	ScopeName  string `json:"scope"`
+	Code       string `json:"code"`
+}
+
+func getLibraryQuery(bucketName string, scopeName string) url.Values {
+	return url.Values{
+		"bucket": {bucketName},
+		"scope":  {scopeName},
+	}
+}
+
+// Gets all JavaScript libraries, including global libraries and libraries in every scope
+func (client *Client) GetJavaScriptLibraries(ctx context.Context) ([]JavaScriptLibrary, error) {
+	libraries := []JavaScriptLibrary{}
+	if err := client.DoJSON(ctx, http.MethodGet, ServiceQuery, "/evaluator/v1/libraries", nil, nil, &libraries); err != nil {
+		return nil, err
+	}
+
+	return libraries, nil
+}
+
+// Creates or replaces a JavaScript library within a scope
+func (client *Client) PutJavaScriptLibrary(ctx context.Context, bucketName string, scopeName string, name string, code string) error {
+	// The Query service requires a JSON content type, even though the body is JavaScript
+	_, err := client.Do(ctx, http.MethodPost, ServiceQuery, "/evaluator/v1/libraries/"+url.PathEscape(name),
+		getLibraryQuery(bucketName, scopeName), "application/json", []byte(code))
+
+	return err
+}
+
+// Deletes a JavaScript library within a scope, succeeding if the library does not exist
+func (client *Client) DeleteJavaScriptLibrary(ctx context.Context, bucketName string, scopeName string, name string) error {
+	_, err := client.Do(ctx, http.MethodDelete, ServiceQuery, "/evaluator/v1/libraries/"+url.PathEscape(name),
+		getLibraryQuery(bucketName, scopeName), "", nil)
+	if err != nil && !IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.Query", func() {
+
+	It("should return results", func() {
+		// Arrange
+
+		var request queryRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/query/service"))
+
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			_, _ = w.Write([]byte(`{"status":"success","results":["a","b"]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).Query(context.Background(), "SELECT RAW name FROM system:functions WHERE bucket = $1", "default")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(HaveLen(2))
+		Expect(request.Args).To(Equal([]interface{}{"default"}))
+	})
+
+	It("should return query errors", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"status":"fatal","errors":[{"code":3000,"msg":"syntax error"}]}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		_, err := newTestClient(server).Query(context.Background(), "SELECT")
+
+		// Assert
+
+		Expect(err).To(Equal(&QueryError{Code: 3000, Message: "syntax error"}))
+	})
+})
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Full text search index definition, as accepted and returned by the Search REST API
+type SearchIndexDefinition struct {
+	Type       string                 `json:"type"`
+	Name       string                 `json:"name"`
+	UUID       string                 `json:"uuid,omitempty"`
+	SourceType string                 `json:"sourceType"`
+	SourceName string                 `json:"sourceName"`
+	PlanParams map[string]interface{} `json:"planParams,omitempty"`
+	Params     map[string]interface{} `json:"params"`
+}
+
+type searchIndexResponse struct {
+	Status   string                 `json:"status"`
+	UUID     string                 `json:"uuid,omitempty"`
+	IndexDef *SearchIndexDefinition `json:"indexDef,omitempty"`
+}
+
+// Returns true if the error indicates that a search index does not exist. Some versions of Couchbase Server
+// respond with a 400 status code rather than 404.
+func isSearchIndexNotFound(err error) bool {
+	var restErr *Error
+	return IsNotFound(err) ||
+		(errors.As(err, &restErr) && restErr.StatusCode == http.StatusBadRequest && strings.Contains(restErr.Body, "index not found"))
+}
+
+func getSearchIndexPath(name string) string {
+	return fmt.Sprintf("/api/index/%s", url.PathEscape(name))
+}
+
+// Gets a search index definition, returns nil if the index does not exist
+func (client *Client) GetSearchIndex(ctx context.Context, name string) (*SearchIndexDefinition, error) {
+	response := searchIndexResponse{}
+	if err := client.DoJSON(ctx, http.MethodGet, ServiceSearch, getSearchIndexPath(name), nil, nil, &response); err != nil {
+		if isSearchIndexNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return response.IndexDef, nil
+}
+
+// Creates or updates a search index. To update an existing index the definition must include the UUID of the
+// existing index. Returns the UUID of the new index definition, if provided by the server.
+func (client *Client) PutSearchIndex(ctx context.Context, definition *SearchIndexDefinition) (string, error) {
+	response := searchIndexResponse{}
+	if err := client.DoJSON(ctx, http.MethodPut, ServiceSearch, getSearchIndexPath(definition.Name), nil, definition, &response); err != nil {
+		return "", err
+	}
+
+	return response.UUID, nil
+}
+
+// Deletes a search index, succeeding if the index does not exist
+func (client *Client) DeleteSearchIndex(ctx context.Context, name string) error {
+	if _, err := client.Do(ctx, http.MethodDelete, ServiceSearch, getSearchIndexPath(name), nil, "", nil); err != nil && !isSearchIndexNotFound(err) {
+		return err
+	}
+
+	return nil
+}
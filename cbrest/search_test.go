@@ -0,0 +1,87 @@
+package cbrest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.GetSearchIndex", func() {
+
+	It("should return the index definition", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/api/index/hotels"))
+
+			_, _ = w.Write([]byte(`{"status":"ok","indexDef":{"type":"fulltext-index","name":"hotels","uuid":"abc","sourceName":"travel","params":{}}}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetSearchIndex(context.Background(), "hotels")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.UUID).To(Equal("abc"))
+		Expect(result.SourceName).To(Equal("travel"))
+	})
+
+	It("should return nil for missing indices", func() {
+		// Arrange
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"rest_auth: preparePerms, err: index not found","status":"fail"}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetSearchIndex(context.Background(), "hotels")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(BeNil())
+	})
+})
+
+var _ = Describe("Client.PutSearchIndex", func() {
+
+	It("should put the definition", func() {
+		// Arrange
+
+		var body SearchIndexDefinition
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPut))
+			Expect(r.URL.Path).To(Equal("/api/index/hotels"))
+
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"status":"ok","uuid":"def"}`))
+		}))
+		defer server.Close()
+
+		// Act
+
+		uuid, err := newTestClient(server).PutSearchIndex(context.Background(), &SearchIndexDefinition{
+			Type:       "fulltext-index",
+			Name:       "hotels",
+			SourceType: "gocbcore",
+			SourceName: "travel",
+			Params:     map[string]interface{}{},
+		})
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(uuid).To(Equal("def"))
+		Expect(body.SourceName).To(Equal("travel"))
+	})
+})
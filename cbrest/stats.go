@@ -0,0 +1,203 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cbrest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	metricLastKnownScanTime = "index_last_known_scan_time"
+	metricNumRequests       = "index_num_requests"
+	metricItemsCount        = "index_items_count"
+	metricDataSize          = "index_data_size"
+	metricDiskSize          = "index_disk_size"
+)
+
+var replicaSuffixRegex = regexp.MustCompile(`\s+\(replica \d+\)$`)
+
+// Uniquely identifies an index within a bucket
+type IndexKey struct {
+	ScopeName      string
+	CollectionName string
+	Name           string
+}
+
+// Usage statistics for an index, aggregated across all replicas and partitions
+type IndexStats struct {
+	// Most recent time the index was scanned, zero if it has never been scanned
+	LastScanTime time.Time
+	// Number of requests served by the index since the indexer started
+	NumRequests int64
+	// Number of items in the index
+	ItemsCount int64
+	// Size of the index data in bytes, for a single replica
+	DataSize int64
+	// Size of the index on disk in bytes, for a single replica
+	DiskSize int64
+}
+
+type statsRangeResponse struct {
+	Data []statsRangeSeries `json:"data"`
+}
+
+type statsRangeSeries struct {
+	Metric map[string]interface{} `json:"metric"`
+	Values [][]interface{}        `json:"values"`
+}
+
+// Gets usage statistics for all indices on a bucket from the cluster statistics API
+func (client *Client) GetIndexStats(ctx context.Context, bucketName string) (map[IndexKey]*IndexStats, error) {
+	result := map[IndexKey]*IndexStats{}
+
+	getStats := func(key IndexKey) *IndexStats {
+		stats, ok := result[key]
+		if !ok {
+			stats = &IndexStats{}
+			result[key] = stats
+		}
+
+		return stats
+	}
+
+	// Items and sizes are counted per replica by summing partitions, then the largest replica is used
+	perReplica := map[string]map[IndexKey]map[string]int64{
+		metricItemsCount: {},
+		metricDataSize:   {},
+		metricDiskSize:   {},
+	}
+
+	for _, metric := range []string{metricLastKnownScanTime, metricNumRequests, metricItemsCount, metricDataSize, metricDiskSize} {
+		series, err := client.getStatsRange(ctx, metric, bucketName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range series {
+			indexName, _ := v.Metric["index"].(string)
+			if indexName == "" {
+				continue
+			}
+
+			value, ok := getLatestValue(v.Values)
+			if !ok {
+				continue
+			}
+
+			key := IndexKey{
+				ScopeName:      getDefaultedLabel(v.Metric, "scope"),
+				CollectionName: getDefaultedLabel(v.Metric, "collection"),
+				Name:           replicaSuffixRegex.ReplaceAllString(indexName, ""),
+			}
+			stats := getStats(key)
+
+			switch metric {
+			case metricLastKnownScanTime:
+				// Reported in nanoseconds since the epoch
+				if value > 0 {
+					scanTime := time.Unix(0, int64(value))
+					if scanTime.After(stats.LastScanTime) {
+						stats.LastScanTime = scanTime
+					}
+				}
+
+			case metricNumRequests:
+				stats.NumRequests += int64(value)
+
+			case metricItemsCount, metricDataSize, metricDiskSize:
+				replicas := perReplica[metric]
+				if replicas[key] == nil {
+					replicas[key] = map[string]int64{}
+				}
+				replicas[key][indexName] += int64(value)
+			}
+		}
+	}
+
+	for metric, values := range perReplica {
+		for key, replicas := range values {
+			stats := getStats(key)
+
+			var largest *int64
+			switch metric {
+			case metricItemsCount:
+				largest = &stats.ItemsCount
+			case metricDataSize:
+				largest = &stats.DataSize
+			case metricDiskSize:
+				largest = &stats.DiskSize
+			}
+
+			for _, value := range replicas {
+				if value > *largest {
+					*largest = value
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// Gets the latest value of each series for a metric, optionally filtered to a bucket
+func (client *Client) getStatsRange(ctx context.Context, metric string, bucketName string) ([]statsRangeSeries, error) {
+	query := url.Values{}
+	if bucketName != "" {
+		query.Set("bucket", bucketName)
+	}
+	query.Set("start", "-60")
+
+	response := statsRangeResponse{}
+	if err := client.DoJSON(ctx, http.MethodGet, ServiceManagement, fmt.Sprintf("/pools/default/stats/range/%s", metric),
+		query, nil, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
+
+func getDefaultedLabel(labels map[string]interface{}, name string) string {
+	if value, ok := labels[name].(string); ok && value != "" {
+		return value
+	}
+
+	return "_default"
+}
+
+// Values are returned as [timestamp, "value"] pairs, in chronological order
+func getLatestValue(values [][]interface{}) (float64, bool) {
+	for i := len(values) - 1; i >= 0; i-- {
+		if len(values[i]) < 2 {
+			continue
+		}
+
+		if str, ok := values[i][1].(string); ok {
+			if value, err := strconv.ParseFloat(str, 64); err == nil && !math.IsNaN(value) {
+				return value, true
+			}
+		}
+	}
+
+	return 0, false
+}
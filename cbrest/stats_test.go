@@ -0,0 +1,66 @@
+package cbrest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client.GetIndexStats", func() {
+
+	It("should aggregate replicas and partitions", func() {
+		// Arrange
+
+		responses := map[string]string{
+			metricLastKnownScanTime: `{"data":[
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx"},"values":[[1,"1629763200000000000"]]},
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx (replica 1)"},"values":[[1,"1629763260000000000"]]}
+			]}`,
+			metricNumRequests: `{"data":[
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx"},"values":[[1,"5"]]},
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx (replica 1)"},"values":[[1,"7"]]}
+			]}`,
+			metricItemsCount: `{"data":[
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx","nodes":["a"]},"values":[[1,"10"]]},
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx","nodes":["b"]},"values":[[1,"15"]]},
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx (replica 1)"},"values":[[1,"25"],[2,"NaN"]]},
+				{"metric":{"bucket":"default","index":"defaultIdx"},"values":[[1,"3"]]}
+			]}`,
+			metricDataSize: `{"data":[
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx"},"values":[[1,"1000"]]},
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx (replica 1)"},"values":[[1,"1200"]]}
+			]}`,
+			metricDiskSize: `{"data":[
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx","nodes":["a"]},"values":[[1,"300"]]},
+				{"metric":{"bucket":"default","scope":"inventory","collection":"airline","index":"idx","nodes":["b"]},"values":[[1,"400"]]}
+			]}`,
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			metric := strings.TrimPrefix(r.URL.Path, "/pools/default/stats/range/")
+			_, _ = w.Write([]byte(responses[metric]))
+		}))
+		defer server.Close()
+
+		// Act
+
+		result, err := newTestClient(server).GetIndexStats(context.Background(), "default")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(*result[IndexKey{ScopeName: "inventory", CollectionName: "airline", Name: "idx"}]).To(Equal(IndexStats{
+			LastScanTime: time.Unix(0, 1629763260000000000),
+			NumRequests:  12,
+			ItemsCount:   25,
+			DataSize:     1200,
+			DiskSize:     700,
+		}))
+		Expect(result[IndexKey{ScopeName: "_default", CollectionName: "_default", Name: "defaultIdx"}].ItemsCount).To(Equal(int64(3)))
+	})
+})
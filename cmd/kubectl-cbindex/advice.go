@@ -0,0 +1,118 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Gets the Index Advisor recommendations for an index set, or a single cluster within an index set targeting
+// multiple clusters
+func getAdvice(indexSet *couchbasev1beta1.CouchbaseIndexSet, clusterName string) *couchbasev1beta1.IndexSetAdvice {
+	if clusterName != "" {
+		if clusterStatus := getClusterStatus(indexSet, clusterName); clusterStatus != nil {
+			return clusterStatus.Advice
+		}
+
+		return nil
+	}
+
+	return indexSet.Status.Advice
+}
+
+// Writes the advice for each query, followed by the recommended indices as a snippet for the index set's indices
+func writeAdvice(out io.Writer, advice *couchbasev1beta1.IndexSetAdvice) error {
+	if advice == nil {
+		_, err := fmt.Fprintln(out, "No advice, spec.advisor is not set or the queries haven't been advised yet")
+		return err
+	}
+
+	fmt.Fprintf(out, "Last Advised: %s\n\n", advice.LastAdvised.UTC().Format(time.RFC3339))
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "QUERY\tUSES\tRECOMMENDED\tERROR")
+	for _, query := range advice.Queries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", query.Name, joinOrNone(query.UsedIndices), joinOrNone(query.RecommendedIndices), query.Error)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(advice.RecommendedIndices) == 0 {
+		_, err := fmt.Fprintln(out, "\nNo indices are recommended")
+		return err
+	}
+
+	bytes, err := marshalManifest(map[string]interface{}{
+		"indices": advice.RecommendedIndices,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\nRecommended indices, review before adding them to spec.indices:")
+	_, err = out.Write(bytes)
+	return err
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+
+	return strings.Join(values, ", ")
+}
+
+func runAdvice(ctx context.Context, args []string, out io.Writer) error {
+	options := globalOptions{}
+	name, err := parseNameArgs(newFlagSet("advice", &options), args)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClients(&options)
+	if err != nil {
+		return err
+	}
+
+	indexSet, err := c.getIndexSet(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	for i, clusterName := range getClusterNames(indexSet, options.ClusterName) {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		if clusterName != "" {
+			fmt.Fprintf(out, "Cluster: %s\n", clusterName)
+		}
+
+		if err := writeAdvice(out, getAdvice(indexSet, clusterName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("writeAdvice", func() {
+
+	It("should write the advice and recommended indices", func() {
+		// Arrange
+
+		advice := couchbasev1beta1.IndexSetAdvice{
+			LastAdvised: metav1.NewTime(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)),
+			Queries: []couchbasev1beta1.QueryAdvice{
+				{Name: "by-city", UsedIndices: []string{"def_type"}, RecommendedIndices: []string{"adv_city"}},
+				{Name: "broken", Error: "syntax error"},
+			},
+			RecommendedIndices: []couchbasev1beta1.GlobalSecondaryIndex{
+				{Name: "adv_city", IndexKey: []string{"`city`"}},
+			},
+		}
+
+		out := bytes.Buffer{}
+
+		// Act
+
+		err := writeAdvice(&out, &advice)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(out.String()).To(Equal("Last Advised: 2021-06-01T12:00:00Z\n\n" +
+			"QUERY    USES      RECOMMENDED  ERROR\n" +
+			"by-city  def_type  adv_city     \n" +
+			"broken   <none>    <none>       syntax error\n" +
+			"\nRecommended indices, review before adding them to spec.indices:\n" +
+			"indices:\n" +
+			"- indexKey:\n" +
+			"  - '`city`'\n" +
+			"  name: adv_city\n"))
+	})
+
+	It("should explain missing advice", func() {
+		// Arrange
+
+		out := bytes.Buffer{}
+
+		// Act
+
+		err := writeAdvice(&out, nil)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(out.String()).To(ContainSubstring("No advice"))
+	})
+})
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	couchbasev2 "github.com/brantburnett/couchbase-index-operator/couchbase/v2"
+)
+
+// Label applied by the operator to Jobs and ConfigMaps for a cluster within an index set targeting multiple clusters
+const indexSetClusterLabel = "indexSetCluster"
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(couchbasev1beta1.AddToScheme(scheme))
+	utilruntime.Must(couchbasev2.AddToScheme(scheme))
+}
+
+type clients struct {
+	client.Client
+	Config    *rest.Config
+	Namespace string
+}
+
+// Connects to Kubernetes using the kubeconfig, following the same rules as kubectl
+func newClients(options *globalOptions) (*clients, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = options.Kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: options.Context,
+	}
+	overrides.Context.Namespace = options.Namespace
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	return &clients{Client: c, Config: config, Namespace: namespace}, nil
+}
+
+func (c *clients) getIndexSet(ctx context.Context, name string) (*couchbasev1beta1.CouchbaseIndexSet, error) {
+	indexSet := couchbasev1beta1.CouchbaseIndexSet{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: c.Namespace, Name: name}, &indexSet); err != nil {
+		return nil, err
+	}
+
+	return &indexSet, nil
+}
+
+func getClusterStatus(indexSet *couchbasev1beta1.CouchbaseIndexSet, clusterName string) *couchbasev1beta1.CouchbaseIndexSetClusterStatus {
+	for i := range indexSet.Status.Clusters {
+		if indexSet.Status.Clusters[i].Name == clusterName {
+			return &indexSet.Status.Clusters[i]
+		}
+	}
+
+	return nil
+}
+
+// Gets the names of the clusters targeted by an index set, or a single empty name for a single cluster
+func getClusterNames(indexSet *couchbasev1beta1.CouchbaseIndexSet, clusterName string) []string {
+	if clusterName != "" || len(indexSet.Spec.Clusters) == 0 {
+		return []string{clusterName}
+	}
+
+	names := make([]string, len(indexSet.Spec.Clusters))
+	for i, cluster := range indexSet.Spec.Clusters {
+		names[i] = cluster.Name
+	}
+
+	return names
+}
+
+// Finds the most recent sync Job for an index set, returns nil if there are none
+func (c *clients) getLatestJob(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet, clusterName string) (*batchv1.Job, error) {
+	jobs := batchv1.JobList{}
+	if err := c.List(ctx, &jobs, client.InNamespace(indexSet.Namespace),
+		client.MatchingLabels{"controller-uid": string(indexSet.UID)}); err != nil {
+		return nil, err
+	}
+
+	var latest *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if job.Labels[indexSetClusterLabel] != clusterName {
+			continue
+		}
+
+		if latest == nil || job.CreationTimestamp.After(latest.CreationTimestamp.Time) {
+			latest = job
+		}
+	}
+
+	return latest, nil
+}
+
+// Finds the most recent Pod created by a Job, returns nil if there are none
+func (c *clients) getLatestPod(ctx context.Context, job *batchv1.Job) (*corev1.Pod, error) {
+	pods := corev1.PodList{}
+	if err := c.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return nil, err
+	}
+
+	var latest *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if latest == nil || pod.CreationTimestamp.After(latest.CreationTimestamp.Time) {
+			latest = pod
+		}
+	}
+
+	return latest, nil
+}
+
+func (c *clients) newClientset() (*kubernetes.Clientset, error) {
+	return kubernetes.NewForConfig(c.Config)
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbim"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+	couchbasev2 "github.com/brantburnett/couchbase-index-operator/couchbase/v2"
+)
+
+// Options for commands which connect to Couchbase to read the collections or indexes in a bucket
+type couchbaseOptions struct {
+	ConnectionString string
+}
+
+func addCouchbaseFlags(fs *flag.FlagSet, options *couchbaseOptions) {
+	fs.StringVar(&options.ConnectionString, "connection-string", "",
+		"Couchbase connection string, overrides the index set's connection, such as couchbase://localhost when port forwarding")
+}
+
+// Gets a copy of the index set which targets a single cluster, using the status of that cluster. Index sets which
+// target multiple clusters require a cluster name.
+func getClusterIndexSet(indexSet *couchbasev1beta1.CouchbaseIndexSet, clusterName string) (*couchbasev1beta1.CouchbaseIndexSet, error) {
+	if clusterName == "" {
+		if len(indexSet.Spec.Clusters) > 0 {
+			return nil, fmt.Errorf("index set %s targets multiple clusters, use --cluster to select one", indexSet.Name)
+		}
+
+		return indexSet, nil
+	}
+
+	for _, cluster := range indexSet.Spec.Clusters {
+		if cluster.Name != clusterName {
+			continue
+		}
+
+		result := indexSet.DeepCopy()
+		result.Spec.Cluster = cluster.Cluster.DeepCopy()
+		result.Spec.Clusters = nil
+		result.Status = couchbasev1beta1.CouchbaseIndexSetStatus{}
+
+		if clusterStatus := getClusterStatus(indexSet, clusterName); clusterStatus != nil {
+			result.Status = couchbasev1beta1.CouchbaseIndexSetStatus{
+				Conditions:       clusterStatus.Conditions,
+				ConfigMapName:    clusterStatus.ConfigMapName,
+				Cluster:          clusterStatus.Cluster,
+				Indices:          clusterStatus.Indices,
+				ProtectedIndices: clusterStatus.ProtectedIndices,
+				PendingDrops:     clusterStatus.PendingDrops,
+				InUseIndices:     clusterStatus.InUseIndices,
+			}
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("cluster %s is not found in index set %s", clusterName, indexSet.Name)
+}
+
+// Creates a client for the Couchbase REST APIs of the cluster targeted by an index set, using the same connection and
+// admin secret as the operator. The index set must target a single cluster.
+func (c *clients) newRestClient(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet,
+	options *couchbaseOptions) (*cbrest.Client, error) {
+
+	cluster := indexSet.Spec.Cluster
+	if cluster == nil {
+		return nil, fmt.Errorf("index set %s has no cluster", indexSet.Name)
+	}
+
+	var connectionString, secretName, caSecretName string
+	if cluster.ClusterRef != nil {
+		couchbaseCluster := couchbasev2.CouchbaseCluster{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: indexSet.Namespace, Name: cluster.ClusterRef.Name}, &couchbaseCluster); err != nil {
+			return nil, err
+		}
+
+		connectionString = fmt.Sprintf("couchbase://%s-srv.%s", couchbaseCluster.Name, couchbaseCluster.Namespace)
+		if cluster.ClusterRef.SecretName != nil {
+			secretName = *cluster.ClusterRef.SecretName
+		} else {
+			secretName = couchbaseCluster.Spec.Security.AdminSecret
+		}
+	} else if cluster.Manual != nil {
+		connectionString = cluster.Manual.ConnectionString
+		secretName = cluster.Manual.SecretName
+		if cluster.Manual.CASecretName != nil {
+			caSecretName = *cluster.Manual.CASecretName
+		}
+	} else {
+		return nil, errors.New("missing connection info")
+	}
+
+	if options.ConnectionString != "" {
+		connectionString = options.ConnectionString
+	}
+
+	secret := corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: indexSet.Namespace, Name: secretName}, &secret); err != nil {
+		return nil, err
+	}
+
+	restClient, err := cbrest.NewClient(connectionString, string(secret.Data["username"]), string(secret.Data["password"]))
+	if err != nil {
+		return nil, err
+	}
+
+	if caSecretName != "" {
+		caSecret := corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: indexSet.Namespace, Name: caSecretName}, &caSecret); err != nil {
+			return nil, err
+		}
+
+		if err := restClient.SetCACertificates(caSecret.Data["ca.crt"]); err != nil {
+			return nil, fmt.Errorf("secret %s: %w", caSecretName, err)
+		}
+	}
+
+	return restClient, nil
+}
+
+// Expands indices which target multiple scopes or collections using the collections which currently exist in the
+// bucket, the same way the operator does when it syncs. Couchbase is only contacted if there are such indices.
+func (c *clients) expandKeyspaces(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet,
+	indices []couchbasev1beta1.GlobalSecondaryIndex, options *couchbaseOptions) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+
+	hasFanOut := false
+	for _, gsi := range indices {
+		if cbim.IsFanOutIndex(gsi) {
+			hasFanOut = true
+			break
+		}
+	}
+
+	if !hasFanOut {
+		return indices, nil
+	}
+
+	restClient, err := c.newRestClient(ctx, indexSet, options)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := restClient.GetCollectionManifest(ctx, indexSet.Spec.BucketName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read collections: %w", err)
+	}
+
+	collections := map[string][]string{}
+	for _, scope := range manifest.Scopes {
+		collectionNames := make([]string, len(scope.Collections))
+		for i, collection := range scope.Collections {
+			collectionNames[i] = collection.Name
+		}
+
+		collections[scope.Name] = collectionNames
+	}
+
+	return cbim.ExpandKeyspaces(indices, collections)
+}
+
+// Reads the indexes which exist in the bucket from system:indexes. Only indexes managed by the index set, which are
+// either desired or tracked in its status, are returned, sorted by identifier. Warnings are returned for indexes
+// which can't be represented.
+func (c *clients) getLiveIndices(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet,
+	indices []couchbasev1beta1.GlobalSecondaryIndex, options *couchbaseOptions) ([]couchbasev1beta1.GlobalSecondaryIndex, []string, error) {
+
+	restClient, err := c.newRestClient(ctx, indexSet, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := restClient.Query(ctx,
+		"SELECT RAW i FROM system:indexes AS i WHERE i.`using` = \"gsi\" AND (i.bucket_id = $1 OR (i.bucket_id IS MISSING AND i.keyspace_id = $1))",
+		indexSet.Spec.BucketName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read indexes: %w", err)
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	imported, err := cbim.ImportSystemIndexes(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return filterManagedIndices(indexSet, indices, imported.Indices), imported.Warnings, nil
+}
+
+// Filters indexes read from the bucket to those managed by the index set, sorted by identifier
+func filterManagedIndices(indexSet *couchbasev1beta1.CouchbaseIndexSet, indices []couchbasev1beta1.GlobalSecondaryIndex,
+	imported []cbim.ImportedIndex) []couchbasev1beta1.GlobalSecondaryIndex {
+
+	managed := toSet(indexSet.Status.Indices)
+	for _, gsi := range indices {
+		managed[cbim.GetIndexIdentifier(gsi).ToString()] = true
+	}
+
+	result := []couchbasev1beta1.GlobalSecondaryIndex{}
+	for _, index := range imported {
+		if index.BucketName == indexSet.Spec.BucketName && managed[cbim.GetIndexIdentifier(index.Index).ToString()] {
+			result = append(result, index.Index)
+		}
+	}
+
+	sortIndices(result)
+	return result
+}
+
+func sortIndices(indices []couchbasev1beta1.GlobalSecondaryIndex) {
+	sort.SliceStable(indices, func(i, j int) bool {
+		return cbim.GetIndexIdentifier(indices[i]).ToString() < cbim.GetIndexIdentifier(indices[j]).ToString()
+	})
+}
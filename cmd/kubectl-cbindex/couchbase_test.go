@@ -0,0 +1,109 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+var _ = Describe("getClusterIndexSet", func() {
+
+	It("should return a single cluster index set unchanged", func() {
+		// Arrange
+
+		indexSet := &couchbasev1beta1.CouchbaseIndexSet{}
+		indexSet.Status.Indices = []string{"example"}
+
+		// Act
+
+		result, err := getClusterIndexSet(indexSet, "")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(BeIdenticalTo(indexSet))
+	})
+
+	It("should require a cluster name for multiple clusters", func() {
+		// Arrange
+
+		indexSet := &couchbasev1beta1.CouchbaseIndexSet{}
+		indexSet.Name = "example"
+		indexSet.Spec.Clusters = []couchbasev1beta1.CouchbaseIndexSetCluster{{Name: "east"}}
+
+		// Act
+
+		_, err := getClusterIndexSet(indexSet, "")
+
+		// Assert
+
+		Expect(err).To(MatchError("index set example targets multiple clusters, use --cluster to select one"))
+	})
+
+	It("should use the cluster's connection and status", func() {
+		// Arrange
+
+		east := couchbasev1beta1.CouchbaseCluster{
+			Manual: &couchbasev1beta1.CouchbaseClusterManual{ConnectionString: "couchbase://east", SecretName: "east"},
+		}
+
+		indexSet := &couchbasev1beta1.CouchbaseIndexSet{}
+		indexSet.Spec.Clusters = []couchbasev1beta1.CouchbaseIndexSetCluster{
+			{Name: "west"},
+			{Name: "east", Cluster: east},
+		}
+		indexSet.Status.Clusters = []couchbasev1beta1.CouchbaseIndexSetClusterStatus{
+			{Name: "west", Indices: []string{"west"}},
+			{Name: "east", Indices: []string{"east"}, ConfigMapName: "example-east-abc"},
+		}
+
+		// Act
+
+		result, err := getClusterIndexSet(indexSet, "east")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result.Spec.Cluster).To(Equal(&east))
+		Expect(result.Spec.Clusters).To(BeNil())
+		Expect(result.Status.Indices).To(Equal([]string{"east"}))
+		Expect(result.Status.ConfigMapName).To(Equal("example-east-abc"))
+		Expect(indexSet.Status.Indices).To(BeNil())
+	})
+})
+
+var _ = Describe("filterManagedIndices", func() {
+
+	It("should return desired and tracked indexes in the bucket sorted", func() {
+		// Arrange
+
+		indexSet := &couchbasev1beta1.CouchbaseIndexSet{}
+		indexSet.Spec.BucketName = "default"
+		indexSet.Status.Indices = []string{"removed"}
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "desired", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("airline")},
+		}
+
+		imported := []cbim.ImportedIndex{
+			{BucketName: "default", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "unmanaged"}},
+			{BucketName: "default", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "removed"}},
+			{BucketName: "default", Index: couchbasev1beta1.GlobalSecondaryIndex{
+				Name: "desired", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("airline")}},
+			{BucketName: "other", Index: couchbasev1beta1.GlobalSecondaryIndex{Name: "removed"}},
+		}
+
+		// Act
+
+		result := filterManagedIndices(indexSet, indices, imported)
+
+		// Assert
+
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Name).To(Equal("desired"))
+		Expect(result[1].Name).To(Equal("removed"))
+	})
+})
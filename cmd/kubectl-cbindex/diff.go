@@ -0,0 +1,365 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+// Number of unchanged lines shown around each change
+const diffContextLines = 3
+
+// Renders the couchbase-index-manager spec for an index set targeting a single cluster, the same spec the operator
+// writes to its ConfigMap
+func (c *clients) renderSpec(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet, options *couchbaseOptions) (string, error) {
+	indices, err := c.getDesiredIndices(ctx, indexSet, options)
+	if err != nil {
+		return "", err
+	}
+
+	result := cbim.GenerateResult{}
+	return cbim.GenerateYaml(indexSet, indices, &result)
+}
+
+// Renders the couchbase-index-manager spec for the indexes which exist in the bucket and the indices desired by an
+// index set, without any drops. Both are sorted so that only the differences in the indexes are shown.
+func (c *clients) renderLiveSpecs(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet, managedBy *couchbasev1beta1.CouchbaseIndexSet,
+	options *couchbaseOptions, warnings io.Writer) (string, string, error) {
+
+	indices, err := c.getDesiredIndices(ctx, indexSet, options)
+	if err != nil {
+		return "", "", err
+	}
+
+	liveIndices, liveWarnings, err := c.getLiveIndices(ctx, managedBy, indices, options)
+	if err != nil {
+		return "", "", err
+	}
+	for _, warning := range liveWarnings {
+		fmt.Fprintf(warnings, "warning: %s\n", warning)
+	}
+
+	// Render without a status so that removed indices are shown as differences instead of drops
+	desiredSet := indexSet.DeepCopy()
+	desiredSet.Status = couchbasev1beta1.CouchbaseIndexSetStatus{}
+	desiredIndices := append([]couchbasev1beta1.GlobalSecondaryIndex{}, indices...)
+	sortIndices(desiredIndices)
+
+	result := cbim.GenerateResult{}
+	live, err := cbim.GenerateYaml(desiredSet, liveIndices, &result)
+	if err != nil {
+		return "", "", err
+	}
+
+	desired, err := cbim.GenerateYaml(desiredSet, desiredIndices, &result)
+	if err != nil {
+		return "", "", err
+	}
+
+	return live, desired, nil
+}
+
+// Renders the N1QL statements which create the index set's indices
+func (c *clients) renderDdl(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet, options *couchbaseOptions) (string, error) {
+	indices, err := c.getDesiredIndices(ctx, indexSet, options)
+	if err != nil {
+		return "", err
+	}
+
+	return cbim.GenerateDdl(indexSet.Spec.BucketName, indices)
+}
+
+// Gets the indices desired by an index set targeting a single cluster, with templates, DDL, and indices targeting
+// multiple scopes or collections expanded
+func (c *clients) getDesiredIndices(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet,
+	options *couchbaseOptions) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+
+	indices, err := c.expandIndices(ctx, indexSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.expandKeyspaces(ctx, indexSet, indices, options)
+}
+
+func readIndexSetFile(fileName string) (*couchbasev1beta1.CouchbaseIndexSet, error) {
+	var (
+		bytes []byte
+		err   error
+	)
+	if fileName == "-" {
+		bytes, err = io.ReadAll(os.Stdin)
+	} else {
+		bytes, err = os.ReadFile(fileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	indexSet := couchbasev1beta1.CouchbaseIndexSet{}
+	if err := yaml.UnmarshalStrict(bytes, &indexSet); err != nil {
+		return nil, fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	if indexSet.Kind != "CouchbaseIndexSet" {
+		return nil, fmt.Errorf("%s: expected kind CouchbaseIndexSet, found %q", fileName, indexSet.Kind)
+	}
+
+	return &indexSet, nil
+}
+
+func runDiff(ctx context.Context, args []string, out io.Writer) error {
+	options := globalOptions{}
+	couchbase := couchbaseOptions{}
+	fs := newFlagSet("diff", &options)
+	addCouchbaseFlags(fs, &couchbase)
+	fileName := fs.String("f", "", "Local manifest to compare with the index set's spec, use - for stdin")
+	live := fs.Bool("live", false, "Compare with the indexes which exist in the bucket instead")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	var local *couchbasev1beta1.CouchbaseIndexSet
+	if *fileName != "" {
+		if local, err = readIndexSetFile(*fileName); err != nil {
+			return err
+		}
+
+		if options.Namespace == "" {
+			options.Namespace = local.Namespace
+		}
+		if len(positional) == 0 {
+			positional = []string{local.Name}
+		}
+	}
+
+	if len(positional) != 1 || positional[0] == "" {
+		return fmt.Errorf("expected an index set name, found %s", formatArgs(positional))
+	}
+
+	c, err := newClients(&options)
+	if err != nil {
+		return err
+	}
+
+	indexSet, err := c.getIndexSet(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	clusterSet, err := getClusterIndexSet(indexSet, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	toName, toSet := "spec", clusterSet
+	if local != nil {
+		// The status is shared so removed indices are dropped the same way the operator would drop them
+		local.Namespace = indexSet.Namespace
+		local.Status = indexSet.Status
+
+		toName = *fileName
+		if toSet, err = getClusterIndexSet(local, options.ClusterName); err != nil {
+			return err
+		}
+	}
+
+	var fromName, from, to string
+	switch {
+	case *live:
+		fromName = "live"
+
+		if from, to, err = c.renderLiveSpecs(ctx, toSet, clusterSet, &couchbase, os.Stderr); err != nil {
+			return err
+		}
+	case local != nil:
+		fromName = "spec"
+
+		if from, err = c.renderSpec(ctx, clusterSet, &couchbase); err != nil {
+			return err
+		}
+		if to, err = c.renderSpec(ctx, toSet, &couchbase); err != nil {
+			return err
+		}
+	default:
+		fromName = "last-applied"
+
+		if configMapName := clusterSet.Status.ConfigMapName; configMapName != "" {
+			configMap := corev1.ConfigMap{}
+			if err := c.Get(ctx, types.NamespacedName{Namespace: indexSet.Namespace, Name: configMapName}, &configMap); err != nil {
+				return err
+			}
+
+			from = configMap.Data["indices.yaml"]
+		}
+
+		if to, err = c.renderSpec(ctx, toSet, &couchbase); err != nil {
+			return err
+		}
+	}
+
+	// Expressions are normalized so that cosmetic differences, which don't rebuild indices, aren't shown
+	diff := diffLines(splitLines(cbim.NormalizeIndexSpecs(from)), splitLines(cbim.NormalizeIndexSpecs(to)), diffContextLines)
+	if diff == "" {
+		fmt.Fprintln(out, "No differences")
+		return nil
+	}
+
+	fmt.Fprintf(out, "--- %s\n+++ %s\n%s", fromName, toName, diff)
+	return nil
+}
+
+func splitLines(value string) []string {
+	value = strings.TrimSuffix(value, "\n")
+	if value == "" {
+		return []string{}
+	}
+
+	return strings.Split(value, "\n")
+}
+
+type diffOp struct {
+	Kind byte // ' ', '-', or '+'
+	Line string
+}
+
+// Computes a line diff using the longest common subsequence, formatted as unified diff hunks with the given number
+// of context lines. Returns an empty string if the lines are equal.
+func diffLines(from []string, to []string, context int) string {
+	// lengths[i][j] is the length of the longest common subsequence of from[i:] and to[j:]
+	lengths := make([][]int, len(from)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(from)+len(to))
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case i < len(from) && j < len(to) && from[i] == to[j]:
+			ops = append(ops, diffOp{' ', from[i]})
+			i++
+			j++
+		case i < len(from) && (j == len(to) || lengths[i+1][j] >= lengths[i][j+1]):
+			// Prefer removals before additions, matching diff
+			ops = append(ops, diffOp{'-', from[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', to[j]})
+			j++
+		}
+	}
+
+	return formatHunks(ops, context)
+}
+
+func formatHunks(ops []diffOp, context int) string {
+	var sb strings.Builder
+
+	for start := 0; start < len(ops); {
+		// Find the next change
+		first := start
+		for first < len(ops) && ops[first].Kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		// Extend the hunk until there are more than twice the context lines without a change
+		last := first
+		for k := first; k < len(ops) && k-last-1 <= 2*context; k++ {
+			if ops[k].Kind != ' ' {
+				last = k
+			}
+		}
+
+		hunkStart := max(first-context, start)
+		hunkEnd := min(last+context+1, len(ops))
+
+		fromLine, toLine := 1, 1
+		for _, op := range ops[:hunkStart] {
+			if op.Kind != '+' {
+				fromLine++
+			}
+			if op.Kind != '-' {
+				toLine++
+			}
+		}
+
+		fromCount, toCount := 0, 0
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.Kind != '+' {
+				fromCount++
+			}
+			if op.Kind != '-' {
+				toCount++
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+		for _, op := range ops[hunkStart:hunkEnd] {
+			sb.WriteByte(op.Kind)
+			sb.WriteString(op.Line)
+			sb.WriteByte('\n')
+		}
+
+		start = hunkEnd
+	}
+
+	return sb.String()
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("diffLines", func() {
+
+	It("should return empty for equal lines", func() {
+		// Act
+
+		result := diffLines([]string{"a", "b"}, []string{"a", "b"}, 3)
+
+		// Assert
+
+		Expect(result).To(BeEmpty())
+	})
+
+	It("should show changes with context", func() {
+		// Arrange
+
+		from := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+		to := []string{"1", "2", "3", "4", "five", "6", "7", "8", "9", "10", "11"}
+
+		// Act
+
+		result := diffLines(from, to, 1)
+
+		// Assert
+
+		Expect(result).To(Equal("@@ -4,3 +4,3 @@\n 4\n-5\n+five\n 6\n@@ -10,1 +10,2 @@\n 10\n+11\n"))
+	})
+
+	It("should merge nearby changes into one hunk", func() {
+		// Arrange
+
+		from := []string{"a", "b", "c", "d"}
+		to := []string{"A", "b", "c", "D"}
+
+		// Act
+
+		result := diffLines(from, to, 1)
+
+		// Assert
+
+		Expect(result).To(Equal("@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n-d\n+D\n"))
+	})
+
+	It("should handle an empty original", func() {
+		// Act
+
+		result := diffLines(splitLines(""), splitLines("a\nb\n"), 3)
+
+		// Assert
+
+		Expect(result).To(Equal("@@ -1,0 +1,2 @@\n+a\n+b\n"))
+	})
+})
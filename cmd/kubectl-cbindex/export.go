@@ -0,0 +1,162 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Annotation added by "kubectl apply", which is not useful in an exported manifest
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Marshals a manifest to YAML, omitting the status and any null values such as an empty creationTimestamp
+func marshalManifest(manifest interface{}) ([]byte, error) {
+	bytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(bytes, &fields); err != nil {
+		return nil, err
+	}
+
+	delete(fields, "status")
+	removeNulls(fields)
+
+	return yaml.Marshal(fields)
+}
+
+func removeNulls(value interface{}) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if child == nil {
+				delete(typed, key)
+			} else {
+				removeNulls(child)
+			}
+		}
+	case []interface{}:
+		for _, child := range typed {
+			removeNulls(child)
+		}
+	}
+}
+
+// Builds a manifest from a live index set, removing the status and server-populated metadata
+func getExportManifest(indexSet *couchbasev1beta1.CouchbaseIndexSet) *couchbasev1beta1.CouchbaseIndexSet {
+	annotations := map[string]string{}
+	for key, value := range indexSet.Annotations {
+		if key != lastAppliedAnnotation {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	return &couchbasev1beta1.CouchbaseIndexSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: couchbasev1beta1.GroupVersion.String(),
+			Kind:       "CouchbaseIndexSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        indexSet.Name,
+			Namespace:   indexSet.Namespace,
+			Labels:      indexSet.Labels,
+			Annotations: annotations,
+		},
+		Spec: *indexSet.Spec.DeepCopy(),
+	}
+}
+
+func runExport(ctx context.Context, args []string, out io.Writer) error {
+	options := globalOptions{}
+	couchbase := couchbaseOptions{}
+	fs := newFlagSet("export", &options)
+	addCouchbaseFlags(fs, &couchbase)
+	expand := fs.Bool("expand", false, "Replace template instances with the indices they define")
+	format := fs.String("format", "yaml",
+		"Output format, yaml for a CouchbaseIndexSet manifest, cbim for a couchbase-index-manager spec, or n1ql for CREATE INDEX statements")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected an index set name, found %s", formatArgs(positional))
+	}
+	if *format != "yaml" && *format != "cbim" && *format != "n1ql" {
+		return fmt.Errorf("unknown format %q, expected yaml, cbim, or n1ql", *format)
+	}
+
+	c, err := newClients(&options)
+	if err != nil {
+		return err
+	}
+
+	indexSet, err := c.getIndexSet(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	if *format == "cbim" || *format == "n1ql" {
+		clusterSet, err := getClusterIndexSet(indexSet, options.ClusterName)
+		if err != nil {
+			return err
+		}
+
+		var spec string
+		if *format == "cbim" {
+			spec, err = c.renderSpec(ctx, clusterSet, &couchbase)
+		} else {
+			spec, err = c.renderDdl(ctx, clusterSet, &couchbase)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(out, spec)
+		return err
+	}
+
+	manifest := getExportManifest(indexSet)
+
+	if *expand {
+		if manifest.Spec.Indices, err = c.expandIndices(ctx, indexSet); err != nil {
+			return err
+		}
+		manifest.Spec.Templates = nil
+	}
+
+	bytes, err := marshalManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(bytes)
+	return err
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+// Formats accepted by the import command
+const (
+	importFormatAuto = "auto"
+	importFormatCbim = "cbim"
+	importFormatN1ql = "n1ql"
+	importFormatJson = "json"
+)
+
+// Determines the format of an import file from its extension
+func getImportFormat(fileName string, format string) (string, error) {
+	if format != importFormatAuto {
+		return format, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		return importFormatCbim, nil
+	case ".n1ql", ".sql", ".sqlpp":
+		return importFormatN1ql, nil
+	case ".json":
+		return importFormatJson, nil
+	default:
+		return "", fmt.Errorf("unable to determine the format of %s, use --format", fileName)
+	}
+}
+
+func importFile(fileName string, format string, bucketName string) (cbim.ImportResult, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if fileName == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(fileName)
+	}
+	if err != nil {
+		return cbim.ImportResult{}, err
+	}
+
+	switch format {
+	case importFormatCbim:
+		if bucketName == "" {
+			return cbim.ImportResult{}, errors.New("couchbase-index-manager specs don't include the bucket, use --bucket")
+		}
+
+		return cbim.ImportIndexSpecs(data, bucketName)
+	case importFormatN1ql:
+		return cbim.ImportStatements(string(data)), nil
+	case importFormatJson:
+		return cbim.ImportSystemIndexes(data)
+	default:
+		return cbim.ImportResult{}, fmt.Errorf("unknown format %q, expected auto, cbim, n1ql, or json", format)
+	}
+}
+
+// Builds the manifests for imported indices, returning the YAML documents
+func buildImportManifests(indexSets []couchbasev1beta1.CouchbaseIndexSet, namespace string, clusterRef string) (string, error) {
+	var sb strings.Builder
+
+	for i := range indexSets {
+		indexSet := &indexSets[i]
+		indexSet.Namespace = namespace
+
+		if clusterRef != "" {
+			indexSet.Spec.Cluster = &couchbasev1beta1.CouchbaseCluster{
+				ClusterRef: &couchbasev1beta1.CouchbaseClusterRef{Name: clusterRef},
+			}
+		}
+
+		bytes, err := marshalManifest(indexSet)
+		if err != nil {
+			return "", err
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("---\n")
+		}
+		sb.Write(bytes)
+	}
+
+	return sb.String(), nil
+}
+
+func runImport(ctx context.Context, args []string, out io.Writer) error {
+	options := globalOptions{}
+	fs := newFlagSet("import", &options)
+	format := fs.String("format", importFormatAuto, "Input format: cbim, n1ql, json, or auto to use the file extension")
+	bucketName := fs.String("bucket", "", "Bucket for couchbase-index-manager specs, which don't include the bucket")
+	namePrefix := fs.String("name-prefix", "", "Prefix for the index set names, which are otherwise the bucket names")
+	clusterRef := fs.String("cluster-ref", "", "Name of the CouchbaseCluster to target")
+
+	fileNames, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(fileNames) == 0 {
+		return errors.New("expected at least one file, use - for stdin")
+	}
+
+	result := cbim.ImportResult{}
+	for _, fileName := range fileNames {
+		fileFormat, err := getImportFormat(fileName, *format)
+		if err != nil {
+			return err
+		}
+
+		fileResult, err := importFile(fileName, fileFormat, *bucketName)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fileName, err)
+		}
+
+		for i, warning := range fileResult.Warnings {
+			fileResult.Warnings[i] = fileName + ": " + warning
+		}
+
+		result.Merge(fileResult)
+	}
+
+	indexSets, warnings := cbim.GroupIndexSets(result.Indices, *namePrefix)
+	result.Warnings = append(result.Warnings, warnings...)
+
+	manifests, err := buildImportManifests(indexSets, options.Namespace, *clusterRef)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(out, manifests); err != nil {
+		return err
+	}
+
+	// Report anything which couldn't be represented without mixing it into the manifests
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
+	if len(indexSets) == 0 {
+		return errors.New("no indices were imported")
+	}
+
+	return nil
+}
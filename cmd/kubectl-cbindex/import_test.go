@@ -0,0 +1,73 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("getImportFormat", func() {
+
+	DescribeTable("should detect the format from the extension",
+		func(fileName string, expected string) {
+			// Act
+
+			result, err := getImportFormat(fileName, importFormatAuto)
+
+			// Assert
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("yaml", "indices.yaml", importFormatCbim),
+		Entry("yml", "indices.YML", importFormatCbim),
+		Entry("n1ql", "indices.n1ql", importFormatN1ql),
+		Entry("sql", "indices.sql", importFormatN1ql),
+		Entry("json", "system-indexes.json", importFormatJson),
+	)
+
+	It("should require a format for unknown extensions", func() {
+		// Act
+
+		_, err := getImportFormat("-", importFormatAuto)
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("buildImportManifests", func() {
+
+	It("should omit the status and empty metadata", func() {
+		// Arrange
+
+		indexSets := []couchbasev1beta1.CouchbaseIndexSet{
+			{
+				Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+					BucketName: "beer",
+					Indices:    []couchbasev1beta1.GlobalSecondaryIndex{{Name: "def_type", IndexKey: []string{"type"}}},
+				},
+			},
+			{
+				Spec: couchbasev1beta1.CouchbaseIndexSetSpec{BucketName: "travel-sample"},
+			},
+		}
+		indexSets[0].Name = "beer"
+		indexSets[1].Name = "travel-sample"
+
+		// Act
+
+		result, err := buildImportManifests(indexSets, "", "cluster")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(ContainSubstring("---\n"))
+		Expect(result).To(ContainSubstring("name: cluster"))
+		Expect(result).NotTo(ContainSubstring("status"))
+		Expect(result).NotTo(ContainSubstring("creationTimestamp"))
+	})
+})
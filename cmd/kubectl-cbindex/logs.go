@@ -0,0 +1,84 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func runLogs(ctx context.Context, args []string, out io.Writer) error {
+	options := globalOptions{}
+	fs := newFlagSet("logs", &options)
+	follow := fs.Bool("f", false, "Stream the logs until the Job completes")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("expected an index set name, found %s", formatArgs(positional))
+	}
+
+	c, err := newClients(&options)
+	if err != nil {
+		return err
+	}
+
+	indexSet, err := c.getIndexSet(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+
+	clusterNames := getClusterNames(indexSet, options.ClusterName)
+	if len(clusterNames) > 1 {
+		return fmt.Errorf("index set %s targets multiple clusters, use --cluster to select one", indexSet.Name)
+	}
+
+	job, err := c.getLatestJob(ctx, indexSet, clusterNames[0])
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("no sync Job found for index set %s", indexSet.Name)
+	}
+
+	pod, err := c.getLatestPod(ctx, job)
+	if err != nil {
+		return err
+	}
+	if pod == nil {
+		return fmt.Errorf("no Pod found for Job %s", job.Name)
+	}
+
+	clientset, err := c.newClientset()
+	if err != nil {
+		return err
+	}
+
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Follow: *follow}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(out, stream)
+	return err
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-cbindex is a kubectl plugin for inspecting and operating CouchbaseIndexSets. Install it by placing the
+// binary on the PATH, then run "kubectl cbindex <command>".
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type command struct {
+	Usage       string
+	Description string
+	Run         func(ctx context.Context, args []string, out io.Writer) error
+}
+
+var commands = map[string]command{
+	"advice": {"advice NAME", "Show Index Advisor recommendations for the workload queries", runAdvice},
+	"status": {"status NAME", "Show the sync state of each index and the last Job's failure reason", runStatus},
+	"diff":   {"diff NAME [-f FILE] [--live]", "Compare the spec with the last-applied spec or the bucket's indexes, or a local manifest with the spec", runDiff},
+	"sync":   {"sync NAME", "Force an immediate sync", runSync},
+	"pause":  {"pause NAME", "Pause index synchronization", runPause},
+	"resume": {"resume NAME", "Resume index synchronization", runResume},
+	"logs":   {"logs NAME [-f]", "Show logs from the latest sync Job", runLogs},
+	"export": {"export NAME [--expand] [--format yaml|cbim|n1ql]", "Export the index set as a manifest", runExport},
+	"import": {"import FILE... [--format auto|cbim|n1ql|json]", "Convert existing index definitions to index set manifests", runImport},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(1)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage(os.Stdout)
+		return
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(1)
+	}
+
+	if err := cmd.Run(context.Background(), os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage(out io.Writer) {
+	fmt.Fprintln(out, "Usage: kubectl cbindex COMMAND [options]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-50s %s\n", commands[name].Usage, commands[name].Description)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Common options: -n/--namespace, --context, --kubeconfig, --cluster (for index sets targeting multiple clusters)")
+	fmt.Fprintln(out, "Commands which read the bucket accept --connection-string to override the index set's connection")
+}
+
+// Options shared by all commands
+type globalOptions struct {
+	Namespace   string
+	Context     string
+	Kubeconfig  string
+	ClusterName string
+}
+
+func newFlagSet(name string, options *globalOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	fs.StringVar(&options.Namespace, "namespace", "", "Namespace of the index set, defaults to the current context's namespace")
+	fs.StringVar(&options.Namespace, "n", "", "Shorthand for --namespace")
+	fs.StringVar(&options.Context, "context", "", "Kubeconfig context to use")
+	fs.StringVar(&options.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+	fs.StringVar(&options.ClusterName, "cluster", "", "Name of the cluster within an index set targeting multiple clusters")
+
+	return fs
+}
+
+// Parses flags which may be interspersed with positional arguments, returning the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// Parses the arguments for a command which accepts a single index set name
+func parseNameArgs(fs *flag.FlagSet, args []string) (string, error) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(positional) != 1 {
+		return "", fmt.Errorf("expected an index set name, found %s", formatArgs(positional))
+	}
+
+	return positional[0], nil
+}
+
+func formatArgs(args []string) string {
+	if len(args) == 0 {
+		return "none"
+	}
+
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+func runPause(ctx context.Context, args []string, out io.Writer) error {
+	return setPaused(ctx, "pause", args, out, true)
+}
+
+func runResume(ctx context.Context, args []string, out io.Writer) error {
+	return setPaused(ctx, "resume", args, out, false)
+}
+
+// Sets spec.paused on an index set using a merge patch
+func setPaused(ctx context.Context, commandName string, args []string, out io.Writer, paused bool) error {
+	options := globalOptions{}
+	name, err := parseNameArgs(newFlagSet(commandName, &options), args)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClients(&options)
+	if err != nil {
+		return err
+	}
+
+	indexSet := couchbasev1beta1.CouchbaseIndexSet{}
+	indexSet.Namespace = c.Namespace
+	indexSet.Name = name
+
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"paused":%t}}`, paused)))
+	if err := c.Patch(ctx, &indexSet, patch); err != nil {
+		return err
+	}
+
+	action := "resumed"
+	if paused {
+		action = "paused"
+	}
+
+	fmt.Fprintf(out, "couchbaseindexset/%s %s\n", name, action)
+	return nil
+}
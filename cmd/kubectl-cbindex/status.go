@@ -0,0 +1,341 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+// States reported for each index
+const (
+	indexStateSynced      = "Synced"
+	indexStatePending     = "Pending"
+	indexStateDropping    = "Dropping"
+	indexStatePendingDrop = "PendingDrop"
+	indexStateInUse       = "InUse"
+)
+
+type indexState struct {
+	Name      string
+	State     string
+	Protected bool
+	Detail    string
+}
+
+// Observed state of an index set, or a single cluster within an index set targeting multiple clusters
+type observedState struct {
+	Conditions       []metav1.Condition
+	Indices          []string
+	ProtectedIndices []string
+	PendingDrops     []couchbasev1beta1.PendingIndexDrop
+	InUseIndices     []string
+}
+
+func getObservedState(indexSet *couchbasev1beta1.CouchbaseIndexSet, clusterName string) observedState {
+	if clusterName != "" {
+		if clusterStatus := getClusterStatus(indexSet, clusterName); clusterStatus != nil {
+			return observedState{
+				Conditions:       clusterStatus.Conditions,
+				Indices:          clusterStatus.Indices,
+				ProtectedIndices: clusterStatus.ProtectedIndices,
+				PendingDrops:     clusterStatus.PendingDrops,
+				InUseIndices:     clusterStatus.InUseIndices,
+			}
+		}
+
+		return observedState{}
+	}
+
+	return observedState{
+		Conditions:       indexSet.Status.Conditions,
+		Indices:          indexSet.Status.Indices,
+		ProtectedIndices: indexSet.Status.ProtectedIndices,
+		PendingDrops:     indexSet.Status.PendingDrops,
+		InUseIndices:     indexSet.Status.InUseIndices,
+	}
+}
+
+// Expands the templates instantiated by an index set and merges any indices defined as DDL. Indices targeting
+// multiple scopes or collections are returned unexpanded, since expanding them requires the collections in the bucket.
+func (c *clients) expandIndices(ctx context.Context, indexSet *couchbasev1beta1.CouchbaseIndexSet) ([]couchbasev1beta1.GlobalSecondaryIndex, error) {
+	templates := map[string]*couchbasev1beta1.CouchbaseIndexTemplate{}
+	for _, instance := range indexSet.Spec.Templates {
+		if _, ok := templates[instance.TemplateName]; ok {
+			continue
+		}
+
+		template := couchbasev1beta1.CouchbaseIndexTemplate{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: indexSet.Namespace, Name: instance.TemplateName}, &template); err != nil {
+			return nil, err
+		}
+
+		templates[instance.TemplateName] = &template
+	}
+
+	indices, err := cbim.ExpandIndices(indexSet, templates)
+	if err != nil || indexSet.Spec.Ddl == nil {
+		return indices, err
+	}
+
+	configMapScript := ""
+	if ref := indexSet.Spec.Ddl.ConfigMapRef; ref != nil {
+		configMap := corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: indexSet.Namespace, Name: ref.Name}, &configMap); err != nil {
+			return nil, err
+		}
+
+		configMapScript = configMap.Data[ref.Key]
+	}
+
+	ddlIndices, err := cbim.ParseIndexSetDdl(indexSet, configMapScript)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DDL: %w", err)
+	}
+
+	return cbim.MergeIndices(indices, ddlIndices)
+}
+
+// Determines the state of each index from the desired indices and the observed state
+func getIndexStates(indices []couchbasev1beta1.GlobalSecondaryIndex, observed observedState) []indexState {
+	desired := map[string]bool{}
+	fanOutNames := map[string]bool{}
+	for _, gsi := range indices {
+		if cbim.IsFanOutIndex(gsi) {
+			fanOutNames[gsi.Name] = true
+		} else {
+			desired[cbim.GetIndexIdentifier(gsi).ToString()] = true
+		}
+	}
+
+	applied := toSet(observed.Indices)
+	protected := toSet(observed.ProtectedIndices)
+	inUse := toSet(observed.InUseIndices)
+
+	pendingDrops := map[string]metav1.Time{}
+	for _, drop := range observed.PendingDrops {
+		pendingDrops[drop.Name] = drop.DropAfter
+	}
+
+	names := map[string]bool{}
+	for _, set := range []map[string]bool{desired, applied, inUse} {
+		for name := range set {
+			names[name] = true
+		}
+	}
+	for name := range pendingDrops {
+		names[name] = true
+	}
+
+	result := make([]indexState, 0, len(names))
+	for name := range names {
+		state := indexState{Name: name, Protected: protected[name]}
+
+		identifier, _ := cbim.ParseIndexIdentifierString(name)
+		isDesired := desired[name] || fanOutNames[identifier.Name]
+
+		switch dropAfter, isPendingDrop := pendingDrops[name]; {
+		case isDesired && applied[name]:
+			state.State = indexStateSynced
+		case isDesired:
+			state.State = indexStatePending
+		case inUse[name]:
+			state.State = indexStateInUse
+			state.Detail = "Drop held, index was recently used"
+		case isPendingDrop:
+			state.State = indexStatePendingDrop
+			state.Detail = "Drop after " + dropAfter.UTC().Format(time.RFC3339)
+		default:
+			state.State = indexStateDropping
+		}
+
+		if state.Protected && !isDesired {
+			state.Detail = "Drop blocked, index is protected"
+		}
+
+		result = append(result, state)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func toSet(values []string) map[string]bool {
+	result := make(map[string]bool, len(values))
+	for _, value := range values {
+		result[value] = true
+	}
+
+	return result
+}
+
+// Describes the result of a Job, including the failure reason if it failed
+func describeJob(job *batchv1.Job, pod *corev1.Pod) string {
+	if job == nil {
+		return "<none>"
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return fmt.Sprintf("%s Complete", job.Name)
+
+		case batchv1.JobFailed:
+			description := fmt.Sprintf("%s Failed: %s", job.Name, condition.Reason)
+			if condition.Message != "" {
+				description += ", " + condition.Message
+			}
+
+			if reason := getPodFailureReason(pod); reason != "" {
+				description += "\n  " + reason
+			}
+
+			return description
+		}
+	}
+
+	return fmt.Sprintf("%s Running", job.Name)
+}
+
+// Gets the reason the sync container in a Pod terminated unsuccessfully, or an empty string
+func getPodFailureReason(pod *corev1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		terminated := status.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+
+		reason := fmt.Sprintf("Pod %s exited with code %d", pod.Name, terminated.ExitCode)
+		if terminated.Reason != "" {
+			reason += " (" + terminated.Reason + ")"
+		}
+		if message := strings.TrimSpace(terminated.Message); message != "" {
+			reason += ": " + message
+		}
+
+		return reason
+	}
+
+	return ""
+}
+
+func describeCondition(conditions []metav1.Condition, conditionType string) string {
+	condition := apimeta.FindStatusCondition(conditions, conditionType)
+	if condition == nil {
+		return "Unknown"
+	}
+
+	description := string(condition.Status)
+	if condition.Reason != "" {
+		description += " (" + condition.Reason + ")"
+	}
+	if condition.Message != "" {
+		description += " " + condition.Message
+	}
+
+	return description
+}
+
+func runStatus(ctx context.Context, args []string, out io.Writer) error {
+	options := globalOptions{}
+	name, err := parseNameArgs(newFlagSet("status", &options), args)
+	if err != nil {
+		return err
+	}
+
+	c, err := newClients(&options)
+	if err != nil {
+		return err
+	}
+
+	indexSet, err := c.getIndexSet(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	indices, err := c.expandIndices(ctx, indexSet)
+	if err != nil {
+		return err
+	}
+
+	paused := indexSet.Spec.Paused != nil && *indexSet.Spec.Paused
+
+	fmt.Fprintf(out, "Name:    %s\n", indexSet.Name)
+	fmt.Fprintf(out, "Bucket:  %s\n", indexSet.Spec.BucketName)
+	fmt.Fprintf(out, "Paused:  %t\n", paused)
+
+	for _, clusterName := range getClusterNames(indexSet, options.ClusterName) {
+		observed := getObservedState(indexSet, clusterName)
+
+		job, err := c.getLatestJob(ctx, indexSet, clusterName)
+		if err != nil {
+			return err
+		}
+
+		var pod *corev1.Pod
+		if job != nil {
+			if pod, err = c.getLatestPod(ctx, job); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintln(out)
+		if clusterName != "" {
+			fmt.Fprintf(out, "Cluster: %s\n", clusterName)
+		}
+		fmt.Fprintf(out, "Ready:    %s\n", describeCondition(observed.Conditions, "Ready"))
+		fmt.Fprintf(out, "Syncing:  %s\n", describeCondition(observed.Conditions, "Syncing"))
+		fmt.Fprintf(out, "Last Job: %s\n", describeJob(job, pod))
+		fmt.Fprintln(out)
+
+		w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "INDEX\tSTATE\tPROTECTED\tDETAIL")
+		for _, state := range getIndexStates(indices, observed) {
+			fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", state.Name, state.State, state.Protected, state.Detail)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
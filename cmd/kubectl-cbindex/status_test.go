@@ -0,0 +1,130 @@
+package main
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("getIndexStates", func() {
+
+	It("should report the state of each index", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "synced"},
+			{Name: "pending"},
+			{Name: "scoped", ScopeName: pointer.StringPtr("inventory"), CollectionName: pointer.StringPtr("airline")},
+		}
+
+		dropAfter := metav1.NewTime(time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC))
+		observed := observedState{
+			Indices:          []string{"synced", "inventory.airline.scoped", "removed", "waiting", "used"},
+			ProtectedIndices: []string{"synced", "removed"},
+			PendingDrops:     []couchbasev1beta1.PendingIndexDrop{{Name: "waiting", DropAfter: dropAfter}},
+			InUseIndices:     []string{"used"},
+		}
+
+		// Act
+
+		result := getIndexStates(indices, observed)
+
+		// Assert
+
+		Expect(result).To(Equal([]indexState{
+			{Name: "inventory.airline.scoped", State: indexStateSynced},
+			{Name: "pending", State: indexStatePending},
+			{Name: "removed", State: indexStateDropping, Protected: true, Detail: "Drop blocked, index is protected"},
+			{Name: "synced", State: indexStateSynced, Protected: true},
+			{Name: "used", State: indexStateInUse, Detail: "Drop held, index was recently used"},
+			{Name: "waiting", State: indexStatePendingDrop, Detail: "Drop after 2021-06-01T12:00:00Z"},
+		}))
+	})
+
+	It("should match indices targeting multiple collections by name", func() {
+		// Arrange
+
+		indices := []couchbasev1beta1.GlobalSecondaryIndex{
+			{Name: "by_type", CollectionNames: []string{"*"}},
+		}
+
+		observed := observedState{
+			Indices: []string{"inventory.airline.by_type", "inventory.hotel.by_type"},
+		}
+
+		// Act
+
+		result := getIndexStates(indices, observed)
+
+		// Assert
+
+		Expect(result).To(Equal([]indexState{
+			{Name: "inventory.airline.by_type", State: indexStateSynced},
+			{Name: "inventory.hotel.by_type", State: indexStateSynced},
+		}))
+	})
+})
+
+var _ = Describe("parseArgs", func() {
+
+	It("should allow flags after positional arguments", func() {
+		// Arrange
+
+		options := globalOptions{}
+		fs := newFlagSet("test", &options)
+
+		// Act
+
+		result, err := parseArgs(fs, []string{"sample", "-n", "couchbase", "--cluster", "east"})
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal([]string{"sample"}))
+		Expect(options.Namespace).To(Equal("couchbase"))
+		Expect(options.ClusterName).To(Equal("east"))
+	})
+})
+
+var _ = Describe("getExportManifest", func() {
+
+	It("should remove server populated fields", func() {
+		// Arrange
+
+		indexSet := couchbasev1beta1.CouchbaseIndexSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "sample",
+				Namespace:       "couchbase",
+				UID:             "1234",
+				ResourceVersion: "5",
+				Generation:      2,
+				Annotations: map[string]string{
+					lastAppliedAnnotation: "{}",
+				},
+			},
+			Spec: couchbasev1beta1.CouchbaseIndexSetSpec{
+				BucketName: "default",
+			},
+			Status: couchbasev1beta1.CouchbaseIndexSetStatus{
+				Indices: []string{"idx"},
+			},
+		}
+
+		// Act
+
+		result := getExportManifest(&indexSet)
+
+		// Assert
+
+		Expect(result.Kind).To(Equal("CouchbaseIndexSet"))
+		Expect(result.APIVersion).To(Equal("couchbase.btburnett.com/v1beta1"))
+		Expect(result.ObjectMeta).To(Equal(metav1.ObjectMeta{Name: "sample", Namespace: "couchbase"}))
+		Expect(result.Spec.BucketName).To(Equal("default"))
+		Expect(result.Status.Indices).To(BeEmpty())
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Annotation which requests an immediate sync when its value changes
+const syncRequestedAtAnnotation = "couchbase.btburnett.com/sync-requested-at"
+
+// Builds a merge patch which sets the sync-requested-at annotation
+func getSyncRequestPatch(requestedAt time.Time) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				syncRequestedAtAnnotation: requestedAt.UTC().Format(time.RFC3339Nano),
+			},
+		},
+	})
+}
+
+// Forces a sync by setting the sync-requested-at annotation, the operator starts a sync immediately rather than
+// waiting for the next scheduled sync. If a sync is already running, another starts once it completes.
+func (c *clients) forceSync(ctx context.Context, name string) error {
+	patch, err := getSyncRequestPatch(time.Now())
+	if err != nil {
+		return err
+	}
+
+	indexSet := couchbasev1beta1.CouchbaseIndexSet{}
+	indexSet.Namespace = c.Namespace
+	indexSet.Name = name
+
+	return c.Patch(ctx, &indexSet, client.RawPatch(types.MergePatchType, patch))
+}
+
+func runSync(ctx context.Context, args []string, out io.Writer) error {
+	options := globalOptions{}
+	name, err := parseNameArgs(newFlagSet("sync", &options), args)
+	if err != nil {
+		return err
+	}
+
+	if options.ClusterName != "" {
+		return errors.New("sync requests apply to every cluster of the index set, --cluster is not supported")
+	}
+
+	c, err := newClients(&options)
+	if err != nil {
+		return err
+	}
+
+	indexSet, err := c.getIndexSet(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if indexSet.Spec.Paused != nil && *indexSet.Spec.Paused {
+		return fmt.Errorf("index set %s is paused, resume it before syncing", name)
+	}
+
+	if err := c.forceSync(ctx, name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "couchbaseindexset/%s sync requested\n", name)
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("getSyncRequestPatch", func() {
+
+	It("should set the annotation", func() {
+		// Arrange
+
+		requestedAt := time.Date(2021, 6, 1, 12, 30, 0, 500, time.FixedZone("EDT", -4*60*60))
+
+		// Act
+
+		result, err := getSyncRequestPatch(requestedAt)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(string(result)).To(Equal(
+			`{"metadata":{"annotations":{"couchbase.btburnett.com/sync-requested-at":"2021-06-01T16:30:00.0000005Z"}}}`))
+	})
+})
@@ -0,0 +1,180 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+	couchbasev2 "github.com/brantburnett/couchbase-index-operator/couchbase/v2"
+)
+
+// Key of the CA certificates within a CA secret
+const caCertificateKey = "ca.crt"
+
+// Connection information for a Couchbase cluster
+type clusterConnection struct {
+	ConnectionString string
+	AdminSecretName  string
+	// Optional secret containing CA certificates used to verify the cluster
+	CASecretName string
+}
+
+// Gets connection information for a CouchbaseCluster resource. If the cluster is not ready for use, returns a message
+// describing the problem instead. The bucket is not checked if the bucket name is empty.
+func getClusterRefConnection(ctx context.Context, reader client.Reader, namespace string,
+	clusterRef *v1beta1.CouchbaseClusterRef, bucketName string) (clusterConnection, string, error) {
+
+	clusterName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      clusterRef.Name,
+	}
+
+	cluster := couchbasev2.CouchbaseCluster{}
+	if err := reader.Get(ctx, clusterName, &cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return clusterConnection{}, "Cluster is not found", nil
+		}
+
+		return clusterConnection{}, "", err
+	}
+
+	connection := clusterConnection{
+		ConnectionString: fmt.Sprintf("couchbase://%s-srv", cluster.ObjectMeta.Name),
+	}
+	if clusterRef.SecretName != nil {
+		// Prefer the secret name if provided in our spec
+		connection.AdminSecretName = *clusterRef.SecretName
+	} else {
+		// Fallback to the admin secret name
+		connection.AdminSecretName = cluster.Spec.Security.AdminSecret
+	}
+
+	available := false
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == "Available" && condition.Status == v1.ConditionTrue {
+			available = true
+			break
+		}
+	}
+
+	if !available {
+		return connection, "Cluster is not available", nil
+	}
+
+	if bucketName != "" && cluster.Spec.Buckets.Managed {
+		// If the cluster is managing buckets, we can confirm the bucket exists via the cluster status
+
+		foundBucket := false
+		for _, bucket := range cluster.Status.Buckets {
+			if bucket.Name == bucketName {
+				foundBucket = true
+				break
+			}
+		}
+
+		if !foundBucket {
+			return connection, "Bucket is not found", nil
+		}
+	}
+
+	return connection, "", nil
+}
+
+// Creates a client for the Couchbase REST APIs using the credentials in the admin secret
+func newRestClient(ctx context.Context, reader client.Reader, namespace string, connection clusterConnection) (*cbrest.Client, error) {
+	secretName := types.NamespacedName{
+		Namespace: namespace,
+		Name:      connection.AdminSecretName,
+	}
+
+	secret := corev1.Secret{}
+	if err := reader.Get(ctx, secretName, &secret); err != nil {
+		return nil, err
+	}
+
+	restClient, err := cbrest.NewClient(connection.ConnectionString, string(secret.Data["username"]), string(secret.Data["password"]))
+	if err != nil {
+		return nil, err
+	}
+
+	if connection.CASecretName != "" {
+		caSecretName := types.NamespacedName{
+			Namespace: namespace,
+			Name:      connection.CASecretName,
+		}
+
+		caSecret := corev1.Secret{}
+		if err := reader.Get(ctx, caSecretName, &caSecret); err != nil {
+			return nil, err
+		}
+
+		if err := restClient.SetCACertificates(caSecret.Data[caCertificateKey]); err != nil {
+			return nil, fmt.Errorf("secret %s: %w", connection.CASecretName, err)
+		}
+	}
+
+	return restClient, nil
+}
+
+// Gets connection information for a cluster targeted by a resource. If the cluster is not ready for use, returns a
+// message describing the problem instead.
+func getClusterConnection(ctx context.Context, reader client.Reader, namespace string, cluster v1beta1.CouchbaseCluster,
+	bucketName string) (clusterConnection, string, error) {
+
+	if cluster.ClusterRef != nil {
+		return getClusterRefConnection(ctx, reader, namespace, cluster.ClusterRef, bucketName)
+	} else if cluster.Manual != nil {
+		connection := clusterConnection{
+			ConnectionString: cluster.Manual.ConnectionString,
+			AdminSecretName:  cluster.Manual.SecretName,
+		}
+		if cluster.Manual.CASecretName != nil {
+			connection.CASecretName = *cluster.Manual.CASecretName
+		}
+
+		return connection, "", nil
+	}
+
+	return clusterConnection{}, "Missing connection info", nil
+}
+
+// Creates a client for the Couchbase REST APIs for resources which are synced directly by the operator. If the cluster
+// is not ready for use, returns a nil client and a message describing the problem instead.
+func getDirectRestClient(ctx context.Context, reader client.Reader, namespace string, cluster v1beta1.CouchbaseCluster,
+	bucketName string) (*cbrest.Client, string, error) {
+
+	connection, message, err := getClusterConnection(ctx, reader, namespace, cluster, bucketName)
+	if err != nil || message != "" {
+		return nil, message, err
+	}
+
+	restClient, err := newRestClient(ctx, reader, namespace, connection)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return restClient, "", nil
+}
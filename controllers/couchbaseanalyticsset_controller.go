@@ -0,0 +1,491 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+	"github.com/brantburnett/couchbase-index-operator/sqlpp"
+)
+
+const analyticsSetFinalizer string = "couchbase.btburnett.com/analytics"
+
+// CouchbaseAnalyticsSetReconciler reconciles a CouchbaseAnalyticsSet object
+type CouchbaseAnalyticsSetReconciler struct {
+	client.Client
+	record.EventRecorder
+	Scheme *runtime.Scheme
+}
+
+// Tracks the links, datasets, or indices applied by an Analytics set, keyed by qualified name
+type appliedAnalyticsDefinitions map[string]v1beta1.AppliedAnalyticsDefinition
+
+// Current state of the Analytics set being synced
+type analyticsSyncState struct {
+	restClient *cbrest.Client
+	links      appliedAnalyticsDefinitions
+	datasets   appliedAnalyticsDefinitions
+	indices    appliedAnalyticsDefinitions
+}
+
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseanalyticssets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseanalyticssets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseanalyticssets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=couchbase.com,namespace=system,resources=couchbaseclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch
+
+// Reconcile applies Analytics links, datasets, and indices through the Analytics service, dropping any which are
+// removed. Syncs follow the same timing as index set sync jobs.
+func (r *CouchbaseAnalyticsSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	analyticsSet := v1beta1.CouchbaseAnalyticsSet{}
+	if err := r.Get(ctx, req.NamespacedName, &analyticsSet); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return reconcileDirectSync(ctx, r.Client, r.EventRecorder, &directSyncResource{
+		object:        &analyticsSet,
+		finalizer:     analyticsSetFinalizer,
+		conditions:    &analyticsSet.Status.Conditions,
+		lastSyncTime:  &analyticsSet.Status.LastSyncTime,
+		cluster:       analyticsSet.Spec.Cluster,
+		paused:        analyticsSet.Spec.Paused != nil && *analyticsSet.Spec.Paused,
+		pausedMessage: "Analytics synchronization is paused",
+		inSyncMessage: "Analytics definitions are in sync",
+		sync: func(ctx context.Context, restClient *cbrest.Client, isDeleting bool) (string, error) {
+			return "", r.syncAnalytics(ctx, restClient, &analyticsSet, isDeleting)
+		},
+	})
+}
+
+func (r *CouchbaseAnalyticsSetReconciler) syncAnalytics(ctx context.Context, restClient *cbrest.Client,
+	analyticsSet *v1beta1.CouchbaseAnalyticsSet, isDeleting bool) error {
+
+	state := analyticsSyncState{
+		restClient: restClient,
+		links:      newAppliedAnalyticsDefinitions(analyticsSet.Status.Links),
+		datasets:   newAppliedAnalyticsDefinitions(analyticsSet.Status.Datasets),
+		indices:    newAppliedAnalyticsDefinitions(analyticsSet.Status.Indices),
+	}
+
+	var err error
+	if isDeleting {
+		err = state.dropAll(ctx)
+	} else {
+		err = r.applyAll(ctx, analyticsSet, &state)
+	}
+
+	// Track progress even if some changes failed
+	analyticsSet.Status.Links = state.links.toSortedList()
+	analyticsSet.Status.Datasets = state.datasets.toSortedList()
+	analyticsSet.Status.Indices = state.indices.toSortedList()
+
+	return err
+}
+
+// Applies links, then datasets, then indices. Datasets and indices can't be altered, so changed definitions are
+// dropped and recreated. Removed indices and datasets are dropped before creating new ones, and removed links are
+// dropped last once no datasets use them. Unchanged definitions are skipped unless they no longer exist.
+func (r *CouchbaseAnalyticsSetReconciler) applyAll(ctx context.Context, analyticsSet *v1beta1.CouchbaseAnalyticsSet, state *analyticsSyncState) error {
+	spec := analyticsSet.Spec
+
+	if err := r.createDataverses(ctx, analyticsSet, state); err != nil {
+		return err
+	}
+
+	existingLinks, err := state.getExisting(ctx, "SELECT VALUE l.DataverseName || '.' || l.Name FROM Metadata.`Link` l")
+	if err != nil {
+		return fmt.Errorf("unable to get links: %w", err)
+	}
+
+	definedLinks := map[string]bool{}
+	for _, link := range spec.Links {
+		dataverseName := sqlpp.GetDataverseName(link.DataverseName)
+		key := dataverseName + "." + link.Name
+		definedLinks[key] = true
+
+		settings, hash, err := r.getLinkSettings(ctx, analyticsSet.Namespace, link)
+		if err != nil {
+			return fmt.Errorf("link %s: %w", key, err)
+		}
+
+		if existingLinks[key] && state.links[key].DefinitionHash == hash {
+			continue
+		}
+
+		if err := state.restClient.PutAnalyticsLink(ctx, dataverseName, link.Name, settings, existingLinks[key]); err != nil {
+			return fmt.Errorf("unable to apply link %s: %w", key, err)
+		}
+
+		r.Eventf(analyticsSet, "Normal", "LinkApplied", "Applied link %s", key)
+		state.links[key] = v1beta1.AppliedAnalyticsDefinition{Name: key, DefinitionHash: hash}
+	}
+
+	definedIndices := map[string]string{}
+	for _, index := range spec.Indices {
+		key := strings.Join([]string{sqlpp.GetDataverseName(index.DataverseName), index.DatasetName, index.Name}, ".")
+		definedIndices[key] = sqlpp.CreateAnalyticsIndexStatement(index)
+	}
+
+	for key := range state.indices {
+		if _, ok := definedIndices[key]; !ok {
+			if err := state.dropIndex(ctx, key); err != nil {
+				return err
+			}
+
+			r.Eventf(analyticsSet, "Normal", "IndexDropped", "Dropped index %s", key)
+		}
+	}
+
+	definedDatasets := map[string]string{}
+	for _, dataset := range spec.Datasets {
+		key := sqlpp.GetDataverseName(dataset.DataverseName) + "." + dataset.Name
+		definedDatasets[key] = sqlpp.CreateDatasetStatement(dataset)
+	}
+
+	for key := range state.datasets {
+		if _, ok := definedDatasets[key]; !ok {
+			if err := state.dropDataset(ctx, key); err != nil {
+				return err
+			}
+
+			r.Eventf(analyticsSet, "Normal", "DatasetDropped", "Dropped dataset %s", key)
+		}
+	}
+
+	existingDatasets, err := state.getExisting(ctx, "SELECT VALUE d.DataverseName || '.' || d.DatasetName FROM Metadata.`Dataset` d")
+	if err != nil {
+		return fmt.Errorf("unable to get datasets: %w", err)
+	}
+
+	connectLinks := map[string]bool{}
+	for _, dataset := range spec.Datasets {
+		dataverseName := sqlpp.GetDataverseName(dataset.DataverseName)
+		key := dataverseName + "." + dataset.Name
+		statement := definedDatasets[key]
+		hash := getDefinitionHash(statement)
+
+		if existingDatasets[key] && state.datasets[key].DefinitionHash == hash {
+			continue
+		}
+
+		if existingDatasets[key] {
+			// Datasets can't be altered, this also drops any indices on the dataset
+			if err := state.dropDataset(ctx, key); err != nil {
+				return err
+			}
+		}
+
+		if _, err := state.restClient.AnalyticsQuery(ctx, statement); err != nil {
+			return fmt.Errorf("unable to create dataset %s: %w", key, err)
+		}
+
+		r.Eventf(analyticsSet, "Normal", "DatasetApplied", "Applied dataset %s", key)
+		state.datasets[key] = v1beta1.AppliedAnalyticsDefinition{Name: key, DefinitionHash: hash}
+
+		linkName := sqlpp.LocalLinkName
+		if dataset.LinkName != nil {
+			linkName = *dataset.LinkName
+		}
+		connectLinks[dataverseName+"."+linkName] = true
+	}
+
+	// Connect links with new datasets to start ingestion, this has no effect on links which are already connected
+	for key := range connectLinks {
+		dataverseName, linkName := splitAnalyticsKey(key)
+		if _, err := state.restClient.AnalyticsQuery(ctx, sqlpp.ConnectLinkStatement(dataverseName, linkName)); err != nil {
+			return fmt.Errorf("unable to connect link %s: %w", key, err)
+		}
+	}
+
+	// Get indices after applying datasets, recreated datasets will have lost their indices
+	existingIndices, err := state.getExisting(ctx,
+		"SELECT VALUE i.DataverseName || '.' || i.DatasetName || '.' || i.IndexName FROM Metadata.`Index` i WHERE i.IsPrimary = false")
+	if err != nil {
+		return fmt.Errorf("unable to get indices: %w", err)
+	}
+
+	for _, index := range spec.Indices {
+		key := strings.Join([]string{sqlpp.GetDataverseName(index.DataverseName), index.DatasetName, index.Name}, ".")
+		statement := definedIndices[key]
+		hash := getDefinitionHash(statement)
+
+		if existingIndices[key] && state.indices[key].DefinitionHash == hash {
+			continue
+		}
+
+		if existingIndices[key] {
+			if err := state.dropIndex(ctx, key); err != nil {
+				return err
+			}
+		}
+
+		if _, err := state.restClient.AnalyticsQuery(ctx, statement); err != nil {
+			return fmt.Errorf("unable to create index %s: %w", key, err)
+		}
+
+		r.Eventf(analyticsSet, "Normal", "IndexApplied", "Applied index %s", key)
+		state.indices[key] = v1beta1.AppliedAnalyticsDefinition{Name: key, DefinitionHash: hash}
+	}
+
+	for key := range state.links {
+		if !definedLinks[key] {
+			if err := state.dropLink(ctx, key); err != nil {
+				return err
+			}
+
+			r.Eventf(analyticsSet, "Normal", "LinkDropped", "Dropped link %s", key)
+		}
+	}
+
+	return nil
+}
+
+// Drops all indices, datasets, and links managed by the Analytics set. Dataverses are left in place.
+func (state *analyticsSyncState) dropAll(ctx context.Context) error {
+	for key := range state.indices {
+		if err := state.dropIndex(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	for key := range state.datasets {
+		if err := state.dropDataset(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	for key := range state.links {
+		if err := state.dropLink(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Creates any dataverses used by the Analytics set which don't exist, other than the Default dataverse
+func (r *CouchbaseAnalyticsSetReconciler) createDataverses(ctx context.Context, analyticsSet *v1beta1.CouchbaseAnalyticsSet, state *analyticsSyncState) error {
+	dataverses := map[string]bool{}
+	for _, link := range analyticsSet.Spec.Links {
+		dataverses[sqlpp.GetDataverseName(link.DataverseName)] = true
+	}
+	for _, dataset := range analyticsSet.Spec.Datasets {
+		dataverses[sqlpp.GetDataverseName(dataset.DataverseName)] = true
+	}
+
+	delete(dataverses, sqlpp.DefaultDataverseName)
+	if len(dataverses) == 0 {
+		return nil
+	}
+
+	existing, err := state.getExisting(ctx, "SELECT VALUE d.DataverseName FROM Metadata.`Dataverse` d")
+	if err != nil {
+		return fmt.Errorf("unable to get dataverses: %w", err)
+	}
+
+	for dataverseName := range dataverses {
+		if existing[dataverseName] {
+			continue
+		}
+
+		if _, err := state.restClient.AnalyticsQuery(ctx, sqlpp.CreateDataverseStatement(dataverseName)); err != nil {
+			return fmt.Errorf("unable to create dataverse %s: %w", dataverseName, err)
+		}
+	}
+
+	return nil
+}
+
+// Builds the REST API settings for a link along with a hash to detect changes. The hash includes the resource
+// version of the secret rather than the credentials.
+func (r *CouchbaseAnalyticsSetReconciler) getLinkSettings(ctx context.Context, namespace string, link v1beta1.AnalyticsLink) (url.Values, string, error) {
+	var (
+		settings   url.Values
+		secretName string
+	)
+	if link.Couchbase != nil && link.S3 == nil {
+		encryption := "none"
+		if link.Couchbase.Encryption != nil {
+			encryption = strings.ToLower(*link.Couchbase.Encryption)
+		}
+
+		settings = url.Values{
+			"type":       {"couchbase"},
+			"hostname":   {link.Couchbase.Hostname},
+			"encryption": {encryption},
+		}
+		secretName = link.Couchbase.SecretName
+	} else if link.S3 != nil && link.Couchbase == nil {
+		settings = url.Values{
+			"type":   {"s3"},
+			"region": {link.S3.Region},
+		}
+		if link.S3.ServiceEndpoint != nil {
+			settings.Set("serviceEndpoint", *link.S3.ServiceEndpoint)
+		}
+		secretName = link.S3.SecretName
+	} else {
+		return nil, "", errors.New("link must have exactly one of couchbase or s3")
+	}
+
+	secret := corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, &secret); err != nil {
+		return nil, "", fmt.Errorf("unable to get secret %s: %w", secretName, err)
+	}
+
+	hash := getDefinitionHash(settings.Encode() + "&secretVersion=" + secret.ResourceVersion)
+
+	if link.Couchbase != nil {
+		settings.Set("username", string(secret.Data["username"]))
+		settings.Set("password", string(secret.Data["password"]))
+	} else {
+		settings.Set("accessKeyId", string(secret.Data["accessKeyId"]))
+		settings.Set("secretAccessKey", string(secret.Data["secretAccessKey"]))
+	}
+
+	return settings, hash, nil
+}
+
+// Gets a set of qualified names returned by a metadata query
+func (state *analyticsSyncState) getExisting(ctx context.Context, statement string) (map[string]bool, error) {
+	results, err := state.restClient.AnalyticsQuery(ctx, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	for _, result := range results {
+		var key string
+		if err := json.Unmarshal(result, &key); err == nil {
+			existing[key] = true
+		}
+	}
+
+	return existing, nil
+}
+
+func (state *analyticsSyncState) dropIndex(ctx context.Context, key string) error {
+	split := strings.SplitN(key, ".", 3)
+	if len(split) != 3 {
+		delete(state.indices, key)
+		return nil
+	}
+
+	if _, err := state.restClient.AnalyticsQuery(ctx, sqlpp.DropAnalyticsIndexStatement(split[0], split[1], split[2])); err != nil {
+		return fmt.Errorf("unable to drop index %s: %w", key, err)
+	}
+
+	delete(state.indices, key)
+	return nil
+}
+
+func (state *analyticsSyncState) dropDataset(ctx context.Context, key string) error {
+	dataverseName, name := splitAnalyticsKey(key)
+	if _, err := state.restClient.AnalyticsQuery(ctx, sqlpp.DropDatasetStatement(dataverseName, name)); err != nil {
+		return fmt.Errorf("unable to drop dataset %s: %w", key, err)
+	}
+
+	// Indices are dropped along with the dataset
+	for indexKey := range state.indices {
+		if strings.HasPrefix(indexKey, key+".") {
+			delete(state.indices, indexKey)
+		}
+	}
+
+	delete(state.datasets, key)
+	return nil
+}
+
+func (state *analyticsSyncState) dropLink(ctx context.Context, key string) error {
+	dataverseName, name := splitAnalyticsKey(key)
+
+	// Links must be disconnected before they are dropped. Disconnecting fails if the link was never connected, so
+	// the error is only reported if the link can't be dropped.
+	_, disconnectErr := state.restClient.AnalyticsQuery(ctx, sqlpp.DisconnectLinkStatement(dataverseName, name))
+	if disconnectErr != nil {
+		log.FromContext(ctx).V(1).Info("Unable to disconnect link", "link", key, "error", disconnectErr.Error())
+	}
+
+	if err := state.restClient.DeleteAnalyticsLink(ctx, dataverseName, name); err != nil {
+		if disconnectErr != nil {
+			return fmt.Errorf("unable to drop link %s: %w, after failing to disconnect it: %s", key, err, disconnectErr)
+		}
+
+		return fmt.Errorf("unable to drop link %s: %w", key, err)
+	}
+
+	delete(state.links, key)
+	return nil
+}
+
+func splitAnalyticsKey(key string) (string, string) {
+	// Dataverse names may not contain periods
+	split := strings.SplitN(key, ".", 2)
+	if len(split) != 2 {
+		return sqlpp.DefaultDataverseName, key
+	}
+
+	return split[0], split[1]
+}
+
+func newAppliedAnalyticsDefinitions(list []v1beta1.AppliedAnalyticsDefinition) appliedAnalyticsDefinitions {
+	result := appliedAnalyticsDefinitions{}
+	for _, v := range list {
+		result[v.Name] = v
+	}
+
+	return result
+}
+
+func (applied appliedAnalyticsDefinitions) toSortedList() []v1beta1.AppliedAnalyticsDefinition {
+	result := make([]v1beta1.AppliedAnalyticsDefinition, 0, len(applied))
+	for _, v := range applied {
+		result = append(result, v)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CouchbaseAnalyticsSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = mgr.GetEventRecorderFor("couchbase-analytics-set-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.CouchbaseAnalyticsSet{}).
+		WithEventFilter(ignoreStatusChangePredicate()).
+		Complete(r)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+type CollectionSetReadyReason string
+
+const (
+	CollectionSetReadyReasonInSync         CollectionSetReadyReason = "InSync"
+	CollectionSetReadyReasonPaused         CollectionSetReadyReason = "Paused"
+	CollectionSetReadyReasonCouchbaseError CollectionSetReadyReason = "CouchbaseError"
+)
+
+func setCollectionSetNotReady(collectionSet *v1beta1.CouchbaseCollectionSet, reason CollectionSetReadyReason, message string) {
+	setCollectionSetReadyStatus(collectionSet, false, reason, message)
+}
+
+func setCollectionSetReadyInSync(collectionSet *v1beta1.CouchbaseCollectionSet) {
+	setCollectionSetReadyStatus(collectionSet, true, CollectionSetReadyReasonInSync, "Scopes and collections exist")
+}
+
+func setCollectionSetReadyStatus(collectionSet *v1beta1.CouchbaseCollectionSet, status bool, reason CollectionSetReadyReason, message string) {
+	meta.SetStatusCondition(&collectionSet.Status.Conditions, v1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             getStatus(status),
+		Message:            message,
+		Reason:             string(reason),
+		ObservedGeneration: collectionSet.Generation,
+	})
+}
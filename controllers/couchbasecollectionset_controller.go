@@ -0,0 +1,178 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+// CouchbaseCollectionSetReconciler reconciles a CouchbaseCollectionSet object
+type CouchbaseCollectionSetReconciler struct {
+	client.Client
+	record.EventRecorder
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasecollectionsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasecollectionsets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=couchbase.com,namespace=system,resources=couchbaseclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch
+
+// Reconcile creates any missing scopes and collections and updates the max TTL of existing collections.
+// Scopes and collections are never dropped, so there is no cleanup when the collection set is deleted.
+func (r *CouchbaseCollectionSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	collectionSet := v1beta1.CouchbaseCollectionSet{}
+	if err := r.Get(ctx, req.NamespacedName, &collectionSet); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if collectionSet.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	log.FromContext(ctx).V(1).Info("reconciling")
+
+	result, err := r.syncCollections(ctx, &collectionSet)
+
+	if statusErr := r.Status().Update(ctx, &collectionSet); statusErr != nil {
+		log.FromContext(ctx).Error(statusErr, "unable to update status")
+		return ctrl.Result{}, statusErr
+	}
+
+	return result, err
+}
+
+func (r *CouchbaseCollectionSetReconciler) syncCollections(ctx context.Context, collectionSet *v1beta1.CouchbaseCollectionSet) (ctrl.Result, error) {
+	if collectionSet.Spec.Paused != nil && *collectionSet.Spec.Paused {
+		setCollectionSetNotReady(collectionSet, CollectionSetReadyReasonPaused, "Collection synchronization is paused")
+		return ctrl.Result{}, nil
+	}
+
+	restClient, message, err := getDirectRestClient(ctx, r, collectionSet.Namespace, collectionSet.Spec.Cluster, collectionSet.Spec.BucketName)
+	if err != nil {
+		setCollectionSetNotReady(collectionSet, CollectionSetReadyReasonCouchbaseError, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if restClient == nil {
+		setCollectionSetNotReady(collectionSet, CollectionSetReadyReasonCouchbaseError, message)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := r.createCollections(ctx, restClient, collectionSet); err != nil {
+		setCollectionSetNotReady(collectionSet, CollectionSetReadyReasonCouchbaseError, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	collections := []string{}
+	for _, scope := range collectionSet.Spec.Scopes {
+		for _, collection := range scope.Collections {
+			collections = append(collections, fmt.Sprintf("%s.%s", scope.Name, collection.Name))
+		}
+	}
+	sort.Strings(collections)
+
+	collectionSet.Status.Collections = collections
+	setCollectionSetReadyInSync(collectionSet)
+
+	// Periodically recheck in case scopes or collections are dropped externally
+	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+}
+
+func (r *CouchbaseCollectionSetReconciler) createCollections(ctx context.Context, restClient *cbrest.Client,
+	collectionSet *v1beta1.CouchbaseCollectionSet) error {
+
+	bucketName := collectionSet.Spec.BucketName
+
+	manifest, err := restClient.GetCollectionManifest(ctx, bucketName)
+	if err != nil {
+		if cbrest.IsNotFound(err) {
+			return fmt.Errorf("bucket %s is not found", bucketName)
+		}
+
+		return err
+	}
+
+	existing := map[string]map[string]cbrest.CollectionManifestCollection{}
+	for _, scope := range manifest.Scopes {
+		collections := map[string]cbrest.CollectionManifestCollection{}
+		for _, collection := range scope.Collections {
+			collections[collection.Name] = collection
+		}
+
+		existing[scope.Name] = collections
+	}
+
+	for _, scope := range collectionSet.Spec.Scopes {
+		existingCollections, ok := existing[scope.Name]
+		if !ok {
+			if err := restClient.CreateScope(ctx, bucketName, scope.Name); err != nil {
+				return fmt.Errorf("unable to create scope %s: %w", scope.Name, err)
+			}
+
+			r.Eventf(collectionSet, "Normal", "ScopeCreated", "Created scope %s", scope.Name)
+			existingCollections = map[string]cbrest.CollectionManifestCollection{}
+		}
+
+		for _, collection := range scope.Collections {
+			maxTTL := 0
+			if collection.MaxTTL != nil {
+				maxTTL = *collection.MaxTTL
+			}
+
+			existingCollection, ok := existingCollections[collection.Name]
+			if !ok {
+				if err := restClient.CreateCollection(ctx, bucketName, scope.Name, collection.Name, maxTTL); err != nil {
+					return fmt.Errorf("unable to create collection %s.%s: %w", scope.Name, collection.Name, err)
+				}
+
+				r.Eventf(collectionSet, "Normal", "CollectionCreated", "Created collection %s.%s", scope.Name, collection.Name)
+			} else if collection.MaxTTL != nil && existingCollection.MaxTTL != maxTTL {
+				if err := restClient.UpdateCollection(ctx, bucketName, scope.Name, collection.Name, maxTTL); err != nil {
+					return fmt.Errorf("unable to update max TTL of collection %s.%s: %w", scope.Name, collection.Name, err)
+				}
+
+				r.Eventf(collectionSet, "Normal", "CollectionUpdated", "Updated max TTL of collection %s.%s", scope.Name, collection.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CouchbaseCollectionSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = mgr.GetEventRecorderFor("couchbase-collection-set-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.CouchbaseCollectionSet{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		Complete(r)
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+const (
+	eventingFunctionFinalizer string = "couchbase.btburnett.com/eventing"
+
+	// Deployment state changes are asynchronous, so poll until they complete
+	eventingTransitionPollInterval = 10 * time.Second
+)
+
+// CouchbaseEventingFunctionReconciler reconciles a CouchbaseEventingFunction object
+type CouchbaseEventingFunctionReconciler struct {
+	client.Client
+	record.EventRecorder
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseeventingfunctions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseeventingfunctions/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseeventingfunctions/finalizers,verbs=update
+//+kubebuilder:rbac:groups=couchbase.com,namespace=system,resources=couchbaseclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch
+
+// Reconcile applies an Eventing function definition through the Eventing REST API and moves the function to the
+// desired deployment state. Deployed functions are paused while their definition is updated, or undeployed if
+// their source or metadata keyspace changes.
+func (r *CouchbaseEventingFunctionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	eventingFunction := v1beta1.CouchbaseEventingFunction{}
+	if err := r.Get(ctx, req.NamespacedName, &eventingFunction); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return reconcileDirectSync(ctx, r.Client, r.EventRecorder, &directSyncResource{
+		object:        &eventingFunction,
+		finalizer:     eventingFunctionFinalizer,
+		conditions:    &eventingFunction.Status.Conditions,
+		lastSyncTime:  &eventingFunction.Status.LastSyncTime,
+		cluster:       eventingFunction.Spec.Cluster,
+		bucketName:    eventingFunction.Spec.SourceKeyspace.BucketName,
+		inSyncMessage: "Function is in sync",
+		pollInterval:  eventingTransitionPollInterval,
+		sync: func(ctx context.Context, restClient *cbrest.Client, isDeleting bool) (string, error) {
+			return r.syncFunction(ctx, restClient, &eventingFunction, isDeleting)
+		},
+	})
+}
+
+// Removes the function when deleting, or the previous function if it was renamed, then applies the function.
+// Returns a message describing the transition in progress, or an empty string once the function is in sync.
+func (r *CouchbaseEventingFunctionReconciler) syncFunction(ctx context.Context, restClient *cbrest.Client,
+	eventingFunction *v1beta1.CouchbaseEventingFunction, isDeleting bool) (string, error) {
+
+	functionName := getEventingFunctionName(eventingFunction)
+
+	removeName := ""
+	if isDeleting {
+		removeName = functionName
+		if eventingFunction.Status.FunctionName != "" {
+			removeName = eventingFunction.Status.FunctionName
+		}
+	} else if eventingFunction.Status.FunctionName != "" && eventingFunction.Status.FunctionName != functionName {
+		removeName = eventingFunction.Status.FunctionName
+	}
+
+	if removeName != "" {
+		removed, err := r.removeFunction(ctx, restClient, eventingFunction, removeName)
+		if err != nil {
+			return "", err
+		}
+		if !removed {
+			return fmt.Sprintf("Waiting for function %s to be undeployed", removeName), nil
+		}
+
+		if isDeleting {
+			return "", nil
+		}
+
+		eventingFunction.Status.DefinitionHash = ""
+	}
+
+	eventingFunction.Status.FunctionName = functionName
+
+	return r.applyFunction(ctx, restClient, eventingFunction, functionName)
+}
+
+// Applies the function definition if it has changed and moves the function towards the desired deployment state.
+// Returns a message describing the transition in progress, or an empty string once the function is in sync.
+func (r *CouchbaseEventingFunctionReconciler) applyFunction(ctx context.Context, restClient *cbrest.Client,
+	eventingFunction *v1beta1.CouchbaseEventingFunction, functionName string) (string, error) {
+
+	deploymentStatus, err := restClient.GetEventingFunctionStatus(ctx, functionName)
+	if err != nil {
+		return "", fmt.Errorf("unable to get function status: %w", err)
+	}
+
+	eventingFunction.Status.DeploymentStatus = deploymentStatus
+	if isEventingStatusTransitioning(deploymentStatus) {
+		return fmt.Sprintf("Waiting for function to finish %s", deploymentStatus), nil
+	}
+
+	definition := buildEventingFunction(eventingFunction, functionName)
+	definitionJSON, err := json.Marshal(definition)
+	if err != nil {
+		return "", err
+	}
+	hash := getDefinitionHash(string(definitionJSON))
+
+	var existing *cbrest.EventingFunction
+	if deploymentStatus != "" {
+		if existing, err = restClient.GetEventingFunction(ctx, functionName); err != nil {
+			return "", fmt.Errorf("unable to get function: %w", err)
+		}
+	}
+
+	if existing == nil || existing.Code != definition.Code || eventingFunction.Status.DefinitionHash != hash {
+		isActive := deploymentStatus == cbrest.EventingStatusDeployed || deploymentStatus == cbrest.EventingStatusPaused
+
+		if isActive && existing != nil && !isSameEventingKeyspaces(existing.DepCfg, definition.DepCfg) {
+			// Keyspaces may only be changed while undeployed
+			if err := restClient.UndeployEventingFunction(ctx, functionName); err != nil {
+				return "", fmt.Errorf("unable to undeploy function: %w", err)
+			}
+
+			r.Event(eventingFunction, "Normal", "Undeploying", "Undeploying function to change keyspaces")
+			return "Waiting for function to undeploy before changing keyspaces", nil
+		}
+
+		if deploymentStatus == cbrest.EventingStatusDeployed {
+			// Code and bindings may be changed while paused
+			if err := restClient.PauseEventingFunction(ctx, functionName); err != nil {
+				return "", fmt.Errorf("unable to pause function: %w", err)
+			}
+
+			r.Event(eventingFunction, "Normal", "Pausing", "Pausing function to apply changes")
+			return "Waiting for function to pause before applying changes", nil
+		}
+
+		// Retain the current deployment state while applying the definition
+		definition.Settings["deployment_status"] = deploymentStatus == cbrest.EventingStatusPaused
+		definition.Settings["processing_status"] = false
+
+		if err := restClient.PutEventingFunction(ctx, definition); err != nil {
+			return "", fmt.Errorf("unable to apply function: %w", err)
+		}
+
+		r.Event(eventingFunction, "Normal", "FunctionApplied", "Applied function definition")
+		eventingFunction.Status.DefinitionHash = hash
+
+		if deploymentStatus == "" {
+			deploymentStatus = cbrest.EventingStatusUndeployed
+			eventingFunction.Status.DeploymentStatus = deploymentStatus
+		}
+	}
+
+	desiredState := "Deployed"
+	if eventingFunction.Spec.DeploymentState != nil {
+		desiredState = *eventingFunction.Spec.DeploymentState
+	}
+
+	switch {
+	case desiredState == "Deployed" && deploymentStatus == cbrest.EventingStatusUndeployed:
+		if err := restClient.DeployEventingFunction(ctx, functionName); err != nil {
+			return "", fmt.Errorf("unable to deploy function: %w", err)
+		}
+
+		r.Event(eventingFunction, "Normal", "Deploying", "Deploying function")
+		return "Waiting for function to deploy", nil
+
+	case desiredState == "Deployed" && deploymentStatus == cbrest.EventingStatusPaused:
+		if err := restClient.ResumeEventingFunction(ctx, functionName); err != nil {
+			return "", fmt.Errorf("unable to resume function: %w", err)
+		}
+
+		r.Event(eventingFunction, "Normal", "Resuming", "Resuming function")
+		return "Waiting for function to resume", nil
+
+	case desiredState == "Paused" && deploymentStatus == cbrest.EventingStatusDeployed:
+		if err := restClient.PauseEventingFunction(ctx, functionName); err != nil {
+			return "", fmt.Errorf("unable to pause function: %w", err)
+		}
+
+		r.Event(eventingFunction, "Normal", "Pausing", "Pausing function")
+		return "Waiting for function to pause", nil
+
+	case desiredState == "Undeployed" &&
+		(deploymentStatus == cbrest.EventingStatusDeployed || deploymentStatus == cbrest.EventingStatusPaused):
+		if err := restClient.UndeployEventingFunction(ctx, functionName); err != nil {
+			return "", fmt.Errorf("unable to undeploy function: %w", err)
+		}
+
+		r.Event(eventingFunction, "Normal", "Undeploying", "Undeploying function")
+		return "Waiting for function to undeploy", nil
+	}
+
+	return "", nil
+}
+
+// Undeploys and deletes a function, returns true once the function no longer exists
+func (r *CouchbaseEventingFunctionReconciler) removeFunction(ctx context.Context, restClient *cbrest.Client,
+	eventingFunction *v1beta1.CouchbaseEventingFunction, functionName string) (bool, error) {
+
+	deploymentStatus, err := restClient.GetEventingFunctionStatus(ctx, functionName)
+	if err != nil {
+		return false, fmt.Errorf("unable to get function status: %w", err)
+	}
+
+	eventingFunction.Status.DeploymentStatus = deploymentStatus
+
+	switch {
+	case deploymentStatus == "":
+		return true, nil
+
+	case isEventingStatusTransitioning(deploymentStatus):
+		return false, nil
+
+	case deploymentStatus == cbrest.EventingStatusUndeployed:
+		if err := restClient.DeleteEventingFunction(ctx, functionName); err != nil {
+			return false, fmt.Errorf("unable to delete function %s: %w", functionName, err)
+		}
+
+		r.Eventf(eventingFunction, "Normal", "FunctionDeleted", "Deleted function %s", functionName)
+		eventingFunction.Status.DeploymentStatus = ""
+		return true, nil
+
+	default:
+		if err := restClient.UndeployEventingFunction(ctx, functionName); err != nil {
+			return false, fmt.Errorf("unable to undeploy function %s: %w", functionName, err)
+		}
+
+		r.Eventf(eventingFunction, "Normal", "Undeploying", "Undeploying function %s", functionName)
+		return false, nil
+	}
+}
+
+func isEventingStatusTransitioning(deploymentStatus string) bool {
+	return deploymentStatus == cbrest.EventingStatusDeploying ||
+		deploymentStatus == cbrest.EventingStatusUndeploying ||
+		deploymentStatus == cbrest.EventingStatusPausing
+}
+
+func isSameEventingKeyspaces(a cbrest.EventingDepCfg, b cbrest.EventingDepCfg) bool {
+	return a.SourceBucket == b.SourceBucket &&
+		defaultedKeyspaceName(&a.SourceScope) == b.SourceScope &&
+		defaultedKeyspaceName(&a.SourceCollection) == b.SourceCollection &&
+		a.MetadataBucket == b.MetadataBucket &&
+		defaultedKeyspaceName(&a.MetadataScope) == b.MetadataScope &&
+		defaultedKeyspaceName(&a.MetadataCollection) == b.MetadataCollection
+}
+
+func getEventingFunctionName(eventingFunction *v1beta1.CouchbaseEventingFunction) string {
+	if eventingFunction.Spec.FunctionName != nil {
+		return *eventingFunction.Spec.FunctionName
+	}
+
+	return eventingFunction.Name
+}
+
+// Builds the Eventing REST API definition of a function, excluding the deployment state
+func buildEventingFunction(eventingFunction *v1beta1.CouchbaseEventingFunction, functionName string) *cbrest.EventingFunction {
+	spec := eventingFunction.Spec
+
+	definition := cbrest.EventingFunction{
+		Name: functionName,
+		Code: spec.Code,
+		DepCfg: cbrest.EventingDepCfg{
+			SourceBucket:       spec.SourceKeyspace.BucketName,
+			SourceScope:        defaultedKeyspaceName(spec.SourceKeyspace.ScopeName),
+			SourceCollection:   defaultedKeyspaceName(spec.SourceKeyspace.CollectionName),
+			MetadataBucket:     spec.MetadataKeyspace.BucketName,
+			MetadataScope:      defaultedKeyspaceName(spec.MetadataKeyspace.ScopeName),
+			MetadataCollection: defaultedKeyspaceName(spec.MetadataKeyspace.CollectionName),
+		},
+		Settings: map[string]interface{}{
+			"dcp_stream_boundary": "everything",
+		},
+	}
+
+	for _, binding := range spec.BucketBindings {
+		access := "r"
+		if binding.Access != nil && *binding.Access == "ReadWrite" {
+			access = "rw"
+		}
+
+		definition.DepCfg.Buckets = append(definition.DepCfg.Buckets, cbrest.EventingBucketBinding{
+			Alias:          binding.Alias,
+			BucketName:     binding.BucketName,
+			ScopeName:      defaultedKeyspaceName(binding.ScopeName),
+			CollectionName: defaultedKeyspaceName(binding.CollectionName),
+			Access:         access,
+		})
+	}
+
+	for _, binding := range spec.URLBindings {
+		definition.DepCfg.URLs = append(definition.DepCfg.URLs, cbrest.EventingURLBinding{
+			Alias:                  binding.Alias,
+			Hostname:               binding.Hostname,
+			AuthType:               "no-auth",
+			AllowCookies:           binding.AllowCookies != nil && *binding.AllowCookies,
+			ValidateSSLCertificate: binding.ValidateSSLCertificate == nil || *binding.ValidateSSLCertificate,
+		})
+	}
+
+	for _, binding := range spec.ConstantBindings {
+		definition.DepCfg.Constants = append(definition.DepCfg.Constants, cbrest.EventingConstantBinding{
+			Alias:   binding.Alias,
+			Literal: binding.Value,
+		})
+	}
+
+	if settings := spec.Settings; settings != nil {
+		if settings.StreamBoundary != nil && *settings.StreamBoundary == "FromNow" {
+			definition.Settings["dcp_stream_boundary"] = "from_now"
+		}
+		if settings.WorkerCount != nil {
+			definition.Settings["worker_count"] = *settings.WorkerCount
+		}
+		if settings.LogLevel != nil {
+			definition.Settings["log_level"] = *settings.LogLevel
+		}
+		if settings.ExecutionTimeoutSeconds != nil {
+			definition.Settings["execution_timeout"] = *settings.ExecutionTimeoutSeconds
+		}
+		if settings.LanguageCompatibility != nil {
+			definition.Settings["language_compatibility"] = *settings.LanguageCompatibility
+		}
+	}
+
+	return &definition
+}
+
+func defaultedKeyspaceName(name *string) string {
+	if name == nil || *name == "" {
+		return "_default"
+	}
+
+	return *name
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CouchbaseEventingFunctionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = mgr.GetEventRecorderFor("couchbase-eventing-function-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.CouchbaseEventingFunction{}).
+		WithEventFilter(ignoreStatusChangePredicate()).
+		Complete(r)
+}
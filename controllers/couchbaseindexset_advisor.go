@@ -0,0 +1,128 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+// Periodically runs the workload queries from the advisor ConfigMap through the Index Advisor, reporting the
+// recommendations in the status. Queries are advised again immediately when they change. Failures are logged and
+// retried, but don't affect the sync.
+func (context *CouchbaseIndexSetReconcileContext) reconcileAdvisor() ctrl.Result {
+	advisor := context.IndexSet.Spec.Advisor
+	if context.IsDeleting || advisor == nil {
+		context.IndexSet.Status.Advice = nil
+		return ctrl.Result{}
+	}
+
+	interval := time.Hour
+	if advisor.IntervalSeconds != nil {
+		interval = time.Duration(*advisor.IntervalSeconds) * time.Second
+	}
+
+	configMap := corev1.ConfigMap{}
+	if err := context.Reconciler.Get(context.Ctx, types.NamespacedName{
+		Namespace: context.IndexSet.Namespace,
+		Name:      advisor.ConfigMapName,
+	}, &configMap); err != nil {
+		context.Error(err, "unable to fetch advisor ConfigMap")
+		return ctrl.Result{RequeueAfter: time.Minute}
+	}
+
+	// Maps are marshaled with sorted keys, so the hash is stable
+	queriesJSON, err := json.Marshal(configMap.Data)
+	if err != nil {
+		context.Error(err, "unable to hash advisor queries")
+		return ctrl.Result{}
+	}
+	queriesHash := getDefinitionHash(string(queriesJSON))
+
+	if advice := context.IndexSet.Status.Advice; advice != nil && advice.QueriesHash == queriesHash {
+		if timeToNextAdvice := getTimeToNextSync(advice.LastAdvised.Time, interval); timeToNextAdvice > 0 {
+			return ctrl.Result{RequeueAfter: timeToNextAdvice}
+		}
+	}
+
+	client, err := context.getRestClient()
+	if err != nil {
+		context.Error(err, "unable to run the index advisor")
+		return ctrl.Result{RequeueAfter: time.Minute}
+	}
+
+	names := make([]string, 0, len(configMap.Data))
+	for name := range configMap.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]cbim.QueryAdviceResult, len(names))
+	for i, name := range names {
+		results[i].Name = name
+
+		advice, err := client.Advise(context.Ctx, configMap.Data[name])
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		results[i].CurrentIndexes = advice.CurrentIndexes
+		results[i].RecommendedIndexes = advice.RecommendedIndexes
+	}
+
+	previouslyRecommended := map[string]bool{}
+	if context.IndexSet.Status.Advice != nil {
+		for _, gsi := range context.IndexSet.Status.Advice.RecommendedIndices {
+			previouslyRecommended[cbim.GetIndexIdentifier(gsi).ToString()] = true
+		}
+	}
+
+	queries, recommendedIndices := cbim.GetIndexSetAdvice(&context.IndexSet, context.Indices, results)
+
+	newlyRecommended := []string{}
+	for _, gsi := range recommendedIndices {
+		if name := cbim.GetIndexIdentifier(gsi).ToString(); !previouslyRecommended[name] {
+			newlyRecommended = append(newlyRecommended, name)
+		}
+	}
+
+	if len(newlyRecommended) > 0 {
+		context.V(1).Info("Indices recommended", "indices", newlyRecommended)
+		context.Reconciler.Event(&context.IndexSet, "Normal", "IndicesRecommended",
+			"Index Advisor recommended indices: "+strings.Join(newlyRecommended, ", "))
+	}
+
+	context.IndexSet.Status.Advice = &v1beta1.IndexSetAdvice{
+		LastAdvised:        metav1.Now(),
+		QueriesHash:        queriesHash,
+		Queries:            queries,
+		RecommendedIndices: recommendedIndices,
+	}
+
+	return ctrl.Result{RequeueAfter: interval}
+}
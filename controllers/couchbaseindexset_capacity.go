@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+// Estimates the footprint of indices which haven't been created yet and compares it to the capacity of the index
+// service, blocking the sync if building them would exceed the configured thresholds
+func (context *CouchbaseIndexSetReconcileContext) reconcileCapacity() (bool, ctrl.Result, error) {
+	check := context.IndexSet.Spec.CapacityCheck
+	if context.IsDeleting || check == nil {
+		removeCapacityCondition(&context.IndexSet)
+		return true, ctrl.Result{}, nil
+	}
+
+	newIndexes := cbim.GetNewIndexes(&context.IndexSet, context.Indices)
+	if len(newIndexes) == 0 {
+		setCapacityExceededStatus(&context.IndexSet, false, IndexSetCapacityReasonWithinCapacity, "No new indices to build")
+		return true, ctrl.Result{}, nil
+	}
+
+	client, err := context.getRestClient()
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+
+	capacity, err := client.GetIndexServiceCapacity(context.Ctx)
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+
+	itemCounts, err := client.GetCollectionItemCounts(context.Ctx, context.IndexSet.Spec.BucketName)
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+
+	stats, err := client.GetIndexStats(context.Ctx, context.IndexSet.Spec.BucketName)
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+
+	arrayItemsPerDocument := int64(10)
+	if check.ArrayItemsPerDocument != nil {
+		arrayItemsPerDocument = int64(*check.ArrayItemsPerDocument)
+	}
+
+	footprint := cbim.EstimateIndexFootprint(newIndexes, itemCounts, stats, arrayItemsPerDocument)
+
+	maxMemoryPercent, maxDiskPercent := int32(90), int32(90)
+	if check.MaxMemoryPercent != nil {
+		maxMemoryPercent = *check.MaxMemoryPercent
+	}
+	if check.MaxDiskPercent != nil {
+		maxDiskPercent = *check.MaxDiskPercent
+	}
+
+	var exceeded []string
+	if cbim.ExceedsCapacity(capacity.MemoryUsed, footprint.Memory, capacity.MemoryQuota, maxMemoryPercent) {
+		exceeded = append(exceeded, fmt.Sprintf("memory would reach %s of %s quota, limit is %d%%",
+			formatBytes(capacity.MemoryUsed+footprint.Memory), formatBytes(capacity.MemoryQuota), maxMemoryPercent))
+	}
+	if cbim.ExceedsCapacity(capacity.DiskUsed, footprint.Disk, capacity.DiskTotal, maxDiskPercent) {
+		exceeded = append(exceeded, fmt.Sprintf("disk would reach %s of %s, limit is %d%%",
+			formatBytes(capacity.DiskUsed+footprint.Disk), formatBytes(capacity.DiskTotal), maxDiskPercent))
+	}
+
+	if len(exceeded) == 0 {
+		setCapacityExceededStatus(&context.IndexSet, false, IndexSetCapacityReasonWithinCapacity,
+			fmt.Sprintf("%d new indices estimated at %s memory and %s disk", len(newIndexes), formatBytes(footprint.Memory), formatBytes(footprint.Disk)))
+		return true, ctrl.Result{}, nil
+	}
+
+	message := fmt.Sprintf("Building %d new indices would exceed the index service capacity: %s",
+		len(newIndexes), strings.Join(exceeded, "; "))
+
+	if !meta.IsStatusConditionTrue(context.IndexSet.Status.Conditions, ConditionTypeCapacityExceeded) {
+		context.V(1).Info("Index capacity exceeded", "message", message)
+		context.Reconciler.Event(&context.IndexSet, "Warning", "CapacityExceeded", message)
+	}
+
+	setCapacityExceededStatus(&context.IndexSet, true, IndexSetCapacityReasonCapacityExceeded, message)
+	setNotReady(&context.IndexSet, IndexSetReadyReasonCapacityExceeded, message)
+
+	// Capacity changes slowly, so there's no need to recheck frequently
+	return false, ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+}
+
+func formatBytes(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
+
+func setCapacityExceededStatus(indexSet *v1beta1.CouchbaseIndexSet, status bool, reason IndexSetCapacityReason, message string) {
+	meta.SetStatusCondition(&indexSet.Status.Conditions, v1.Condition{
+		Type:               ConditionTypeCapacityExceeded,
+		Status:             getStatus(status),
+		Message:            message,
+		Reason:             string(reason),
+		ObservedGeneration: indexSet.Generation,
+	})
+}
+
+func removeCapacityCondition(indexSet *v1beta1.CouchbaseIndexSet) {
+	meta.RemoveStatusCondition(&indexSet.Status.Conditions, ConditionTypeCapacityExceeded)
+}
@@ -0,0 +1,502 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+const (
+	indexSetClusterLabel = "indexSetCluster"
+
+	rolloutStrategyOrdered = "Ordered"
+)
+
+// Describes why a cluster is waiting on earlier clusters in an ordered rollout
+type rolloutWait struct {
+	Message      string
+	RequeueAfter time.Duration
+}
+
+// Syncs indices to each of the clusters of an index set targeting multiple clusters. Each cluster is reconciled
+// separately using a copy of the index set which targets only that cluster, and the results are tracked in the
+// status of the cluster.
+func (context *CouchbaseIndexSetReconcileContext) reconcileClusters() (ctrl.Result, error) {
+	existingStatuses := map[string]v1beta1.CouchbaseIndexSetClusterStatus{}
+	for _, clusterStatus := range context.IndexSet.Status.Clusters {
+		existingStatuses[clusterStatus.Name] = clusterStatus
+	}
+
+	// Indices managed before switching from a single cluster, tracked at the top level of the status
+	var previousStatus *v1beta1.CouchbaseIndexSetClusterStatus
+	if status := &context.IndexSet.Status; status.Cluster != nil || len(status.Indices) > 0 || len(status.ProtectedIndices) > 0 {
+		clusterStatus := getClusterStatus("", status)
+		previousStatus = &clusterStatus
+	}
+
+	var (
+		result          ctrl.Result
+		resultErr       error
+		cleanupComplete = true
+	)
+
+	targetedClusters := map[string]bool{}
+	clusterStatuses := make([]v1beta1.CouchbaseIndexSetClusterStatus, 0, len(context.IndexSet.Spec.Clusters))
+	for i, cluster := range context.IndexSet.Spec.Clusters {
+		targetedClusters[cluster.Name] = true
+
+		clusterStatus, ok := existingStatuses[cluster.Name]
+		if !ok {
+			clusterStatus = v1beta1.CouchbaseIndexSetClusterStatus{
+				Name: cluster.Name,
+			}
+
+			if previousStatus != nil && isSameConnection(previousStatus.Cluster, &cluster.Cluster) {
+				// Continue tracking the indices on the cluster they were created on
+				clusterStatus = *previousStatus
+				clusterStatus.Name = cluster.Name
+				previousStatus = nil
+			}
+		}
+
+		clusterContext := context.newClusterContext(cluster.Name, cluster.Cluster, clusterStatus)
+
+		if i > 0 {
+			clusterContext.RolloutWait = getRolloutWait(&context.IndexSet, clusterStatuses[i-1])
+		}
+
+		clusterResult, err := clusterContext.reconcileCluster()
+		if err != nil && resultErr == nil {
+			resultErr = err
+		}
+		result = mergeResults(result, clusterResult)
+
+		if !clusterContext.CleanupComplete {
+			cleanupComplete = false
+		}
+
+		clusterStatuses = append(clusterStatuses, getClusterStatus(cluster.Name, &clusterContext.IndexSet.Status))
+	}
+
+	// Clusters which are no longer targeted, including a single cluster targeted before, have their indices dropped
+	removedStatuses := []v1beta1.CouchbaseIndexSetClusterStatus{}
+	for _, clusterStatus := range context.IndexSet.Status.Clusters {
+		if !targetedClusters[clusterStatus.Name] {
+			removedStatuses = append(removedStatuses, clusterStatus)
+		}
+	}
+	if previousStatus != nil {
+		removedStatuses = append(removedStatuses, *previousStatus)
+	}
+
+	remainingStatuses, removedResult, err := context.reconcileRemovedClusters(removedStatuses)
+	if err != nil && resultErr == nil {
+		resultErr = err
+	}
+	result = mergeResults(result, removedResult)
+
+	// Per-cluster tracking replaces the top level tracking used for a single cluster
+	context.IndexSet.Status.ConfigMapName = ""
+	context.IndexSet.Status.Cluster = nil
+	context.IndexSet.Status.Indices = nil
+	context.IndexSet.Status.ProtectedIndices = nil
+	context.IndexSet.Status.PendingDrops = nil
+	context.IndexSet.Status.InUseIndices = nil
+	context.IndexSet.Status.Templates = nil
+	context.IndexSet.Status.LastSyncRequest = ""
+	context.IndexSet.Status.NextSyncWindow = nil
+	context.IndexSet.Status.IndexUsage = nil
+	context.IndexSet.Status.LastUsageCollection = nil
+	context.IndexSet.Status.Advice = nil
+	context.IndexSet.Status.LastQueryPlanCheck = nil
+
+	for _, clusterStatus := range remainingStatuses {
+		if clusterStatus.Name == "" {
+			// The single cluster targeted before keeps its top level tracking until cleanup is complete
+			context.IndexSet.Status.ConfigMapName = clusterStatus.ConfigMapName
+			context.IndexSet.Status.Cluster = clusterStatus.Cluster
+			context.IndexSet.Status.Indices = clusterStatus.Indices
+			context.IndexSet.Status.ProtectedIndices = clusterStatus.ProtectedIndices
+			continue
+		}
+
+		clusterStatuses = append(clusterStatuses, clusterStatus)
+	}
+
+	context.IndexSet.Status.Clusters = clusterStatuses
+
+	setAggregateConditions(&context.IndexSet)
+
+	if context.IsDeleting && cleanupComplete && len(remainingStatuses) == 0 {
+		context.V(1).Info("Cleanup complete for all clusters")
+
+		if err := context.removeFinalizer(); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	return result, resultErr
+}
+
+// Syncs indices to the single cluster targeted by the index set. When switching from multiple clusters, the cluster
+// with the same connection continues to track its indices at the top level of the status, and the indices on the
+// other clusters are dropped.
+func (context *CouchbaseIndexSetReconcileContext) reconcileSingleCluster() (ctrl.Result, error) {
+	removedStatuses := []v1beta1.CouchbaseIndexSetClusterStatus{}
+	for _, clusterStatus := range context.IndexSet.Status.Clusters {
+		status := &context.IndexSet.Status
+		if status.Cluster == nil && len(status.Indices) == 0 && isSameConnection(clusterStatus.Cluster, context.IndexSet.Spec.Cluster) {
+			clusters, indexCount := status.Clusters, status.IndexCount
+			*status = getStatusFromCluster(clusterStatus)
+			status.Clusters, status.IndexCount = clusters, indexCount
+			continue
+		}
+
+		removedStatuses = append(removedStatuses, clusterStatus)
+	}
+
+	remainingStatuses, removedResult, removedErr := context.reconcileRemovedClusters(removedStatuses)
+	context.IndexSet.Status.Clusters = remainingStatuses
+
+	result, err := context.reconcileCluster()
+	if err == nil {
+		err = removedErr
+	}
+
+	return mergeResults(result, removedResult), err
+}
+
+// Drops the indices managed on clusters which are no longer targeted by the index set, using the connection tracked
+// in the status of each cluster. Returns the status of the clusters which haven't finished cleanup.
+func (context *CouchbaseIndexSetReconcileContext) reconcileRemovedClusters(clusterStatuses []v1beta1.CouchbaseIndexSetClusterStatus) (
+	[]v1beta1.CouchbaseIndexSetClusterStatus, ctrl.Result, error) {
+
+	var (
+		remainingStatuses []v1beta1.CouchbaseIndexSetClusterStatus
+		result            ctrl.Result
+		resultErr         error
+	)
+
+	for _, clusterStatus := range clusterStatuses {
+		name := clusterStatus.Name
+		if name == "" {
+			name = "(cluster)"
+		}
+
+		if clusterStatus.Cluster == nil {
+			// Status from earlier versions of the operator doesn't track the connection, so there's no way to drop them
+			if len(clusterStatus.Indices) > 0 {
+				context.Reconciler.Event(&context.IndexSet, "Warning", "ClusterCleanupSkipped",
+					fmt.Sprintf("Indices on removed cluster %s are no longer managed, its connection is unknown: %s",
+						name, strings.Join(clusterStatus.Indices, ", ")))
+			}
+
+			continue
+		}
+
+		clusterContext := context.newClusterContext(clusterStatus.Name, *clusterStatus.Cluster, clusterStatus)
+		clusterContext.IsDeleting = true
+		clusterContext.RemovingCluster = true
+		if clusterContext.IndexSet.DeletionTimestamp == nil {
+			// Treat the copy of the index set as deleted so that every index on the cluster is dropped
+			now := v1.Now()
+			clusterContext.IndexSet.DeletionTimestamp = &now
+		}
+
+		clusterResult, err := clusterContext.reconcileCluster()
+		if err != nil && resultErr == nil {
+			resultErr = err
+		}
+		result = mergeResults(result, clusterResult)
+
+		if clusterContext.CleanupComplete {
+			context.V(1).Info("Cleanup complete for removed cluster", "cluster", name)
+			context.Reconciler.Event(&context.IndexSet, "Normal", "ClusterRemoved",
+				fmt.Sprintf("Dropped indices on removed cluster %s", name))
+			continue
+		}
+
+		remainingStatuses = append(remainingStatuses, getClusterStatus(clusterStatus.Name, &clusterContext.IndexSet.Status))
+	}
+
+	return remainingStatuses, result, resultErr
+}
+
+// Creates a context which reconciles a single cluster of the index set
+func (context *CouchbaseIndexSetReconcileContext) newClusterContext(name string, cluster v1beta1.CouchbaseCluster,
+	clusterStatus v1beta1.CouchbaseIndexSetClusterStatus) CouchbaseIndexSetReconcileContext {
+
+	return CouchbaseIndexSetReconcileContext{
+		Ctx:                 context.Ctx,
+		Request:             context.Request,
+		Logger:              context.Logger.WithValues("cluster", name),
+		Reconciler:          context.Reconciler,
+		IndexSet:            getClusterIndexSet(&context.IndexSet, v1beta1.CouchbaseIndexSetCluster{Name: name, Cluster: cluster}, clusterStatus),
+		Indices:             context.Indices,
+		AppliedTemplates:    context.AppliedTemplates,
+		IsDeleting:          context.IsDeleting,
+		MissingDependencies: context.MissingDependencies,
+		ClusterName:         name,
+	}
+}
+
+// Returns true if both connections refer to the same Couchbase cluster, ignoring the credentials used
+func isSameConnection(a *v1beta1.CouchbaseCluster, b *v1beta1.CouchbaseCluster) bool {
+	return a != nil && b != nil && getConnectionIdentity(a) != "" && getConnectionIdentity(a) == getConnectionIdentity(b)
+}
+
+func getConnectionIdentity(cluster *v1beta1.CouchbaseCluster) string {
+	switch {
+	case cluster.ClusterRef != nil:
+		return "clusterRef:" + cluster.ClusterRef.Name
+	case cluster.Manual != nil:
+		return "manual:" + strings.TrimSuffix(cluster.Manual.ConnectionString, "/")
+	default:
+		return ""
+	}
+}
+
+// Creates a copy of the index set which targets a single cluster, using the status of that cluster
+func getClusterIndexSet(indexSet *v1beta1.CouchbaseIndexSet, cluster v1beta1.CouchbaseIndexSetCluster,
+	clusterStatus v1beta1.CouchbaseIndexSetClusterStatus) v1beta1.CouchbaseIndexSet {
+
+	result := indexSet.DeepCopy()
+	result.Spec.Cluster = cluster.Cluster.DeepCopy()
+	result.Spec.Clusters = nil
+	result.Status = getStatusFromCluster(clusterStatus)
+
+	return *result
+}
+
+func getStatusFromCluster(clusterStatus v1beta1.CouchbaseIndexSetClusterStatus) v1beta1.CouchbaseIndexSetStatus {
+	return v1beta1.CouchbaseIndexSetStatus{
+		Conditions:          clusterStatus.Conditions,
+		ConfigMapName:       clusterStatus.ConfigMapName,
+		Cluster:             clusterStatus.Cluster,
+		Indices:             clusterStatus.Indices,
+		ProtectedIndices:    clusterStatus.ProtectedIndices,
+		PendingDrops:        clusterStatus.PendingDrops,
+		InUseIndices:        clusterStatus.InUseIndices,
+		Templates:           clusterStatus.Templates,
+		LastSyncRequest:     clusterStatus.LastSyncRequest,
+		NextSyncWindow:      clusterStatus.NextSyncWindow,
+		IndexUsage:          clusterStatus.IndexUsage,
+		LastUsageCollection: clusterStatus.LastUsageCollection,
+		Advice:              clusterStatus.Advice,
+		LastQueryPlanCheck:  clusterStatus.LastQueryPlanCheck,
+		IndexCount:          clusterStatus.IndexCount,
+	}
+}
+
+func getClusterStatus(name string, status *v1beta1.CouchbaseIndexSetStatus) v1beta1.CouchbaseIndexSetClusterStatus {
+	return v1beta1.CouchbaseIndexSetClusterStatus{
+		Name:                name,
+		Conditions:          status.Conditions,
+		ConfigMapName:       status.ConfigMapName,
+		Cluster:             status.Cluster,
+		Indices:             status.Indices,
+		ProtectedIndices:    status.ProtectedIndices,
+		PendingDrops:        status.PendingDrops,
+		InUseIndices:        status.InUseIndices,
+		Templates:           status.Templates,
+		LastSyncRequest:     status.LastSyncRequest,
+		NextSyncWindow:      status.NextSyncWindow,
+		IndexUsage:          status.IndexUsage,
+		LastUsageCollection: status.LastUsageCollection,
+		Advice:              status.Advice,
+		LastQueryPlanCheck:  status.LastQueryPlanCheck,
+		IndexCount:          status.IndexCount,
+	}
+}
+
+// Determines if a cluster must wait for the previous cluster in an ordered rollout, returns nil if it may sync
+func getRolloutWait(indexSet *v1beta1.CouchbaseIndexSet, previous v1beta1.CouchbaseIndexSetClusterStatus) *rolloutWait {
+	rollout := indexSet.Spec.Rollout
+	if rollout == nil || rollout.Strategy == nil || *rollout.Strategy != rolloutStrategyOrdered {
+		return nil
+	}
+
+	readyCondition := meta.FindStatusCondition(previous.Conditions, ConditionTypeReady)
+	if readyCondition == nil || readyCondition.Status != v1.ConditionTrue || readyCondition.ObservedGeneration != indexSet.Generation {
+		return &rolloutWait{
+			Message: fmt.Sprintf("Waiting for cluster %s to sync", previous.Name),
+		}
+	}
+
+	if rollout.WaitSeconds != nil {
+		waitUntil := readyCondition.LastTransitionTime.Add(time.Duration(*rollout.WaitSeconds) * time.Second)
+		if timeToWait := getTimeToNextSync(waitUntil, 0); timeToWait > 0 {
+			return &rolloutWait{
+				Message:      fmt.Sprintf("Waiting until %s after cluster %s synced", waitUntil.UTC().Format(time.RFC3339), previous.Name),
+				RequeueAfter: timeToWait,
+			}
+		}
+	}
+
+	return nil
+}
+
+// Combines the results of reconciling multiple clusters, requeuing as soon as any cluster requires it
+func mergeResults(a ctrl.Result, b ctrl.Result) ctrl.Result {
+	result := ctrl.Result{
+		Requeue:      a.Requeue || b.Requeue,
+		RequeueAfter: a.RequeueAfter,
+	}
+
+	if b.RequeueAfter > 0 && (result.RequeueAfter == 0 || b.RequeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = b.RequeueAfter
+	}
+
+	return result
+}
+
+// Summarizes the conditions of each cluster in the top level conditions of the index set
+func setAggregateConditions(indexSet *v1beta1.CouchbaseIndexSet) {
+	var (
+		syncing     []string
+		notReady    []string
+		notReadyWhy IndexSetReadyReason
+		dropBlocked []string
+		blockedWhy  IndexSetDropBlockedReason
+		tracked     bool
+		unused      []string
+		explained   bool
+		invalid     []string
+		invalidWhy  IndexSetQueryPlansReason
+		checked     bool
+		exceeded    []string
+	)
+
+	targetedClusters := map[string]bool{}
+	for _, cluster := range indexSet.Spec.Clusters {
+		targetedClusters[cluster.Name] = true
+	}
+
+	for _, clusterStatus := range indexSet.Status.Clusters {
+		if condition := meta.FindStatusCondition(clusterStatus.Conditions, ConditionTypeDropBlocked); condition != nil && condition.Status == v1.ConditionTrue {
+			if blockedWhy == "" {
+				blockedWhy = IndexSetDropBlockedReason(condition.Reason)
+			}
+
+			dropBlocked = append(dropBlocked, fmt.Sprintf("%s: %s", clusterStatus.Name, condition.Message))
+		}
+
+		if !targetedClusters[clusterStatus.Name] {
+			// Removed clusters are only tracked until their indices are dropped, they don't affect the other conditions
+			continue
+		}
+
+		if condition := meta.FindStatusCondition(clusterStatus.Conditions, ConditionTypeSyncing); condition != nil && condition.Status == v1.ConditionTrue {
+			syncing = append(syncing, clusterStatus.Name)
+		}
+
+		if condition := meta.FindStatusCondition(clusterStatus.Conditions, ConditionTypeReady); condition == nil || condition.Status != v1.ConditionTrue {
+			if notReadyWhy == "" {
+				notReadyWhy = getCurrentStateFromCondition(condition)
+			}
+
+			message := "Not ready"
+			if condition != nil {
+				message = condition.Message
+			}
+			notReady = append(notReady, fmt.Sprintf("%s: %s", clusterStatus.Name, message))
+		}
+
+		if condition := meta.FindStatusCondition(clusterStatus.Conditions, ConditionTypeUnused); condition != nil {
+			tracked = true
+
+			if condition.Status == v1.ConditionTrue {
+				unused = append(unused, fmt.Sprintf("%s: %s", clusterStatus.Name, condition.Message))
+			}
+		}
+
+		if condition := meta.FindStatusCondition(clusterStatus.Conditions, ConditionTypeQueryPlansValid); condition != nil {
+			explained = true
+
+			if condition.Status == v1.ConditionFalse {
+				if invalidWhy == "" {
+					invalidWhy = IndexSetQueryPlansReason(condition.Reason)
+				}
+
+				invalid = append(invalid, fmt.Sprintf("%s: %s", clusterStatus.Name, condition.Message))
+			}
+		}
+
+		if condition := meta.FindStatusCondition(clusterStatus.Conditions, ConditionTypeCapacityExceeded); condition != nil {
+			checked = true
+
+			if condition.Status == v1.ConditionTrue {
+				exceeded = append(exceeded, fmt.Sprintf("%s: %s", clusterStatus.Name, condition.Message))
+			}
+		}
+	}
+
+	if len(syncing) > 0 {
+		setSyncStatus(indexSet, true, IndexSetSyncingReasonSyncing, "Sync in progress for "+strings.Join(syncing, ", "))
+	} else {
+		setNotSyncing(indexSet)
+	}
+
+	if len(notReady) > 0 {
+		setNotReady(indexSet, notReadyWhy, strings.Join(notReady, "; "))
+	} else {
+		setReadyStatus(indexSet, true, IndexSetReadyReasonInSync, "Indices are in sync on all clusters")
+	}
+
+	if len(dropBlocked) > 0 {
+		setDropBlockedStatus(indexSet, true, blockedWhy, strings.Join(dropBlocked, "; "))
+	} else {
+		setDropBlockedStatus(indexSet, false, IndexSetDropBlockedReasonNotBlocked, "No index drops are blocked")
+	}
+
+	switch {
+	case len(unused) > 0:
+		setUnusedStatus(indexSet, true, IndexSetUnusedReasonIndicesUnused, strings.Join(unused, "; "))
+	case tracked:
+		setUnused(indexSet, nil)
+	default:
+		removeUnusedCondition(indexSet)
+	}
+
+	switch {
+	case len(invalid) > 0:
+		setQueryPlansValidStatus(indexSet, false, invalidWhy, strings.Join(invalid, "; "))
+	case explained:
+		setQueryPlansValidStatus(indexSet, true, IndexSetQueryPlansReasonPlansValid, "All queries use the expected indices on all clusters")
+	default:
+		removeQueryPlansCondition(indexSet)
+	}
+
+	switch {
+	case len(exceeded) > 0:
+		setCapacityExceededStatus(indexSet, true, IndexSetCapacityReasonCapacityExceeded, strings.Join(exceeded, "; "))
+	case checked:
+		setCapacityExceededStatus(indexSet, false, IndexSetCapacityReasonWithinCapacity, "New indices are within capacity on all clusters")
+	default:
+		removeCapacityCondition(indexSet)
+	}
+}
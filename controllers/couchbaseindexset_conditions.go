@@ -17,30 +17,63 @@ limitations under the License.
 package controllers
 
 import (
+	"strings"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
 )
 
 type IndexSetSyncingReason string
 type IndexSetReadyReason string
+type IndexSetDropBlockedReason string
+type IndexSetUnusedReason string
+type IndexSetQueryPlansReason string
+type IndexSetCapacityReason string
 
 const (
-	ConditionTypeSyncing string = "Syncing"
-	ConditionTypeReady   string = "Ready"
+	ConditionTypeSyncing          string = "Syncing"
+	ConditionTypeReady            string = "Ready"
+	ConditionTypeDropBlocked      string = "DropBlocked"
+	ConditionTypeUnused           string = "Unused"
+	ConditionTypeQueryPlansValid  string = "QueryPlansValid"
+	ConditionTypeCapacityExceeded string = "CapacityExceeded"
 
 	IndexSetSyncingReasonNotSyncing IndexSetSyncingReason = "NotSyncing"
 	IndexSetSyncingReasonSyncing    IndexSetSyncingReason = "Syncing"
 
-	IndexSetReadyReasonUnknown        IndexSetReadyReason = "Unknown"
-	IndexSetReadyReasonInSync         IndexSetReadyReason = "InSync"
-	IndexSetReadyReasonOutOfSync      IndexSetReadyReason = "OutOfSync"
-	IndexSetReadyReasonPaused         IndexSetReadyReason = "Paused"
-	IndexSetReadyReasonJobFailed      IndexSetReadyReason = "JobFailed"
-	IndexSetReadyReasonConfigMapError IndexSetReadyReason = "ConfigMapError"
-	IndexSetReadyReasonCouchbaseError IndexSetReadyReason = "CouchbaseError"
+	IndexSetReadyReasonUnknown                IndexSetReadyReason = "Unknown"
+	IndexSetReadyReasonInSync                 IndexSetReadyReason = "InSync"
+	IndexSetReadyReasonOutOfSync              IndexSetReadyReason = "OutOfSync"
+	IndexSetReadyReasonPaused                 IndexSetReadyReason = "Paused"
+	IndexSetReadyReasonJobFailed              IndexSetReadyReason = "JobFailed"
+	IndexSetReadyReasonConfigMapError         IndexSetReadyReason = "ConfigMapError"
+	IndexSetReadyReasonCouchbaseError         IndexSetReadyReason = "CouchbaseError"
+	IndexSetReadyReasonTemplateError          IndexSetReadyReason = "TemplateError"
+	IndexSetReadyReasonInvalidSpec            IndexSetReadyReason = "InvalidSpec"
+	IndexSetReadyReasonWaitingForRollout      IndexSetReadyReason = "WaitingForRollout"
+	IndexSetReadyReasonWaitingForCollections  IndexSetReadyReason = "WaitingForCollections"
+	IndexSetReadyReasonWaitingForFunctions    IndexSetReadyReason = "WaitingForFunctions"
+	IndexSetReadyReasonPending                IndexSetReadyReason = "Pending"
+	IndexSetReadyReasonWaitingForDependencies IndexSetReadyReason = "WaitingForDependencies"
+	IndexSetReadyReasonCapacityExceeded       IndexSetReadyReason = "CapacityExceeded"
+
+	IndexSetDropBlockedReasonNotBlocked     IndexSetDropBlockedReason = "NotBlocked"
+	IndexSetDropBlockedReasonDropProtection IndexSetDropBlockedReason = "DropProtection"
+	IndexSetDropBlockedReasonInUse          IndexSetDropBlockedReason = "InUse"
+
+	IndexSetUnusedReasonNoUnusedIndices IndexSetUnusedReason = "NoUnusedIndices"
+	IndexSetUnusedReasonIndicesUnused   IndexSetUnusedReason = "IndicesUnused"
+
+	IndexSetQueryPlansReasonPlansValid    IndexSetQueryPlansReason = "PlansValid"
+	IndexSetQueryPlansReasonIndexNotUsed  IndexSetQueryPlansReason = "IndexNotUsed"
+	IndexSetQueryPlansReasonExplainFailed IndexSetQueryPlansReason = "ExplainFailed"
+
+	IndexSetCapacityReasonWithinCapacity   IndexSetCapacityReason = "WithinCapacity"
+	IndexSetCapacityReasonCapacityExceeded IndexSetCapacityReason = "CapacityExceeded"
 )
 
 func getStatus(status bool) v1.ConditionStatus {
@@ -87,6 +120,72 @@ func setReadyStatus(indexSet *v1beta1.CouchbaseIndexSet, status bool, reason Ind
 	})
 }
 
+func setDropBlocked(indexSet *v1beta1.CouchbaseIndexSet, protectedIndexes []cbim.GlobalSecondaryIndexIdentifier,
+	inUseIndexes []cbim.GlobalSecondaryIndexIdentifier) {
+
+	if len(protectedIndexes) == 0 && len(inUseIndexes) == 0 {
+		setDropBlockedStatus(indexSet, false, IndexSetDropBlockedReasonNotBlocked, "No index drops are blocked")
+		return
+	}
+
+	var (
+		reason   IndexSetDropBlockedReason
+		messages []string
+	)
+	if len(inUseIndexes) > 0 {
+		reason = IndexSetDropBlockedReasonInUse
+		messages = append(messages, "Indices in use were not dropped: "+joinIndexNames(inUseIndexes))
+	}
+	if len(protectedIndexes) > 0 {
+		reason = IndexSetDropBlockedReasonDropProtection
+		messages = append(messages, "Drop protected indices were not dropped: "+joinIndexNames(protectedIndexes))
+	}
+
+	setDropBlockedStatus(indexSet, true, reason, strings.Join(messages, "; "))
+}
+
+func joinIndexNames(identifiers []cbim.GlobalSecondaryIndexIdentifier) string {
+	names := make([]string, len(identifiers))
+	for i, v := range identifiers {
+		names[i] = v.ToString()
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func setDropBlockedStatus(indexSet *v1beta1.CouchbaseIndexSet, status bool, reason IndexSetDropBlockedReason, message string) {
+	meta.SetStatusCondition(&indexSet.Status.Conditions, v1.Condition{
+		Type:               ConditionTypeDropBlocked,
+		Status:             getStatus(status),
+		Message:            message,
+		Reason:             string(reason),
+		ObservedGeneration: indexSet.Generation,
+	})
+}
+
+func setUnused(indexSet *v1beta1.CouchbaseIndexSet, unusedIndexes []string) {
+	if len(unusedIndexes) == 0 {
+		setUnusedStatus(indexSet, false, IndexSetUnusedReasonNoUnusedIndices, "All indices have been scanned recently")
+	} else {
+		setUnusedStatus(indexSet, true, IndexSetUnusedReasonIndicesUnused,
+			"Indices have not been scanned recently: "+strings.Join(unusedIndexes, ", "))
+	}
+}
+
+func setUnusedStatus(indexSet *v1beta1.CouchbaseIndexSet, status bool, reason IndexSetUnusedReason, message string) {
+	meta.SetStatusCondition(&indexSet.Status.Conditions, v1.Condition{
+		Type:               ConditionTypeUnused,
+		Status:             getStatus(status),
+		Message:            message,
+		Reason:             string(reason),
+		ObservedGeneration: indexSet.Generation,
+	})
+}
+
+func removeUnusedCondition(indexSet *v1beta1.CouchbaseIndexSet) {
+	meta.RemoveStatusCondition(&indexSet.Status.Conditions, ConditionTypeUnused)
+}
+
 func getCurrentStateFromIndexSet(indexSet *v1beta1.CouchbaseIndexSet) IndexSetReadyReason {
 	readyCondition := meta.FindStatusCondition(indexSet.Status.Conditions, ConditionTypeReady)
 
@@ -17,6 +17,8 @@ limitations under the License.
 package controllers
 
 import (
+	"reflect"
+
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,7 +31,7 @@ import (
 func (context *CouchbaseIndexSetReconcileContext) reconcileConfigMap() (string, error) {
 	name := types.NamespacedName{
 		Namespace: context.IndexSet.Namespace,
-		Name:      context.IndexSet.Name + "-indexspec",
+		Name:      context.getResourceName() + "-indexspec",
 	}
 
 	var (
@@ -52,24 +54,33 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileConfigMap() (string,
 		}
 	}
 
-	yaml, err := cbim.GenerateYaml(&context.IndexSet, &context.DeletingIndexes)
+	yaml, err := cbim.GenerateYaml(&context.IndexSet, context.Indices, &context.GenerateResult)
 	if err != nil {
 		context.Error(err, "Error generating index spec")
 		return "", err
 	}
 
-	if configMap.Data == nil ||
-		len(configMap.Data) != 1 ||
-		configMap.Data["indices.yaml"] != yaml {
+	data := map[string]string{
+		"indices.yaml": yaml,
+	}
 
-		configMap.Data = map[string]string{
-			"indices.yaml": yaml,
+	if context.IndexSet.Spec.WriteDdl != nil && *context.IndexSet.Spec.WriteDdl && context.IndexSet.GetDeletionTimestamp() == nil {
+		ddl, err := cbim.GenerateDdl(context.IndexSet.Spec.BucketName, context.Indices)
+		if err != nil {
+			context.Error(err, "Error generating index DDL")
+			return "", err
 		}
-	} else {
+
+		data["indices.n1ql"] = ddl
+	}
+
+	if reflect.DeepEqual(configMap.Data, data) {
 		// Already in sync, do nothing
 		return name.Name, nil
 	}
 
+	configMap.Data = data
+
 	controllerutil.SetControllerReference(&context.IndexSet, &configMap, context.Reconciler.Scheme)
 
 	if isNew {
@@ -22,6 +22,7 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
@@ -30,8 +31,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
 	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
@@ -55,18 +58,36 @@ type CouchbaseIndexSetReconcileContext struct {
 	Reconciler *CouchbaseIndexSetReconciler
 
 	IndexSet         v1beta1.CouchbaseIndexSet
+	Indices          []v1beta1.GlobalSecondaryIndex
+	AppliedTemplates []v1beta1.AppliedIndexTemplate
 	ConnectionString string
 	AdminSecretName  string
-	DeletingIndexes  []cbim.GlobalSecondaryIndexIdentifier
-	IsDeleting       bool
+	CASecretName     string
+	MissingKeyspaces []string
+	MissingFunctions []string
+	// Dependencies which aren't Ready yet, in "kind/name" format
+	MissingDependencies []string
+	GenerateResult      cbim.GenerateResult
+	IsDeleting          bool
+
+	// Name of the cluster within an index set targeting multiple clusters, empty for a single cluster
+	ClusterName string
+	RolloutWait *rolloutWait
+	// Set when dropping the indices on a cluster which is no longer targeted by the index set
+	RemovingCluster bool
+	CleanupComplete bool
 }
 
 //+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseindexsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseindexsets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseindexsets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbaseindextemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasecollectionsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasequeryfunctionsets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=batch,namespace=system,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=couchbase.com,namespace=system,resources=couchbaseclusters,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",namespace=system,resources=secret,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -116,15 +137,75 @@ func (r *CouchbaseIndexSetReconciler) Reconcile(ctx context.Context, req ctrl.Re
 // Performs primary reconcilation. Any actions in this context may change the status of the index set, it is expected
 // that the caller will commit the changes.
 func (r *CouchbaseIndexSetReconciler) primaryReconcile(context *CouchbaseIndexSetReconcileContext) (ctrl.Result, error) {
+	// Expand any templates to get the full list of indices
+	if ok, result, err := context.reconcileTemplates(); !ok {
+		return result, err
+	}
+
+	// Add any indices defined as N1QL statements
+	if ok, result, err := context.reconcileDdl(); !ok {
+		return result, err
+	}
+
+	// Validate index keys before syncing, cleanup doesn't require valid indices since they will all be dropped
+	if err := cbim.ValidateIndices(context.Indices); err != nil && !context.IsDeleting {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec, err.Error())
+		return ctrl.Result{}, nil
+	}
+
 	// Update the status with the number of indices in the array
 	// This is useful for the print columns display for kubectl
-	context.IndexSet.Status.IndexCount = pointer.Int32Ptr(int32(len(context.IndexSet.Spec.Indices)))
+	context.IndexSet.Status.IndexCount = pointer.Int32Ptr(int32(len(context.Indices)))
+
+	// Find dependencies which aren't Ready yet, the sync waits for them
+	if ok, result, err := context.reconcileDependencies(); !ok {
+		return result, err
+	}
+
+	if len(context.IndexSet.Spec.Clusters) > 0 {
+		if context.IndexSet.Spec.Cluster != nil {
+			setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec, "Only one of cluster or clusters may be specified")
+			return ctrl.Result{}, nil
+		}
 
+		return context.reconcileClusters()
+	}
+
+	return context.reconcileSingleCluster()
+}
+
+// Syncs indices to the Couchbase cluster described by the index set
+func (context *CouchbaseIndexSetReconcileContext) reconcileCluster() (ctrl.Result, error) {
 	if ok, result, err := context.getConnectionInfo(); !ok {
 		return result, err
 	}
 
-	return context.reconcileJob()
+	// Track the connection so that the indices can be found again if the targeted clusters change
+	context.IndexSet.Status.Cluster = context.IndexSet.Spec.Cluster.DeepCopy()
+
+	// Expand any indices targeting multiple scopes or collections
+	if ok, result, err := context.reconcileKeyspaces(); !ok {
+		return result, err
+	}
+
+	// Find user-defined functions used by the indices which don't exist yet
+	if ok, result, err := context.reconcileFunctions(); !ok {
+		return result, err
+	}
+
+	// Recount now that indices targeting multiple scopes or collections are expanded
+	context.IndexSet.Status.IndexCount = pointer.Int32Ptr(int32(len(context.Indices)))
+
+	// Track drop protection in the status so that it is retained after indices are removed from the spec
+	context.IndexSet.Status.ProtectedIndices = cbim.ToSortedStrings(cbim.GetProtectedIndexes(&context.IndexSet, context.Indices))
+
+	result, err := context.reconcileJob()
+
+	// Collect index usage and advice after the job is tracked so that newly created indices are included
+	result = mergeResults(result, context.reconcileUsageTracking())
+	result = mergeResults(result, context.reconcileAdvisor())
+
+	return result, err
 }
 
 func (context *CouchbaseIndexSetReconcileContext) addFinalizer() error {
@@ -139,6 +220,31 @@ func (context *CouchbaseIndexSetReconcileContext) removeFinalizer() error {
 	return context.Reconciler.Update(context.Ctx, &context.IndexSet)
 }
 
+// Records that index cleanup is complete during deletion. The finalizer is removed immediately for a single cluster,
+// otherwise it is removed once cleanup is complete for every cluster, including clusters which are no longer targeted.
+func (context *CouchbaseIndexSetReconcileContext) completeCleanup() error {
+	if context.ClusterName != "" || context.RemovingCluster {
+		context.CleanupComplete = true
+		return nil
+	}
+
+	if len(context.IndexSet.Status.Clusters) > 0 {
+		context.V(1).Info("Waiting for cleanup of removed clusters")
+		return nil
+	}
+
+	return context.removeFinalizer()
+}
+
+// Returns the base name for the ConfigMaps and Jobs created for the index set
+func (context *CouchbaseIndexSetReconcileContext) getResourceName() string {
+	if context.ClusterName != "" {
+		return context.IndexSet.Name + "-" + context.ClusterName
+	}
+
+	return context.IndexSet.Name
+}
+
 func ignoreStatusChangePredicate() predicate.Predicate {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
@@ -150,9 +256,46 @@ func ignoreStatusChangePredicate() predicate.Predicate {
 				}
 			}
 
+			if newCollectionSet, ok := e.ObjectNew.(*v1beta1.CouchbaseCollectionSet); ok {
+				if oldCollectionSet, ok := e.ObjectOld.(*v1beta1.CouchbaseCollectionSet); ok {
+					// Index sets may be waiting for collections to be created
+
+					return !reflect.DeepEqual(oldCollectionSet.Status.Conditions, newCollectionSet.Status.Conditions)
+				}
+			}
+
+			if newFunctionSet, ok := e.ObjectNew.(*v1beta1.CouchbaseQueryFunctionSet); ok {
+				if oldFunctionSet, ok := e.ObjectOld.(*v1beta1.CouchbaseQueryFunctionSet); ok {
+					// Index sets may be waiting for functions to be created
+
+					return !reflect.DeepEqual(oldFunctionSet.Status.Conditions, newFunctionSet.Status.Conditions) ||
+						!reflect.DeepEqual(oldFunctionSet.Status.Functions, newFunctionSet.Status.Functions)
+				}
+			}
+
+			if newIndexSet, ok := e.ObjectNew.(*v1beta1.CouchbaseIndexSet); ok {
+				if oldIndexSet, ok := e.ObjectOld.(*v1beta1.CouchbaseIndexSet); ok &&
+					isReady(oldIndexSet.Status.Conditions, oldIndexSet.Generation) != isReady(newIndexSet.Status.Conditions, newIndexSet.Generation) {
+					// Other index sets may depend on this index set becoming ready
+
+					return true
+				}
+			}
+
+			if newConfigMap, ok := e.ObjectNew.(*corev1.ConfigMap); ok && metav1.GetControllerOf(newConfigMap) == nil {
+				if oldConfigMap, ok := e.ObjectOld.(*corev1.ConfigMap); ok {
+					// Index sets may read DDL or advisor queries from a ConfigMap, which doesn't have a generation
+
+					return !reflect.DeepEqual(oldConfigMap.Data, newConfigMap.Data)
+				}
+			}
+
 			// We don't want to reconcile every time the status changes on the CouchbaseIndexSet or ConfigMap
+			// Sync requests and sync window overrides are made by annotation, which doesn't change the generation
 			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
-				!reflect.DeepEqual(e.ObjectOld.GetFinalizers(), e.ObjectNew.GetFinalizers())
+				!reflect.DeepEqual(e.ObjectOld.GetFinalizers(), e.ObjectNew.GetFinalizers()) ||
+				e.ObjectOld.GetAnnotations()[syncRequestedAtAnnotationKey] != e.ObjectNew.GetAnnotations()[syncRequestedAtAnnotationKey] ||
+				e.ObjectOld.GetAnnotations()[syncWindowOverrideAnnotationKey] != e.ObjectNew.GetAnnotations()[syncWindowOverrideAnnotationKey]
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			if _, ok := e.Object.(*batchv1.Job); ok {
@@ -173,6 +316,11 @@ func (r *CouchbaseIndexSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&v1beta1.CouchbaseIndexSet{}).
 		Owns(&batchv1.Job{}).
 		Owns(&corev1.ConfigMap{}).
+		Watches(&source.Kind{Type: &v1beta1.CouchbaseIndexTemplate{}}, handler.EnqueueRequestsFromMapFunc(r.findIndexSetsForTemplate)).
+		Watches(&source.Kind{Type: &v1beta1.CouchbaseCollectionSet{}}, handler.EnqueueRequestsFromMapFunc(r.findIndexSetsForCollectionSet)).
+		Watches(&source.Kind{Type: &v1beta1.CouchbaseQueryFunctionSet{}}, handler.EnqueueRequestsFromMapFunc(r.findIndexSetsForQueryFunctionSet)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.findIndexSetsForConfigMap)).
+		Watches(&source.Kind{Type: &v1beta1.CouchbaseIndexSet{}}, handler.EnqueueRequestsFromMapFunc(r.findIndexSetsForIndexSet)).
 		WithEventFilter(ignoreStatusChangePredicate()).
 		WithOptions(controller.Options{}).
 		Complete(r)
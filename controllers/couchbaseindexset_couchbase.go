@@ -17,102 +17,58 @@ limitations under the License.
 package controllers
 
 import (
-	"fmt"
-
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
-	couchbasev2 "github.com/brantburnett/couchbase-index-operator/couchbase/v2"
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
 )
 
+// Resolves the connection to the cluster targeted by the index set, using the same connection resolution as other
+// resources which target a cluster
 func (context *CouchbaseIndexSetReconcileContext) getConnectionInfo() (bool, ctrl.Result, error) {
-	if context.IndexSet.Spec.Cluster.ClusterRef != nil {
-		if getResult, err := context.getCluster(); !getResult.IsZero() || err != nil {
-			if context.IsDeleting && err == nil {
-				// The cluster can't be found, etc. In this case during a delete we don't need to worry
-				// about cleanup, just remove the finalizer.
-
-				controllerutil.RemoveFinalizer(&context.IndexSet, indexSetFinalizer)
-				if err := context.Reconciler.Update(context.Ctx, &context.IndexSet); err != nil {
-					return false, ctrl.Result{}, err
-				}
-
-				return false, ctrl.Result{}, nil
-			}
-
-			return false, getResult, err
-		}
-	} else if context.IndexSet.Spec.Cluster.Manual != nil {
-		context.ConnectionString = context.IndexSet.Spec.Cluster.Manual.ConnectionString
-		context.AdminSecretName = context.IndexSet.Spec.Cluster.Manual.SecretName
-	} else {
-		setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, "Missing connection info")
-
-		return false, ctrl.Result{}, nil
+	cluster := v1beta1.CouchbaseCluster{}
+	if context.IndexSet.Spec.Cluster != nil {
+		cluster = *context.IndexSet.Spec.Cluster
 	}
 
-	// Success
-	return true, ctrl.Result{}, nil
-}
+	connection, message, err := getClusterConnection(context.Ctx, context.Reconciler, context.IndexSet.Namespace,
+		cluster, context.IndexSet.Spec.BucketName)
+	if err != nil || message != "" {
+		if context.IsDeleting && err == nil {
+			// The cluster can't be found, etc. In this case during a delete we don't need to worry
+			// about cleanup, just complete it.
 
-func (context *CouchbaseIndexSetReconcileContext) getCluster() (ctrl.Result, error) {
-	clusterName := types.NamespacedName{
-		Namespace: context.IndexSet.Namespace,
-		Name:      context.IndexSet.Spec.Cluster.ClusterRef.Name,
-	}
+			if err := context.completeCleanup(); err != nil {
+				return false, ctrl.Result{}, err
+			}
 
-	cluster := couchbasev2.CouchbaseCluster{}
-	if err := context.Reconciler.Get(context.Ctx, clusterName, &cluster); err != nil {
-		if apierrors.IsNotFound(err) {
-			setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, "Cluster is not found")
-			return ctrl.Result{Requeue: true}, nil
+			return false, ctrl.Result{}, nil
 		}
 
-		setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, err.Error())
-		return ctrl.Result{}, err
-	}
-
-	context.ConnectionString = fmt.Sprintf("couchbase://%s-srv", cluster.ObjectMeta.Name)
-	if context.IndexSet.Spec.Cluster.ClusterRef.SecretName != nil {
-		// Prefer the secret name if provided in our spec
-		context.AdminSecretName = *context.IndexSet.Spec.Cluster.ClusterRef.SecretName
-	} else {
-		// Fallback to the admin secret name
-		context.AdminSecretName = cluster.Spec.Security.AdminSecret
-	}
-
-	available := false
-	for _, condition := range cluster.Status.Conditions {
-		if condition.Type == "Available" && condition.Status == v1.ConditionTrue {
-			available = true
-			break
+		if err != nil {
+			setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, err.Error())
+			return false, ctrl.Result{}, err
 		}
-	}
 
-	if !available {
-		setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, "Cluster is not available")
-		return ctrl.Result{Requeue: true}, nil
-	}
+		setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, message)
 
-	if cluster.Spec.Buckets.Managed {
-		// If the cluster is managing buckets, we can confirm the bucket exists via the cluster status
+		// Clusters which aren't available yet are checked again, missing connection info requires a spec change
+		return false, ctrl.Result{Requeue: cluster.ClusterRef != nil}, nil
+	}
 
-		foundBucket := false
-		for _, bucket := range cluster.Status.Buckets {
-			if bucket.Name == context.IndexSet.Spec.BucketName {
-				foundBucket = true
-				break
-			}
-		}
+	context.ConnectionString = connection.ConnectionString
+	context.AdminSecretName = connection.AdminSecretName
+	context.CASecretName = connection.CASecretName
 
-		if !foundBucket {
-			setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, "Bucket is not found")
-			return ctrl.Result{Requeue: true}, nil
-		}
-	}
+	// Success
+	return true, ctrl.Result{}, nil
+}
 
-	return ctrl.Result{}, nil
+// Creates a client for the Couchbase REST APIs, must be called after getConnectionInfo
+func (context *CouchbaseIndexSetReconcileContext) getRestClient() (*cbrest.Client, error) {
+	return newRestClient(context.Ctx, context.Reconciler, context.IndexSet.Namespace, clusterConnection{
+		ConnectionString: context.ConnectionString,
+		AdminSecretName:  context.AdminSecretName,
+		CASecretName:     context.CASecretName,
+	})
 }
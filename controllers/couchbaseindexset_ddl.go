@@ -0,0 +1,116 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+// Parses indices defined as N1QL statements, including any from the referenced ConfigMap, and merges them with the
+// other indices
+func (context *CouchbaseIndexSetReconcileContext) reconcileDdl() (bool, ctrl.Result, error) {
+	ddl := context.IndexSet.Spec.Ddl
+	if ddl == nil {
+		return true, ctrl.Result{}, nil
+	}
+
+	configMapScript := ""
+	if ddl.ConfigMapRef != nil {
+		name := types.NamespacedName{
+			Namespace: context.IndexSet.Namespace,
+			Name:      ddl.ConfigMapRef.Name,
+		}
+
+		configMap := corev1.ConfigMap{}
+		if err := context.Reconciler.Get(context.Ctx, name, &configMap); err != nil {
+			if !apierrors.IsNotFound(err) {
+				context.Error(err, "unable to fetch DDL ConfigMap")
+				return false, ctrl.Result{}, err
+			}
+
+			if context.IsDeleting {
+				// Cleanup doesn't require the DDL indices, they will all be dropped
+				return true, ctrl.Result{}, nil
+			}
+
+			setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec, fmt.Sprintf("DDL ConfigMap %s is not found", name.Name))
+			return false, ctrl.Result{}, nil
+		}
+
+		script, ok := configMap.Data[ddl.ConfigMapRef.Key]
+		if !ok && !context.IsDeleting {
+			setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec,
+				fmt.Sprintf("DDL ConfigMap %s has no key %s", name.Name, ddl.ConfigMapRef.Key))
+			return false, ctrl.Result{}, nil
+		}
+
+		configMapScript = script
+	}
+
+	indices, err := cbim.ParseIndexSetDdl(&context.IndexSet, configMapScript)
+	if err == nil {
+		indices, err = cbim.MergeIndices(context.Indices, indices)
+	}
+	if err != nil {
+		if context.IsDeleting {
+			return true, ctrl.Result{}, nil
+		}
+
+		setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec, "Invalid DDL: "+err.Error())
+		return false, ctrl.Result{}, nil
+	}
+
+	context.Indices = indices
+
+	return true, ctrl.Result{}, nil
+}
+
+// Finds index sets which read DDL or advisor queries from a ConfigMap
+func (r *CouchbaseIndexSetReconciler) findIndexSetsForConfigMap(configMap client.Object) []reconcile.Request {
+	indexSets := v1beta1.CouchbaseIndexSetList{}
+	if err := r.List(context.Background(), &indexSets, client.InNamespace(configMap.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, indexSet := range indexSets.Items {
+		ddl := indexSet.Spec.Ddl
+		advisor := indexSet.Spec.Advisor
+		if (ddl != nil && ddl.ConfigMapRef != nil && ddl.ConfigMapRef.Name == configMap.GetName()) ||
+			(advisor != nil && advisor.ConfigMapName == configMap.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: indexSet.Namespace,
+					Name:      indexSet.Name,
+				},
+			})
+		}
+	}
+
+	return requests
+}
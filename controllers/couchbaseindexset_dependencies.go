@@ -0,0 +1,208 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Kinds of resources an index set may depend on
+const (
+	dependencyKindIndexSet         = "CouchbaseIndexSet"
+	dependencyKindCollectionSet    = "CouchbaseCollectionSet"
+	dependencyKindQueryFunctionSet = "CouchbaseQueryFunctionSet"
+)
+
+// Finds dependencies of the index set which aren't Ready yet. Dependencies which are being deleted are ignored, as
+// are dependencies which no longer exist when the index set is being deleted. Index sets which depend on each other,
+// directly or through other index sets, would wait forever so the cycle is reported instead.
+func (context *CouchbaseIndexSetReconcileContext) reconcileDependencies() (bool, ctrl.Result, error) {
+	context.MissingDependencies = nil
+
+	if !context.IsDeleting {
+		// Cleanup ignores dependencies which are being deleted, so a cycle can't block it
+		cycle, err := context.findDependencyCycle()
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+
+		if cycle != nil {
+			setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec, "Dependencies form a cycle: "+strings.Join(cycle, " -> "))
+			return false, ctrl.Result{}, nil
+		}
+	}
+
+	for _, dependency := range context.IndexSet.Spec.DependsOn {
+		kind := getDependencyKind(dependency)
+		if kind == dependencyKindIndexSet && dependency.Name == context.IndexSet.Name {
+			continue
+		}
+
+		object, conditions := newDependencyObject(kind)
+		if object == nil {
+			setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec, fmt.Sprintf("Unsupported dependency kind %s", kind))
+			return false, ctrl.Result{}, nil
+		}
+
+		name := types.NamespacedName{
+			Namespace: context.IndexSet.Namespace,
+			Name:      dependency.Name,
+		}
+
+		if err := context.Reconciler.Get(context.Ctx, name, object); err != nil {
+			if !apierrors.IsNotFound(err) {
+				context.Error(err, "unable to fetch dependency", "kind", kind, "name", dependency.Name)
+				return false, ctrl.Result{}, err
+			}
+
+			if !context.IsDeleting {
+				context.MissingDependencies = append(context.MissingDependencies, kind+"/"+dependency.Name)
+			}
+			continue
+		}
+
+		if object.GetDeletionTimestamp() == nil && !isReady(*conditions, object.GetGeneration()) {
+			context.MissingDependencies = append(context.MissingDependencies, kind+"/"+dependency.Name)
+		}
+	}
+
+	return true, ctrl.Result{}, nil
+}
+
+// Follows the dependencies of index sets, starting with this index set, looking for a path back to this index set.
+// Returns the names of the index sets in the cycle, or nil if there is none. Index sets which don't exist are skipped,
+// they are reported as missing dependencies.
+func (context *CouchbaseIndexSetReconcileContext) findDependencyCycle() ([]string, error) {
+	visited := map[string]bool{}
+
+	var visit func(indexSet *v1beta1.CouchbaseIndexSet, path []string) ([]string, error)
+	visit = func(indexSet *v1beta1.CouchbaseIndexSet, path []string) ([]string, error) {
+		for _, dependency := range indexSet.Spec.DependsOn {
+			if getDependencyKind(dependency) != dependencyKindIndexSet {
+				continue
+			}
+
+			dependencyPath := append(append([]string{}, path...), dependency.Name)
+			if dependency.Name == context.IndexSet.Name {
+				return dependencyPath, nil
+			}
+
+			if visited[dependency.Name] {
+				continue
+			}
+			visited[dependency.Name] = true
+
+			next := v1beta1.CouchbaseIndexSet{}
+			if err := context.Reconciler.Get(context.Ctx, types.NamespacedName{
+				Namespace: context.IndexSet.Namespace,
+				Name:      dependency.Name,
+			}, &next); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+
+				context.Error(err, "unable to fetch dependency", "kind", dependencyKindIndexSet, "name", dependency.Name)
+				return nil, err
+			}
+
+			if cycle, err := visit(&next, dependencyPath); cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+
+		return nil, nil
+	}
+
+	return visit(&context.IndexSet, []string{context.IndexSet.Name})
+}
+
+func getDependencyKind(dependency v1beta1.CouchbaseIndexSetDependency) string {
+	if dependency.Kind == "" {
+		return dependencyKindIndexSet
+	}
+
+	return dependency.Kind
+}
+
+// Creates an empty object of the given kind, along with a pointer to its conditions once it is fetched
+func newDependencyObject(kind string) (client.Object, *[]v1.Condition) {
+	switch kind {
+	case dependencyKindIndexSet:
+		object := &v1beta1.CouchbaseIndexSet{}
+		return object, &object.Status.Conditions
+	case dependencyKindCollectionSet:
+		object := &v1beta1.CouchbaseCollectionSet{}
+		return object, &object.Status.Conditions
+	case dependencyKindQueryFunctionSet:
+		object := &v1beta1.CouchbaseQueryFunctionSet{}
+		return object, &object.Status.Conditions
+	default:
+		return nil, nil
+	}
+}
+
+// Returns true if the Ready condition is true and reflects the current generation of the resource
+func isReady(conditions []v1.Condition, generation int64) bool {
+	readyCondition := meta.FindStatusCondition(conditions, ConditionTypeReady)
+
+	return readyCondition != nil && readyCondition.Status == v1.ConditionTrue && readyCondition.ObservedGeneration == generation
+}
+
+// Returns true if the index set depends on the resource of the given kind and name
+func dependsOn(indexSet *v1beta1.CouchbaseIndexSet, kind string, name string) bool {
+	for _, dependency := range indexSet.Spec.DependsOn {
+		if getDependencyKind(dependency) == kind && dependency.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Maps an index set to reconcile requests for the index sets which depend on it
+func (r *CouchbaseIndexSetReconciler) findIndexSetsForIndexSet(object client.Object) []reconcile.Request {
+	indexSets := v1beta1.CouchbaseIndexSetList{}
+	if err := r.List(context.Background(), &indexSets, client.InNamespace(object.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, indexSet := range indexSets.Items {
+		if dependsOn(&indexSet, dependencyKindIndexSet, object.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: indexSet.Namespace,
+					Name:      indexSet.Name,
+				},
+			})
+		}
+	}
+
+	return requests
+}
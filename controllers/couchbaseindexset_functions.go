@@ -0,0 +1,133 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
+	"github.com/brantburnett/couchbase-index-operator/sqlpp"
+)
+
+// Finds user-defined functions called by index expressions which are declared by a CouchbaseQueryFunctionSet
+// on the same bucket but haven't been created yet.
+func (context *CouchbaseIndexSetReconcileContext) reconcileFunctions() (bool, ctrl.Result, error) {
+	context.MissingFunctions = nil
+
+	if context.IsDeleting {
+		return true, ctrl.Result{}, nil
+	}
+
+	functionSets := v1beta1.CouchbaseQueryFunctionSetList{}
+	if err := context.Reconciler.List(context.Ctx, &functionSets, client.InNamespace(context.IndexSet.Namespace)); err != nil {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, "Unable to list function sets: "+err.Error())
+		return false, ctrl.Result{}, err
+	}
+
+	// Map of lower case "scope.name" keys to whether the function has been created
+	declared := map[string]bool{}
+	for _, functionSet := range functionSets.Items {
+		if functionSet.Spec.BucketName != context.IndexSet.Spec.BucketName {
+			continue
+		}
+
+		applied := map[string]bool{}
+		for _, function := range functionSet.Status.Functions {
+			applied[strings.ToLower(function.Name)] = true
+		}
+
+		for _, scope := range functionSet.Spec.Scopes {
+			for _, function := range scope.Functions {
+				key := strings.ToLower(scope.Name + "." + function.Name)
+				declared[key] = declared[key] || applied[key]
+			}
+		}
+	}
+
+	if len(declared) == 0 {
+		return true, ctrl.Result{}, nil
+	}
+
+	missing := map[string]bool{}
+	for _, gsi := range context.Indices {
+		scopeName := cbim.GetIndexIdentifier(gsi).ScopeName
+
+		for _, name := range getIndexFunctionCalls(gsi) {
+			key := scopeName + "." + name
+			if created, ok := declared[strings.ToLower(key)]; ok && !created {
+				missing[key] = true
+			}
+		}
+	}
+
+	for key := range missing {
+		context.MissingFunctions = append(context.MissingFunctions, key)
+	}
+	sort.Strings(context.MissingFunctions)
+
+	return true, ctrl.Result{}, nil
+}
+
+// Gets the names of all functions called by the expressions of an index
+func getIndexFunctionCalls(gsi v1beta1.GlobalSecondaryIndex) []string {
+	expressions := append([]string{}, cbim.GetIndexKey(gsi)...)
+	if gsi.Condition != nil {
+		expressions = append(expressions, *gsi.Condition)
+	}
+	if gsi.Partition != nil {
+		expressions = append(expressions, gsi.Partition.Expressions...)
+	}
+
+	result := []string{}
+	for _, expression := range expressions {
+		result = append(result, sqlpp.FindFunctionCalls(expression)...)
+	}
+
+	return result
+}
+
+// Maps a CouchbaseQueryFunctionSet to reconcile requests for the index sets on the same bucket or which depend on it
+func (r *CouchbaseIndexSetReconciler) findIndexSetsForQueryFunctionSet(functionSet client.Object) []reconcile.Request {
+	bucketName := functionSet.(*v1beta1.CouchbaseQueryFunctionSet).Spec.BucketName
+
+	indexSets := v1beta1.CouchbaseIndexSetList{}
+	if err := r.List(context.Background(), &indexSets, client.InNamespace(functionSet.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, indexSet := range indexSets.Items {
+		if indexSet.Spec.BucketName == bucketName || dependsOn(&indexSet, dependencyKindQueryFunctionSet, functionSet.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: indexSet.Namespace,
+					Name:      indexSet.Name,
+				},
+			})
+		}
+	}
+
+	return requests
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -39,6 +40,13 @@ import (
 
 const gsiAnnotationKey = "couchbase.btburnett.com/gsi"
 
+// Annotation on a job with a hash of the fully expanded indices it syncs, including those read from DDL or fanned out
+// across collections, which may change without changing the generation of the index set
+const indicesHashAnnotationKey = "couchbase.btburnett.com/indices-hash"
+
+// Annotation on an index set requesting an immediate sync, any change to the value starts a sync without waiting
+const syncRequestedAtAnnotationKey = "couchbase.btburnett.com/sync-requested-at"
+
 type gsiAnnotation struct {
 	Adding   []string `json:"adding,omitempty"`
 	Deleting []string `json:"deleting,omitempty"`
@@ -61,9 +69,26 @@ func getTimeToNextSync(lastUpdate time.Time, syncInterval time.Duration) time.Du
 	}
 }
 
+func getTimeToNextPendingDrop(indexSet *v1beta1.CouchbaseIndexSet) (time.Duration, bool) {
+	if len(indexSet.Status.PendingDrops) == 0 {
+		return 0, false
+	}
+
+	nextDrop := indexSet.Status.PendingDrops[0].DropAfter.Time
+	for _, v := range indexSet.Status.PendingDrops[1:] {
+		if v.DropAfter.Time.Before(nextDrop) {
+			nextDrop = v.DropAfter.Time
+		}
+	}
+
+	// Use the same approach as sync intervals so that past deadlines result in an immediate sync
+	return getTimeToNextSync(nextDrop, 0), true
+}
+
 func isCurrentJob(context *CouchbaseIndexSetReconcileContext, job *batchv1.Job) bool {
 	if context.IsDeleting {
-		return job.Labels["deletion"] == "true"
+		// Lifting drop protection during deletion changes the generation, and requires another cleanup job
+		return job.Labels["deletion"] == "true" && job.Labels["generation"] == fmt.Sprintf("%d", context.IndexSet.Generation)
 	} else {
 		jobGeneration := int64(0)
 		var err error
@@ -71,10 +96,25 @@ func isCurrentJob(context *CouchbaseIndexSetReconcileContext, job *batchv1.Job)
 			jobGeneration = 0
 		}
 
-		return jobGeneration == context.IndexSet.Generation
+		// Changes to instantiated templates or the expanded indices also require a new sync
+		return jobGeneration == context.IndexSet.Generation &&
+			job.GetAnnotations()[templatesAnnotationKey] == getTemplatesAnnotation(context.AppliedTemplates) &&
+			job.GetAnnotations()[indicesHashAnnotationKey] == getIndicesHash(context.Indices)
 	}
 }
 
+// Gets a stable hash of a list of indices. Expressions are normalized first so that reformatting them, such as in a
+// DDL ConfigMap, doesn't require a new sync.
+func getIndicesHash(indices []v1beta1.GlobalSecondaryIndex) string {
+	normalized := make([]v1beta1.GlobalSecondaryIndex, len(indices))
+	for i, gsi := range indices {
+		normalized[i] = cbim.NormalizeIndex(gsi)
+	}
+
+	indicesJSON, _ := json.Marshal(normalized)
+	return getDefinitionHash(string(indicesJSON))
+}
+
 func updateIndexStatus(indexSet *v1beta1.CouchbaseIndexSet, gsiAnnotationValue string) {
 	if gsiAnnotationValue == "" {
 		return
@@ -109,12 +149,22 @@ func updateIndexStatus(indexSet *v1beta1.CouchbaseIndexSet, gsiAnnotationValue s
 }
 
 func (context *CouchbaseIndexSetReconcileContext) getMostRecentJob() (jobLookupResult, error) {
-	var labelRequirement *labels.Requirement
+	var labelRequirement, clusterRequirement *labels.Requirement
 	var err error
 	if labelRequirement, err = labels.NewRequirement("controller-uid", selection.Equals, []string{string(context.IndexSet.GetUID())}); err != nil {
 		return jobLookupResult{}, err
 	}
 
+	// Jobs for each cluster of an index set targeting multiple clusters are tracked separately
+	if context.ClusterName != "" {
+		clusterRequirement, err = labels.NewRequirement(indexSetClusterLabel, selection.Equals, []string{context.ClusterName})
+	} else {
+		clusterRequirement, err = labels.NewRequirement(indexSetClusterLabel, selection.DoesNotExist, nil)
+	}
+	if err != nil {
+		return jobLookupResult{}, err
+	}
+
 	// Find the most recent job, deleting any jobs found which are both old and not the most recent
 	result := jobLookupResult{}
 	mostRecentJobTimestamp := time.Time{}
@@ -124,7 +174,7 @@ func (context *CouchbaseIndexSetReconcileContext) getMostRecentJob() (jobLookupR
 	for moreItems := true; moreItems; {
 		var jobList = batchv1.JobList{}
 		err = context.Reconciler.List(context.Ctx, &jobList, &client.ListOptions{
-			LabelSelector: labels.NewSelector().Add(*labelRequirement),
+			LabelSelector: labels.NewSelector().Add(*labelRequirement, *clusterRequirement),
 			Namespace:     context.IndexSet.Namespace,
 			Limit:         100,
 			Continue:      continuationToken,
@@ -198,9 +248,13 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileJob() (ctrl.Result, e
 		if jobStatus == jobCompleted {
 			// We always want to track indices, even if we're about to start a fresh run, so do that first
 			updateIndexStatus(&context.IndexSet, job.GetAnnotations()[gsiAnnotationKey])
+			context.IndexSet.Status.Templates = parseTemplatesAnnotation(job.GetAnnotations()[templatesAnnotationKey])
 		}
 	}
 
+	// Track removed indices waiting for the drop grace period, this must follow any updates to the tracked indices
+	context.IndexSet.Status.PendingDrops = cbim.GetPendingDrops(&context.IndexSet, context.Indices, time.Now())
+
 	if isCurrentJob {
 		// If this job is the current job, handle status updates for success/failure and sleeps
 		// Note that if we've reached this code, the status is definitely etiher jobFailed or jobCompleted
@@ -220,7 +274,9 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileJob() (ctrl.Result, e
 					setNotReady(&context.IndexSet, IndexSetReadyReasonJobFailed, "Sync failed")
 				}
 
-				return ctrl.Result{RequeueAfter: timeToNextSync}, nil
+				if !context.isSyncRequested() {
+					return ctrl.Result{RequeueAfter: timeToNextSync}, nil
+				}
 			}
 
 			// Fall through to create a new job below
@@ -229,17 +285,34 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileJob() (ctrl.Result, e
 			if context.IsDeleting {
 				context.V(1).Info("Cleanup job successful")
 
-				// We're done with index cleanup, remove the finalizer
-				if err := context.removeFinalizer(); err != nil {
+				// Protected indices would be orphaned once the finalizer is removed, so wait for protection to be lifted
+				if protectedIndexes := cbim.GetRemovedIndexes(&context.IndexSet, context.Indices).ProtectedIndexes; len(protectedIndexes) > 0 {
+					setDropBlocked(&context.IndexSet, protectedIndexes, nil)
+					setNotReady(&context.IndexSet, IndexSetReadyReasonPending,
+						"Deletion is waiting for drop protection to be lifted: "+joinIndexNames(protectedIndexes))
+
+					return ctrl.Result{}, nil
+				}
+
+				// We're done with index cleanup
+				if err := context.completeCleanup(); err != nil {
 					return ctrl.Result{}, err
 				}
 
 				return ctrl.Result{}, nil
 			}
 
+			// Check that queries still use the expected indices as synced by this job
+			context.reconcileQueryPlans(job.Status.CompletionTime.Time)
+
 			// Since the most recent job was successful, sleep 5 minutes from the time it completed
 			timeToNextSync := getTimeToNextSync(job.Status.CompletionTime.Time, time.Minute*5)
 
+			// Sync sooner if a pending drop is due before then
+			if timeToNextDrop, ok := getTimeToNextPendingDrop(&context.IndexSet); ok && timeToNextDrop < timeToNextSync {
+				timeToNextSync = timeToNextDrop
+			}
+
 			if timeToNextSync > 0 {
 				if getCurrentStateFromIndexSet(&context.IndexSet) != IndexSetReadyReasonInSync {
 					context.V(1).Info("Index sync job succeeded")
@@ -248,7 +321,9 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileJob() (ctrl.Result, e
 					setReadyInSync(&context.IndexSet)
 				}
 
-				return ctrl.Result{RequeueAfter: timeToNextSync}, nil
+				if !context.isSyncRequested() {
+					return ctrl.Result{RequeueAfter: timeToNextSync}, nil
+				}
 			}
 
 			// Fall through to create a new job below
@@ -263,6 +338,52 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileJob() (ctrl.Result, e
 		return ctrl.Result{}, nil
 	}
 
+	// Hold syncs until the next sync window, a completed job for the current spec means this is a periodic resync
+	if ok, result := context.reconcileSyncWindows(isCurrentJob && jobStatus == jobCompleted); !ok {
+		return result, nil
+	}
+
+	// Wait for earlier clusters in an ordered rollout before syncing this cluster
+	if !context.IsDeleting && context.RolloutWait != nil {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonWaitingForRollout, context.RolloutWait.Message)
+		return ctrl.Result{RequeueAfter: context.RolloutWait.RequeueAfter}, nil
+	}
+
+	// Wait for scopes and collections to be created, the sync would fail without them
+	if !context.IsDeleting && len(context.MissingKeyspaces) > 0 {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonWaitingForCollections,
+			"Waiting for collections to be created: "+strings.Join(context.MissingKeyspaces, ", "))
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	// Wait for user-defined functions used by index expressions, the index creation would fail without them
+	if !context.IsDeleting && len(context.MissingFunctions) > 0 {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonWaitingForFunctions,
+			"Waiting for functions to be created: "+strings.Join(context.MissingFunctions, ", "))
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	// Wait for dependencies to be Ready, this also applies to cleanup so that drops may be ordered after other changes
+	if len(context.MissingDependencies) > 0 {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonWaitingForDependencies,
+			"Waiting for dependencies to be ready: "+strings.Join(context.MissingDependencies, ", "))
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	// Check for removed indices which are still in use before deciding which indices to drop
+
+	context.reconcileIndexUsage()
+
+	// Make sure the index service has room for any new indices, building them could otherwise degrade the service
+
+	if ok, result, err := context.reconcileCapacity(); !ok {
+		if err != nil {
+			setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, "Unable to check index capacity: "+err.Error())
+		}
+
+		return result, err
+	}
+
 	// Update the config map before starting the job
 
 	if configMapName, err := context.reconcileConfigMap(); err != nil {
@@ -273,6 +394,8 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileJob() (ctrl.Result, e
 		context.IndexSet.Status.ConfigMapName = configMapName
 	}
 
+	setDropBlocked(&context.IndexSet, context.GenerateResult.ProtectedIndexes, context.GenerateResult.InUseIndexes)
+
 	// Create the job
 
 	if err := context.createJob(); err != nil {
@@ -280,6 +403,9 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileJob() (ctrl.Result, e
 		return ctrl.Result{}, err
 	}
 
+	// Any sync request made before now is satisfied by this job
+	context.IndexSet.Status.LastSyncRequest = context.IndexSet.GetAnnotations()[syncRequestedAtAnnotationKey]
+
 	// Since we started a new job, we can clean up the old one if it's old
 	if job != nil {
 		_ = context.deleteJobIfOld(job)
@@ -288,29 +414,54 @@ func (context *CouchbaseIndexSetReconcileContext) reconcileJob() (ctrl.Result, e
 	return ctrl.Result{}, nil
 }
 
+// Returns true if the sync-requested-at annotation has changed since the most recent sync was started
+func (context *CouchbaseIndexSetReconcileContext) isSyncRequested() bool {
+	requestedAt := context.IndexSet.GetAnnotations()[syncRequestedAtAnnotationKey]
+	if requestedAt == "" || requestedAt == context.IndexSet.Status.LastSyncRequest {
+		return false
+	}
+
+	context.V(1).Info("Immediate sync requested", "requestedAt", requestedAt)
+	return true
+}
+
 func (context *CouchbaseIndexSetReconcileContext) createJob() error {
 	context.V(1).Info("Creating index sync job")
 
 	gsiAnnotation := gsiAnnotation{
-		Adding:   make([]string, len(context.IndexSet.Spec.Indices)),
-		Deleting: make([]string, len(context.DeletingIndexes)),
+		Adding:   []string{},
+		Deleting: make([]string, len(context.GenerateResult.DeletingIndexes)),
 	}
-	for i, v := range context.IndexSet.Spec.Indices {
-		gsiAnnotation.Adding[i] = cbim.GetIndexIdentifier(v).ToString()
+	if !context.IsDeleting {
+		// Cleanup jobs don't create indices, tracking them would block cleanup if they are drop protected
+		for _, v := range context.Indices {
+			gsiAnnotation.Adding = append(gsiAnnotation.Adding, cbim.GetIndexIdentifier(v).ToString())
+		}
 	}
-	for i, v := range context.DeletingIndexes {
+	for i, v := range context.GenerateResult.DeletingIndexes {
 		gsiAnnotation.Deleting[i] = v.ToString()
 	}
 	gsiAnnotationValue, _ := json.Marshal(gsiAnnotation)
 
+	annotations := map[string]string{
+		gsiAnnotationKey:         string(gsiAnnotationValue),
+		indicesHashAnnotationKey: getIndicesHash(context.Indices),
+	}
+	if templatesAnnotationValue := getTemplatesAnnotation(context.AppliedTemplates); templatesAnnotationValue != "" {
+		annotations[templatesAnnotationKey] = templatesAnnotationValue
+	}
+
 	labels := map[string]string{
 		"controller-uid": string(context.IndexSet.GetUID()),
 		"generation":     fmt.Sprintf("%d", context.IndexSet.GetGeneration()),
 		"bucketName":     context.IndexSet.Spec.BucketName,
 	}
 
-	if context.IndexSet.Spec.Cluster.ClusterRef != nil {
-		labels["clusterName"] = context.IndexSet.Spec.Cluster.ClusterRef.Name
+	if cluster := context.IndexSet.Spec.Cluster; cluster != nil && cluster.ClusterRef != nil {
+		labels["clusterName"] = cluster.ClusterRef.Name
+	}
+	if context.ClusterName != "" {
+		labels[indexSetClusterLabel] = context.ClusterName
 	}
 	if context.IsDeleting {
 		labels["deletion"] = "true"
@@ -329,11 +480,9 @@ func (context *CouchbaseIndexSetReconcileContext) createJob() error {
 	job := batchv1.Job{
 		ObjectMeta: v1.ObjectMeta{
 			Namespace:    context.IndexSet.GetNamespace(),
-			GenerateName: fmt.Sprintf("%s-", context.IndexSet.Name),
+			GenerateName: fmt.Sprintf("%s-", context.getResourceName()),
 			Labels:       labels,
-			Annotations: map[string]string{
-				gsiAnnotationKey: string(gsiAnnotationValue),
-			},
+			Annotations:  annotations,
 		},
 		Spec: batchv1.JobSpec{
 			ActiveDeadlineSeconds: activeDeadlineSeconds,
@@ -398,6 +547,10 @@ func (context *CouchbaseIndexSetReconcileContext) createJob() error {
 									LocalObjectReference: corev1.LocalObjectReference{
 										Name: context.IndexSet.Status.ConfigMapName,
 									},
+									// Only mount the spec, the ConfigMap may also contain DDL for reference
+									Items: []corev1.KeyToPath{
+										{Key: "indices.yaml", Path: "indices.yaml"},
+									},
 								},
 							},
 						},
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+// Expands indices which target multiple scopes or collections, using the current collection manifest of the bucket,
+// and finds any collections which don't exist yet. Must be called after getConnectionInfo.
+func (context *CouchbaseIndexSetReconcileContext) reconcileKeyspaces() (bool, ctrl.Result, error) {
+	context.MissingKeyspaces = nil
+
+	hasFanOut := false
+	hasCollections := false
+	for _, gsi := range context.Indices {
+		if cbim.IsFanOutIndex(gsi) {
+			hasFanOut = true
+		} else if identifier := cbim.GetIndexIdentifier(gsi); identifier.ScopeName != "_default" || identifier.CollectionName != "_default" {
+			hasCollections = true
+		}
+	}
+
+	if !hasFanOut && !hasCollections {
+		return true, ctrl.Result{}, nil
+	}
+
+	if context.IsDeleting {
+		// The expanded indices are tracked in the status and will all be dropped, so skip the manifest
+		indices := []v1beta1.GlobalSecondaryIndex{}
+		for _, gsi := range context.Indices {
+			if !cbim.IsFanOutIndex(gsi) {
+				indices = append(indices, gsi)
+			}
+		}
+
+		context.Indices = indices
+		return true, ctrl.Result{}, nil
+	}
+
+	collections, err := context.getCollections()
+	if err != nil {
+		if !hasFanOut {
+			// The manifest is only needed to wait for missing collections, leave any errors to the sync job
+			context.Error(err, "Unable to read collections")
+			return true, ctrl.Result{}, nil
+		}
+
+		setNotReady(&context.IndexSet, IndexSetReadyReasonCouchbaseError, "Unable to read collections: "+err.Error())
+		return false, ctrl.Result{}, err
+	}
+
+	if hasFanOut {
+		indices, err := cbim.ExpandKeyspaces(context.Indices, collections)
+		if err != nil {
+			setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec, err.Error())
+			return false, ctrl.Result{}, nil
+		}
+
+		context.Indices = indices
+	}
+
+	context.MissingKeyspaces = cbim.GetMissingKeyspaces(context.Indices, collections)
+
+	return true, ctrl.Result{}, nil
+}
+
+// Gets the existing collections in the bucket as a map of scope names to collection names
+func (context *CouchbaseIndexSetReconcileContext) getCollections() (map[string][]string, error) {
+	client, err := context.getRestClient()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := client.GetCollectionManifest(context.Ctx, context.IndexSet.Spec.BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := map[string][]string{}
+	for _, scope := range manifest.Scopes {
+		collectionNames := make([]string, len(scope.Collections))
+		for i, collection := range scope.Collections {
+			collectionNames[i] = collection.Name
+		}
+
+		collections[scope.Name] = collectionNames
+	}
+
+	return collections, nil
+}
+
+// Maps a CouchbaseCollectionSet to reconcile requests for the index sets on the same bucket or which depend on it
+func (r *CouchbaseIndexSetReconciler) findIndexSetsForCollectionSet(collectionSet client.Object) []reconcile.Request {
+	bucketName := collectionSet.(*v1beta1.CouchbaseCollectionSet).Spec.BucketName
+
+	indexSets := v1beta1.CouchbaseIndexSetList{}
+	if err := r.List(context.Background(), &indexSets, client.InNamespace(collectionSet.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, indexSet := range indexSets.Items {
+		if indexSet.Spec.BucketName == bucketName || dependsOn(&indexSet, dependencyKindCollectionSet, collectionSet.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: indexSet.Namespace,
+					Name:      indexSet.Name,
+				},
+			})
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+// Explains the expected query plans once after each successful sync, reporting any query whose plan doesn't use the
+// expected index on the QueryPlansValid condition
+func (context *CouchbaseIndexSetReconcileContext) reconcileQueryPlans(syncTime time.Time) {
+	expectedPlans := context.IndexSet.Spec.ExpectedPlans
+	if len(expectedPlans) == 0 {
+		context.IndexSet.Status.LastQueryPlanCheck = nil
+		removeQueryPlansCondition(&context.IndexSet)
+		return
+	}
+
+	if lastCheck := context.IndexSet.Status.LastQueryPlanCheck; lastCheck != nil && !lastCheck.Time.Before(syncTime) {
+		// Already checked after this sync
+		return
+	}
+
+	context.IndexSet.Status.LastQueryPlanCheck = &metav1.Time{Time: time.Now()}
+
+	client, err := context.getRestClient()
+	if err != nil {
+		setQueryPlansValidStatus(&context.IndexSet, false, IndexSetQueryPlansReasonExplainFailed, "Unable to explain queries: "+err.Error())
+		return
+	}
+
+	var (
+		notUsed []string
+		failed  []string
+	)
+	for _, expectedPlan := range expectedPlans {
+		name := expectedPlan.Name
+		if name == "" {
+			name = expectedPlan.Statement
+		}
+
+		indexes, err := client.Explain(context.Ctx, expectedPlan.Statement)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", name, err.Error()))
+			continue
+		}
+
+		used := make([]cbim.GlobalSecondaryIndexIdentifier, len(indexes))
+		for i, key := range indexes {
+			used[i] = cbim.GlobalSecondaryIndexIdentifier{
+				ScopeName:      key.ScopeName,
+				CollectionName: key.CollectionName,
+				Name:           key.Name,
+			}
+		}
+
+		if !cbim.PlanUsesIndex(used, expectedPlan.MustUseIndex) {
+			usedNames := make([]string, len(used))
+			for i, identifier := range used {
+				usedNames[i] = identifier.ToString()
+			}
+			if len(usedNames) == 0 {
+				usedNames = []string{"no index"}
+			}
+
+			notUsed = append(notUsed, fmt.Sprintf("%s uses %s instead of %s", name, strings.Join(usedNames, ", "), expectedPlan.MustUseIndex))
+		}
+	}
+
+	if len(notUsed) == 0 && len(failed) == 0 {
+		setQueryPlansValidStatus(&context.IndexSet, true, IndexSetQueryPlansReasonPlansValid, "All queries use the expected indices")
+		return
+	}
+
+	wasValid := !meta.IsStatusConditionFalse(context.IndexSet.Status.Conditions, ConditionTypeQueryPlansValid)
+
+	var messages []string
+	reason := IndexSetQueryPlansReasonExplainFailed
+	if len(notUsed) > 0 {
+		reason = IndexSetQueryPlansReasonIndexNotUsed
+		messages = append(messages, "Queries don't use the expected index: "+strings.Join(notUsed, "; "))
+	}
+	if len(failed) > 0 {
+		messages = append(messages, "Unable to explain queries: "+strings.Join(failed, "; "))
+	}
+
+	message := strings.Join(messages, "; ")
+	setQueryPlansValidStatus(&context.IndexSet, false, reason, message)
+
+	if wasValid {
+		context.V(1).Info("Query plans are invalid", "message", message)
+		context.Reconciler.Event(&context.IndexSet, "Warning", "QueryPlansInvalid", message)
+	}
+}
+
+func setQueryPlansValidStatus(indexSet *v1beta1.CouchbaseIndexSet, status bool, reason IndexSetQueryPlansReason, message string) {
+	meta.SetStatusCondition(&indexSet.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeQueryPlansValid,
+		Status:             getStatus(status),
+		Message:            message,
+		Reason:             string(reason),
+		ObservedGeneration: indexSet.Generation,
+	})
+}
+
+func removeQueryPlansCondition(indexSet *v1beta1.CouchbaseIndexSet) {
+	meta.RemoveStatusCondition(&indexSet.Status.Conditions, ConditionTypeQueryPlansValid)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/schedule"
+)
+
+// Annotation on an index set which bypasses its sync windows while set to "true", for emergencies
+const syncWindowOverrideAnnotationKey = "couchbase.btburnett.com/sync-window-override"
+
+// Determines if a sync may start now based on the sync windows for the kind of sync, returning false with the result
+// to requeue at the next window if it must wait. Periodic resyncs which must wait leave the Ready condition unchanged.
+func (context *CouchbaseIndexSetReconcileContext) reconcileSyncWindows(isResync bool) (bool, ctrl.Result) {
+	context.IndexSet.Status.NextSyncWindow = nil
+
+	syncWindows := context.IndexSet.Spec.SyncWindows
+	if syncWindows == nil {
+		return true, ctrl.Result{}
+	}
+
+	var (
+		windowSpecs []v1beta1.CouchbaseIndexSetSyncWindow
+		description string
+	)
+	switch {
+	case context.IsDeleting:
+		windowSpecs = syncWindows.Deletion
+		description = "Index cleanup is"
+	case isResync:
+		windowSpecs = syncWindows.Resyncs
+		description = "Resync is"
+	default:
+		windowSpecs = syncWindows.Changes
+		description = "Changes are"
+	}
+
+	if len(windowSpecs) == 0 {
+		return true, ctrl.Result{}
+	}
+
+	if context.IndexSet.GetAnnotations()[syncWindowOverrideAnnotationKey] == "true" {
+		context.V(1).Info("Sync windows bypassed by override annotation")
+		return true, ctrl.Result{}
+	}
+
+	windows, err := schedule.ParseWindows(windowSpecs, syncWindows.TimeZone)
+	if err != nil {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonInvalidSpec, "Invalid sync windows: "+err.Error())
+		return false, ctrl.Result{}
+	}
+
+	now := time.Now()
+	nextWindow, ok := windows.NextAllowed(now)
+	if ok && !nextWindow.After(now) {
+		return true, ctrl.Result{}
+	}
+
+	if !ok {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonPending, description+" pending, no sync window is scheduled")
+		return false, ctrl.Result{}
+	}
+
+	context.V(1).Info("Waiting for sync window", "nextSyncWindow", nextWindow)
+	context.IndexSet.Status.NextSyncWindow = &v1.Time{Time: nextWindow}
+
+	if !isResync || context.IsDeleting {
+		setNotReady(&context.IndexSet, IndexSetReadyReasonPending,
+			fmt.Sprintf("%s pending until the next sync window at %s", description, nextWindow.UTC().Format(time.RFC3339)))
+	}
+
+	return false, ctrl.Result{RequeueAfter: nextWindow.Sub(now)}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
+)
+
+const templatesAnnotationKey = "couchbase.btburnett.com/templates"
+
+// Loads the index templates instantiated by the index set and expands them into the full list of indices
+func (context *CouchbaseIndexSetReconcileContext) reconcileTemplates() (bool, ctrl.Result, error) {
+	context.Indices = context.IndexSet.Spec.Indices
+	context.AppliedTemplates = nil
+
+	if len(context.IndexSet.Spec.Templates) == 0 {
+		return true, ctrl.Result{}, nil
+	}
+
+	templates := map[string]*v1beta1.CouchbaseIndexTemplate{}
+	for _, instance := range context.IndexSet.Spec.Templates {
+		if _, ok := templates[instance.TemplateName]; ok {
+			continue
+		}
+
+		templateName := types.NamespacedName{
+			Namespace: context.IndexSet.Namespace,
+			Name:      instance.TemplateName,
+		}
+
+		template := v1beta1.CouchbaseIndexTemplate{}
+		if err := context.Reconciler.Get(context.Ctx, templateName, &template); err != nil {
+			if !apierrors.IsNotFound(err) {
+				setNotReady(&context.IndexSet, IndexSetReadyReasonTemplateError, err.Error())
+				return false, ctrl.Result{}, err
+			}
+
+			// Missing templates are reported when expanding
+			continue
+		}
+
+		templates[instance.TemplateName] = &template
+	}
+
+	indices, err := cbim.ExpandIndices(&context.IndexSet, templates)
+	if err != nil {
+		if context.IsDeleting {
+			// Cleanup doesn't require the template indices, they will all be dropped
+			return true, ctrl.Result{}, nil
+		}
+
+		setNotReady(&context.IndexSet, IndexSetReadyReasonTemplateError, err.Error())
+		return false, ctrl.Result{}, nil
+	}
+
+	context.Indices = indices
+	context.AppliedTemplates = cbim.GetAppliedTemplates(&context.IndexSet, templates)
+
+	return true, ctrl.Result{}, nil
+}
+
+// Formats applied templates for the templates annotation on a Job, returns an empty string if there are none
+func getTemplatesAnnotation(appliedTemplates []v1beta1.AppliedIndexTemplate) string {
+	values := make([]string, len(appliedTemplates))
+	for i, v := range appliedTemplates {
+		values[i] = fmt.Sprintf("%s=%d", v.Name, v.Generation)
+	}
+
+	return strings.Join(values, ",")
+}
+
+func parseTemplatesAnnotation(templatesAnnotationValue string) []v1beta1.AppliedIndexTemplate {
+	result := []v1beta1.AppliedIndexTemplate{}
+
+	for _, value := range strings.Split(templatesAnnotationValue, ",") {
+		split := strings.Split(value, "=")
+		if len(split) != 2 {
+			continue
+		}
+
+		if generation, err := strconv.ParseInt(split[1], 10, 64); err == nil {
+			result = append(result, v1beta1.AppliedIndexTemplate{
+				Name:       split[0],
+				Generation: generation,
+			})
+		}
+	}
+
+	return result
+}
+
+// Maps a CouchbaseIndexTemplate to reconcile requests for the index sets which instantiate it
+func (r *CouchbaseIndexSetReconciler) findIndexSetsForTemplate(template client.Object) []reconcile.Request {
+	indexSets := v1beta1.CouchbaseIndexSetList{}
+	if err := r.List(context.Background(), &indexSets, client.InNamespace(template.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, indexSet := range indexSets.Items {
+		for _, instance := range indexSet.Spec.Templates {
+			if instance.TemplateName == template.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: indexSet.Namespace,
+						Name:      indexSet.Name,
+					},
+				})
+				break
+			}
+		}
+	}
+
+	return requests
+}
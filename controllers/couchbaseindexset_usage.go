@@ -0,0 +1,153 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	cbim "github.com/brantburnett/couchbase-index-operator/cbim"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+// Checks removed indices which are ready to be dropped for recent usage, tracking any which are in use in the status
+// so that their drop is held back. If usage can't be checked, every drop is held back until the next sync so that
+// other changes are still applied.
+func (context *CouchbaseIndexSetReconcileContext) reconcileIndexUsage() {
+	window := context.IndexSet.Spec.DropUsageWindowSeconds
+	if context.IsDeleting || window == nil {
+		context.IndexSet.Status.InUseIndices = nil
+		return
+	}
+
+	removed := cbim.GetRemovedIndexes(&context.IndexSet, context.Indices)
+	if len(removed.DeletingIndexes) == 0 {
+		context.IndexSet.Status.InUseIndices = nil
+		return
+	}
+
+	inUseIndexes := map[cbim.GlobalSecondaryIndexIdentifier]bool{}
+
+	stats, err := context.getIndexStats()
+	if err != nil {
+		context.Error(err, "unable to check index usage, holding back drops")
+
+		for _, identifier := range removed.DeletingIndexes {
+			inUseIndexes[identifier] = true
+		}
+
+		context.IndexSet.Status.InUseIndices = cbim.ToSortedStrings(inUseIndexes)
+		return
+	}
+
+	cutoff := time.Now().Add(-time.Duration(*window) * time.Second)
+
+	for _, identifier := range removed.DeletingIndexes {
+		indexStats, ok := stats[cbrest.IndexKey{
+			ScopeName:      identifier.ScopeName,
+			CollectionName: identifier.CollectionName,
+			Name:           identifier.Name,
+		}]
+
+		if ok && indexStats.LastScanTime.After(cutoff) {
+			context.V(1).Info("Index is in use", "index", identifier.ToString(), "lastScanTime", indexStats.LastScanTime)
+			inUseIndexes[identifier] = true
+		}
+	}
+
+	context.IndexSet.Status.InUseIndices = cbim.ToSortedStrings(inUseIndexes)
+}
+
+func (context *CouchbaseIndexSetReconcileContext) getIndexStats() (map[cbrest.IndexKey]*cbrest.IndexStats, error) {
+	client, err := context.getRestClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetIndexStats(context.Ctx, context.IndexSet.Spec.BucketName)
+}
+
+// Periodically collects usage statistics for the managed indices, reporting indices which haven't been scanned recently
+// on the Unused condition. Failures are logged and retried, but don't affect the sync.
+func (context *CouchbaseIndexSetReconcileContext) reconcileUsageTracking() ctrl.Result {
+	tracking := context.IndexSet.Spec.UsageTracking
+	if context.IsDeleting || tracking == nil {
+		context.IndexSet.Status.IndexUsage = nil
+		context.IndexSet.Status.LastUsageCollection = nil
+		removeUnusedCondition(&context.IndexSet)
+		return ctrl.Result{}
+	}
+
+	interval := time.Hour
+	if tracking.IntervalSeconds != nil {
+		interval = time.Duration(*tracking.IntervalSeconds) * time.Second
+	}
+
+	if lastCollection := context.IndexSet.Status.LastUsageCollection; lastCollection != nil {
+		if timeToNextCollection := getTimeToNextSync(lastCollection.Time, interval); timeToNextCollection > 0 {
+			return ctrl.Result{RequeueAfter: timeToNextCollection}
+		}
+	}
+
+	client, err := context.getRestClient()
+	if err != nil {
+		context.Error(err, "unable to collect index usage")
+		return ctrl.Result{RequeueAfter: time.Minute}
+	}
+
+	stats, err := client.GetIndexStats(context.Ctx, context.IndexSet.Spec.BucketName)
+	if err != nil {
+		context.Error(err, "unable to collect index usage")
+		return ctrl.Result{RequeueAfter: time.Minute}
+	}
+
+	now := time.Now()
+	previouslyUnused := toStringSet(cbim.GetUnusedIndexNames(context.IndexSet.Status.IndexUsage))
+
+	context.IndexSet.Status.IndexUsage = cbim.GetIndexUsage(&context.IndexSet, stats, now)
+	context.IndexSet.Status.LastUsageCollection = &metav1.Time{Time: now}
+
+	unused := cbim.GetUnusedIndexNames(context.IndexSet.Status.IndexUsage)
+	newlyUnused := []string{}
+	for _, name := range unused {
+		if !previouslyUnused[name] {
+			newlyUnused = append(newlyUnused, name)
+		}
+	}
+
+	if len(newlyUnused) > 0 {
+		context.V(1).Info("Indices are unused", "indices", newlyUnused)
+		context.Reconciler.Event(&context.IndexSet, "Normal", "IndicesUnused",
+			"Indices have not been scanned recently: "+strings.Join(newlyUnused, ", "))
+	}
+
+	setUnused(&context.IndexSet, unused)
+
+	return ctrl.Result{RequeueAfter: interval}
+}
+
+func toStringSet(values []string) map[string]bool {
+	result := make(map[string]bool, len(values))
+	for _, value := range values {
+		result[value] = true
+	}
+
+	return result
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+	"github.com/brantburnett/couchbase-index-operator/sqlpp"
+)
+
+const queryFunctionSetFinalizer string = "couchbase.btburnett.com/queryfunctions"
+
+// CouchbaseQueryFunctionSetReconciler reconciles a CouchbaseQueryFunctionSet object
+type CouchbaseQueryFunctionSetReconciler struct {
+	client.Client
+	record.EventRecorder
+	Scheme *runtime.Scheme
+}
+
+// Tracks the functions or libraries applied by a function set, keyed by "scope.name"
+type appliedQueryFunctions map[string]v1beta1.AppliedQueryFunction
+
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasequeryfunctionsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasequeryfunctionsets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasequeryfunctionsets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=couchbase.com,namespace=system,resources=couchbaseclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch
+
+// Reconcile applies SQL++ user-defined functions and JavaScript libraries through the Query service, dropping any
+// which are removed. Syncs follow the same timing as index set sync jobs.
+func (r *CouchbaseQueryFunctionSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	functionSet := v1beta1.CouchbaseQueryFunctionSet{}
+	if err := r.Get(ctx, req.NamespacedName, &functionSet); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	return reconcileDirectSync(ctx, r.Client, r.EventRecorder, &directSyncResource{
+		object:        &functionSet,
+		finalizer:     queryFunctionSetFinalizer,
+		conditions:    &functionSet.Status.Conditions,
+		lastSyncTime:  &functionSet.Status.LastSyncTime,
+		cluster:       functionSet.Spec.Cluster,
+		bucketName:    functionSet.Spec.BucketName,
+		paused:        functionSet.Spec.Paused != nil && *functionSet.Spec.Paused,
+		pausedMessage: "Function synchronization is paused",
+		inSyncMessage: "Functions are in sync",
+		sync: func(ctx context.Context, restClient *cbrest.Client, isDeleting bool) (string, error) {
+			return "", r.syncFunctions(ctx, restClient, &functionSet, isDeleting)
+		},
+	})
+}
+
+func (r *CouchbaseQueryFunctionSetReconciler) syncFunctions(ctx context.Context, restClient *cbrest.Client,
+	functionSet *v1beta1.CouchbaseQueryFunctionSet, isDeleting bool) error {
+
+	libraries := newAppliedQueryFunctions(functionSet.Status.Libraries)
+	functions := newAppliedQueryFunctions(functionSet.Status.Functions)
+
+	var err error
+	if isDeleting {
+		err = r.dropAll(ctx, restClient, functionSet, libraries, functions)
+	} else {
+		err = r.applyAll(ctx, restClient, functionSet, libraries, functions)
+	}
+
+	// Track progress even if some changes failed
+	functionSet.Status.Libraries = libraries.toSortedList()
+	functionSet.Status.Functions = functions.toSortedList()
+
+	return err
+}
+
+// Applies libraries before the functions which use them, then drops removed functions before removed libraries.
+// Unchanged functions and libraries are skipped unless they no longer exist.
+func (r *CouchbaseQueryFunctionSetReconciler) applyAll(ctx context.Context, restClient *cbrest.Client,
+	functionSet *v1beta1.CouchbaseQueryFunctionSet, libraries appliedQueryFunctions, functions appliedQueryFunctions) error {
+
+	bucketName := functionSet.Spec.BucketName
+
+	existingLibraries, existingFunctions, err := getExistingQueryFunctions(ctx, restClient, bucketName)
+	if err != nil {
+		return err
+	}
+
+	definedLibraries := map[string]bool{}
+	for _, scope := range functionSet.Spec.Scopes {
+		for _, library := range scope.Libraries {
+			key := scope.Name + "." + library.Name
+			hash := getDefinitionHash(library.Code)
+			definedLibraries[key] = true
+
+			if existingLibraries[key] && libraries[key].DefinitionHash == hash {
+				continue
+			}
+
+			if err := restClient.PutJavaScriptLibrary(ctx, bucketName, scope.Name, library.Name, library.Code); err != nil {
+				return fmt.Errorf("unable to apply library %s: %w", key, err)
+			}
+
+			r.Eventf(functionSet, "Normal", "LibraryApplied", "Applied library %s", key)
+			libraries[key] = v1beta1.AppliedQueryFunction{Name: key, DefinitionHash: hash}
+		}
+	}
+
+	definedFunctions := map[string]bool{}
+	for _, scope := range functionSet.Spec.Scopes {
+		for _, function := range scope.Functions {
+			key := scope.Name + "." + function.Name
+			definedFunctions[key] = true
+
+			statement, err := sqlpp.CreateFunctionStatement(bucketName, scope.Name, function)
+			if err != nil {
+				return fmt.Errorf("function %s: %w", key, err)
+			}
+
+			hash := getDefinitionHash(statement)
+			if existingFunctions[key] && functions[key].DefinitionHash == hash {
+				continue
+			}
+
+			if _, err := restClient.Query(ctx, statement); err != nil {
+				return fmt.Errorf("unable to apply function %s: %w", key, err)
+			}
+
+			r.Eventf(functionSet, "Normal", "FunctionApplied", "Applied function %s", key)
+			functions[key] = v1beta1.AppliedQueryFunction{Name: key, DefinitionHash: hash}
+		}
+	}
+
+	for key := range functions {
+		if !definedFunctions[key] {
+			if err := dropQueryFunction(ctx, restClient, bucketName, key, existingFunctions[key]); err != nil {
+				return err
+			}
+
+			r.Eventf(functionSet, "Normal", "FunctionDropped", "Dropped function %s", key)
+			delete(functions, key)
+		}
+	}
+
+	for key := range libraries {
+		if !definedLibraries[key] {
+			if err := dropJavaScriptLibrary(ctx, restClient, bucketName, key); err != nil {
+				return err
+			}
+
+			r.Eventf(functionSet, "Normal", "LibraryDropped", "Dropped library %s", key)
+			delete(libraries, key)
+		}
+	}
+
+	return nil
+}
+
+// Drops all functions and libraries managed by the function set
+func (r *CouchbaseQueryFunctionSetReconciler) dropAll(ctx context.Context, restClient *cbrest.Client,
+	functionSet *v1beta1.CouchbaseQueryFunctionSet, libraries appliedQueryFunctions, functions appliedQueryFunctions) error {
+
+	bucketName := functionSet.Spec.BucketName
+
+	_, existingFunctions, err := getExistingQueryFunctions(ctx, restClient, bucketName)
+	if err != nil {
+		return err
+	}
+
+	for key := range functions {
+		if err := dropQueryFunction(ctx, restClient, bucketName, key, existingFunctions[key]); err != nil {
+			return err
+		}
+
+		delete(functions, key)
+	}
+
+	for key := range libraries {
+		if err := dropJavaScriptLibrary(ctx, restClient, bucketName, key); err != nil {
+			return err
+		}
+
+		delete(libraries, key)
+	}
+
+	return nil
+}
+
+// Gets the keys of the libraries and functions which exist within the bucket, in "scope.name" format
+func getExistingQueryFunctions(ctx context.Context, restClient *cbrest.Client, bucketName string) (map[string]bool, map[string]bool, error) {
+	libraryList, err := restClient.GetJavaScriptLibraries(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get libraries: %w", err)
+	}
+
+	libraries := map[string]bool{}
+	for _, library := range libraryList {
+		if library.BucketName == bucketName {
+			libraries[library.ScopeName+"."+library.Name] = true
+		}
+	}
+
+	results, err := restClient.Query(ctx,
+		"SELECT RAW f.identity.`scope` || '.' || f.identity.name FROM system:functions AS f WHERE f.identity.`bucket` = $1",
+		bucketName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get functions: %w", err)
+	}
+
+	functions := map[string]bool{}
+	for _, result := range results {
+		var key string
+		if err := json.Unmarshal(result, &key); err == nil {
+			functions[key] = true
+		}
+	}
+
+	return libraries, functions, nil
+}
+
+func dropQueryFunction(ctx context.Context, restClient *cbrest.Client, bucketName string, key string, exists bool) error {
+	if !exists {
+		return nil
+	}
+
+	scopeName, name := splitQueryFunctionKey(key)
+	if _, err := restClient.Query(ctx, sqlpp.DropFunctionStatement(bucketName, scopeName, name)); err != nil {
+		return fmt.Errorf("unable to drop function %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func dropJavaScriptLibrary(ctx context.Context, restClient *cbrest.Client, bucketName string, key string) error {
+	scopeName, name := splitQueryFunctionKey(key)
+	if err := restClient.DeleteJavaScriptLibrary(ctx, bucketName, scopeName, name); err != nil {
+		return fmt.Errorf("unable to drop library %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func splitQueryFunctionKey(key string) (string, string) {
+	// Scope names may not contain periods
+	split := strings.SplitN(key, ".", 2)
+	if len(split) != 2 {
+		return "_default", key
+	}
+
+	return split[0], split[1]
+}
+
+func newAppliedQueryFunctions(list []v1beta1.AppliedQueryFunction) appliedQueryFunctions {
+	result := appliedQueryFunctions{}
+	for _, v := range list {
+		result[v.Name] = v
+	}
+
+	return result
+}
+
+func (applied appliedQueryFunctions) toSortedList() []v1beta1.AppliedQueryFunction {
+	result := make([]v1beta1.AppliedQueryFunction, 0, len(applied))
+	for _, v := range applied {
+		result = append(result, v)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CouchbaseQueryFunctionSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = mgr.GetEventRecorderFor("couchbase-query-function-set-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.CouchbaseQueryFunctionSet{}).
+		WithEventFilter(ignoreStatusChangePredicate()).
+		Complete(r)
+}
@@ -0,0 +1,638 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbfts"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+const (
+	searchIndexSetFinalizer string = "couchbase.btburnett.com/searchindices"
+
+	// Annotation on search index sync Jobs holding the hash of each definition applied by the Job
+	searchIndexHashesAnnotationKey string = "couchbase.btburnett.com/search-index-hashes"
+
+	SearchIndexSetReadyReasonIndexConflict IndexSetReadyReason = "IndexConflict"
+)
+
+// CouchbaseSearchIndexSetReconciler reconciles a CouchbaseSearchIndexSet object
+type CouchbaseSearchIndexSetReconciler struct {
+	client.Client
+	record.EventRecorder
+	Scheme *runtime.Scheme
+	// Image of the operator, which runs search index sync Jobs
+	OperatorImage string
+}
+
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasesearchindexsets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasesearchindexsets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=couchbase.btburnett.com,namespace=system,resources=couchbasesearchindexsets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=batch,namespace=system,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=couchbase.com,namespace=system,resources=couchbaseclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",namespace=system,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",namespace=system,resources=pods,verbs=get
+
+// Reconcile applies full text search index definitions using a Job which runs the operator image, following the
+// same model as index set sync Jobs. Failed Jobs are retried after a minute, and the indices are resynced every
+// 5 minutes to restore any indices which are dropped or changed externally.
+func (r *CouchbaseSearchIndexSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	indexSet := v1beta1.CouchbaseSearchIndexSet{}
+	if err := r.Get(ctx, req.NamespacedName, &indexSet); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	isDeleting := indexSet.GetDeletionTimestamp() != nil
+
+	log.FromContext(ctx).V(1).Info("reconciling")
+
+	if !controllerutil.ContainsFinalizer(&indexSet, searchIndexSetFinalizer) {
+		if isDeleting {
+			// We've already done the finalization, nothing to do
+			return ctrl.Result{}, nil
+		}
+
+		// Don't continue if we can't add the finalizer, we don't want to leave orphaned indices
+		controllerutil.AddFinalizer(&indexSet, searchIndexSetFinalizer)
+		if err := r.Update(ctx, &indexSet); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.reconcileJob(ctx, &indexSet, isDeleting)
+
+	// Apply any status updates, the index set is gone if the finalizer was removed
+	if statusErr := r.Status().Update(ctx, &indexSet); statusErr != nil && !apierrors.IsNotFound(statusErr) {
+		log.FromContext(ctx).Error(statusErr, "unable to update status")
+		return ctrl.Result{}, statusErr
+	}
+
+	return result, err
+}
+
+func (r *CouchbaseSearchIndexSetReconciler) reconcileJob(ctx context.Context, indexSet *v1beta1.CouchbaseSearchIndexSet,
+	isDeleting bool) (ctrl.Result, error) {
+
+	connection, message, err := getClusterConnection(ctx, r, indexSet.Namespace, indexSet.Spec.Cluster, indexSet.Spec.BucketName)
+	if err != nil || message != "" {
+		if isDeleting && err == nil {
+			// The cluster can't be found, etc. In this case during a delete we don't need to worry
+			// about cleanup, just remove the finalizer.
+			return ctrl.Result{}, r.removeFinalizer(ctx, indexSet)
+		}
+
+		if err != nil {
+			setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonCouchbaseError, err.Error())
+			return ctrl.Result{}, err
+		}
+
+		setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonCouchbaseError, message)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	definitions := []*cbrest.SearchIndexDefinition{}
+	if !isDeleting {
+		for _, index := range indexSet.Spec.Indices {
+			definition, err := cbfts.BuildIndexDefinition(indexSet.Spec.BucketName, index)
+			if err != nil {
+				// Malformed mappings or analysis won't succeed on retry
+				setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonInvalidSpec, fmt.Sprintf("index %s: %s", index.Name, err))
+				return ctrl.Result{}, nil
+			}
+
+			definitions = append(definitions, definition)
+		}
+
+		indexSet.Status.IndexCount = pointer.Int32Ptr(int32(len(definitions)))
+
+		if conflicts, err := r.getIndexConflicts(ctx, indexSet); err != nil {
+			return ctrl.Result{}, err
+		} else if len(conflicts) > 0 {
+			// The other index set isn't watched, so check again later
+			setSearchIndexSetNotReady(indexSet, SearchIndexSetReadyReasonIndexConflict,
+				"Indices are managed by another search index set: "+strings.Join(conflicts, ", "))
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+	}
+
+	job, err := r.getMostRecentJob(ctx, indexSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	isCurrentJob := job != nil && isCurrentSearchJob(indexSet, job, isDeleting)
+	if !isCurrentJob {
+		// The current job isn't for the latest spec, so we're out of date
+		setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonOutOfSync, "Indices are out of sync")
+	}
+
+	jobStatus := getJobStatus(job)
+	if jobStatus == jobRunning {
+		// The next status change of the job will trigger another reconcile
+		setSearchIndexSetSyncStatus(indexSet, true, IndexSetSyncingReasonSyncing, "Sync in progress")
+		return ctrl.Result{}, nil
+	}
+
+	setSearchIndexSetSyncStatus(indexSet, false, IndexSetSyncingReasonNotSyncing, "Sync not in progress")
+
+	if job != nil && indexSet.Status.LastSyncJobName != job.Name {
+		// Track the indices applied by the job, this is only done once since later changes are external
+		if err := r.updateTrackedIndices(ctx, indexSet, connection, job, jobStatus == jobCompleted); err != nil {
+			setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonCouchbaseError, "Unable to read search indices: "+err.Error())
+			return ctrl.Result{}, err
+		}
+	}
+
+	if isDeleting && len(indexSet.Status.Indices) == 0 {
+		log.FromContext(ctx).V(1).Info("Cleanup successful")
+		return ctrl.Result{}, r.removeFinalizer(ctx, indexSet)
+	}
+
+	if isCurrentJob {
+		switch jobStatus {
+		case jobFailed:
+			// Jobs don't include a failed time, so we do the best we can using the start time
+			if timeToNextSync := getTimeToNextSync(job.Status.StartTime.Time, time.Minute); timeToNextSync > 0 {
+				if getSearchIndexSetState(indexSet) != IndexSetReadyReasonJobFailed {
+					r.Event(indexSet, "Warning", "SyncFailed", "Sync failed")
+					setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonJobFailed, "Sync failed")
+				}
+
+				return ctrl.Result{RequeueAfter: timeToNextSync}, nil
+			}
+
+		case jobCompleted:
+			if timeToNextSync := getTimeToNextSync(job.Status.CompletionTime.Time, time.Minute*5); timeToNextSync > 0 && !isDeleting {
+				if getSearchIndexSetState(indexSet) != IndexSetReadyReasonInSync {
+					r.Event(indexSet, "Normal", "SyncComplete", "Sync completed")
+					setSearchIndexSetReadyStatus(indexSet, true, IndexSetReadyReasonInSync, "Indices are in sync")
+				}
+
+				return ctrl.Result{RequeueAfter: timeToNextSync}, nil
+			}
+		}
+	}
+
+	// We still run jobs if deleted so we can do cleanup and remove the finalizer
+	if !isDeleting && indexSet.Spec.Paused != nil && *indexSet.Spec.Paused {
+		setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonPaused, "Index synchronization is paused")
+		return ctrl.Result{}, nil
+	}
+
+	configMapName, err := r.reconcileConfigMap(ctx, indexSet, definitions)
+	if err != nil {
+		setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonConfigMapError, err.Error())
+		return ctrl.Result{}, err
+	}
+	indexSet.Status.ConfigMapName = configMapName
+
+	if err := r.createJob(ctx, indexSet, connection, definitions, isDeleting); err != nil {
+		setSearchIndexSetNotReady(indexSet, IndexSetReadyReasonJobFailed, "Failed to create sync job: "+err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if job != nil && isJobOld(job) {
+		_ = r.deleteJob(ctx, job)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func isCurrentSearchJob(indexSet *v1beta1.CouchbaseSearchIndexSet, job *batchv1.Job, isDeleting bool) bool {
+	return job.Labels["generation"] == fmt.Sprintf("%d", indexSet.Generation) &&
+		(job.Labels["deletion"] == "true") == isDeleting
+}
+
+// Finds the most recent sync Job for the index set, deleting any other Jobs which are old
+func (r *CouchbaseSearchIndexSetReconciler) getMostRecentJob(ctx context.Context, indexSet *v1beta1.CouchbaseSearchIndexSet) (*batchv1.Job, error) {
+	jobs := batchv1.JobList{}
+	if err := r.List(ctx, &jobs, client.InNamespace(indexSet.Namespace),
+		client.MatchingLabels{"controller-uid": string(indexSet.UID)}); err != nil {
+		return nil, err
+	}
+
+	var mostRecent *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if mostRecent == nil || job.CreationTimestamp.After(mostRecent.CreationTimestamp.Time) {
+			if mostRecent != nil && isJobOld(mostRecent) {
+				_ = r.deleteJob(ctx, mostRecent)
+			}
+
+			mostRecent = job
+		} else if isJobOld(job) {
+			_ = r.deleteJob(ctx, job)
+		}
+	}
+
+	return mostRecent, nil
+}
+
+func (r *CouchbaseSearchIndexSetReconciler) deleteJob(ctx context.Context, job *batchv1.Job) error {
+	log.FromContext(ctx).V(1).Info("Deleting job", "jobName", job.GetName())
+
+	deletePropagationBackground := v1.DeletePropagationBackground
+	if err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &deletePropagationBackground}); client.IgnoreNotFound(err) != nil {
+		log.FromContext(ctx).Error(err, "Failed to delete Job", "jobName", job.GetName())
+		return err
+	}
+
+	return nil
+}
+
+// Updates the tracked indices from a finished Job, using the UUIDs of the indices which now exist
+func (r *CouchbaseSearchIndexSetReconciler) updateTrackedIndices(ctx context.Context, indexSet *v1beta1.CouchbaseSearchIndexSet,
+	connection clusterConnection, job *batchv1.Job, succeeded bool) error {
+
+	hashes := map[string]string{}
+	if value := job.Annotations[searchIndexHashesAnnotationKey]; value != "" {
+		if err := json.Unmarshal([]byte(value), &hashes); err != nil {
+			return err
+		}
+	}
+
+	restClient, err := newRestClient(ctx, r, indexSet.Namespace, connection)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for name := range hashes {
+		names[name] = true
+	}
+	for _, v := range indexSet.Status.Indices {
+		names[v.Name] = true
+	}
+
+	uuids := map[string]string{}
+	for name := range names {
+		definition, err := restClient.GetSearchIndex(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if definition != nil {
+			uuids[name] = definition.UUID
+		}
+	}
+
+	indexSet.Status.Indices = cbfts.UpdateTrackedIndices(indexSet.Status.Indices, hashes, uuids, succeeded)
+	indexSet.Status.LastSyncJobName = job.Name
+
+	return nil
+}
+
+// Finds indices which are managed by another search index set on the same cluster, in "index (namespace/name)"
+// format. An index belongs to the index set which tracks it in its status, otherwise to the index set created first.
+func (r *CouchbaseSearchIndexSetReconciler) getIndexConflicts(ctx context.Context, indexSet *v1beta1.CouchbaseSearchIndexSet) ([]string, error) {
+	indexSets := v1beta1.CouchbaseSearchIndexSetList{}
+	if err := r.List(ctx, &indexSets); err != nil {
+		return nil, err
+	}
+
+	identity := getSearchConnectionIdentity(indexSet)
+	if identity == "" {
+		return nil, nil
+	}
+
+	tracked := map[string]bool{}
+	for _, v := range indexSet.Status.Indices {
+		tracked[v.Name] = true
+	}
+
+	conflicts := []string{}
+	for i := range indexSets.Items {
+		other := &indexSets.Items[i]
+		if other.UID == indexSet.UID || getSearchConnectionIdentity(other) != identity {
+			continue
+		}
+
+		otherDefined := map[string]bool{}
+		for _, index := range other.Spec.Indices {
+			otherDefined[index.Name] = true
+		}
+		otherTracked := map[string]bool{}
+		for _, v := range other.Status.Indices {
+			otherTracked[v.Name] = true
+		}
+
+		isOlder := other.CreationTimestamp.Before(&indexSet.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&indexSet.CreationTimestamp) &&
+				other.Namespace+"/"+other.Name < indexSet.Namespace+"/"+indexSet.Name)
+
+		for _, index := range indexSet.Spec.Indices {
+			if !otherDefined[index.Name] && !otherTracked[index.Name] {
+				continue
+			}
+
+			if (otherTracked[index.Name] && !tracked[index.Name]) || (otherTracked[index.Name] == tracked[index.Name] && isOlder) {
+				conflicts = append(conflicts, fmt.Sprintf("%s (%s/%s)", index.Name, other.Namespace, other.Name))
+			}
+		}
+	}
+
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
+// Identifies the Couchbase cluster targeted by a search index set, search index names are unique within the cluster
+func getSearchConnectionIdentity(indexSet *v1beta1.CouchbaseSearchIndexSet) string {
+	identity := getConnectionIdentity(&indexSet.Spec.Cluster)
+	if indexSet.Spec.Cluster.ClusterRef != nil {
+		// Cluster references are resolved within the namespace of the index set
+		identity = indexSet.Namespace + "/" + identity
+	}
+
+	return identity
+}
+
+func (r *CouchbaseSearchIndexSetReconciler) reconcileConfigMap(ctx context.Context, indexSet *v1beta1.CouchbaseSearchIndexSet,
+	definitions []*cbrest.SearchIndexDefinition) (string, error) {
+
+	name := types.NamespacedName{
+		Namespace: indexSet.Namespace,
+		Name:      indexSet.Name + "-searchspec",
+	}
+
+	var (
+		configMap corev1.ConfigMap
+		isNew     bool = false
+	)
+	if err := r.Get(ctx, name, &configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", err
+		}
+
+		// Config map doesn't exist, so make a new one
+		isNew = true
+		configMap = corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace: name.Namespace,
+				Name:      name.Name,
+			},
+		}
+	}
+
+	request, err := json.Marshal(cbfts.SyncRequest{
+		Definitions: definitions,
+		Tracked:     indexSet.Status.Indices,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	data := map[string]string{
+		"sync.json": string(request),
+	}
+
+	if reflect.DeepEqual(configMap.Data, data) {
+		// Already in sync, do nothing
+		return name.Name, nil
+	}
+
+	configMap.Data = data
+
+	controllerutil.SetControllerReference(indexSet, &configMap, r.Scheme)
+
+	if isNew {
+		log.FromContext(ctx).Info("Creating config map")
+		err = r.Create(ctx, &configMap)
+	} else {
+		log.FromContext(ctx).Info("Updating config map")
+		err = r.Update(ctx, &configMap)
+	}
+
+	return name.Name, err
+}
+
+func (r *CouchbaseSearchIndexSetReconciler) createJob(ctx context.Context, indexSet *v1beta1.CouchbaseSearchIndexSet,
+	connection clusterConnection, definitions []*cbrest.SearchIndexDefinition, isDeleting bool) error {
+
+	if r.OperatorImage == "" {
+		return fmt.Errorf("the operator image is unknown, set --operator-image")
+	}
+
+	log.FromContext(ctx).V(1).Info("Creating search index sync job")
+
+	hashes := map[string]string{}
+	for _, definition := range definitions {
+		hashes[definition.Name] = cbfts.GetDefinitionHash(definition)
+	}
+	hashesAnnotationValue, _ := json.Marshal(hashes)
+
+	labels := map[string]string{
+		"controller-uid": string(indexSet.GetUID()),
+		"generation":     fmt.Sprintf("%d", indexSet.GetGeneration()),
+		"bucketName":     indexSet.Spec.BucketName,
+	}
+	if cluster := indexSet.Spec.Cluster; cluster.ClusterRef != nil {
+		labels["clusterName"] = cluster.ClusterRef.Name
+	}
+	if isDeleting {
+		labels["deletion"] = "true"
+	}
+
+	activeDeadlineSeconds := indexSet.Spec.ActiveDeadlineSeconds
+	if activeDeadlineSeconds == nil {
+		activeDeadlineSeconds = pointer.Int64Ptr(600)
+	}
+
+	backoffLimit := indexSet.Spec.BackoffLimit
+	if backoffLimit == nil {
+		backoffLimit = pointer.Int32Ptr(2)
+	}
+
+	args := []string{
+		cbfts.SyncCommand,
+		"-c",
+		connection.ConnectionString,
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "searchspec",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: indexSet.Status.ConfigMapName,
+					},
+				},
+			},
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "searchspec",
+			ReadOnly:  true,
+			MountPath: "/spec",
+		},
+	}
+	if connection.CASecretName != "" {
+		args = append(args, "--ca-cert", "/ca/"+caCertificateKey)
+		volumes = append(volumes, corev1.Volume{
+			Name: "ca",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: connection.CASecretName,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "ca",
+			ReadOnly:  true,
+			MountPath: "/ca",
+		})
+	}
+	args = append(args, "/spec/sync.json")
+
+	job := batchv1.Job{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace:    indexSet.GetNamespace(),
+			GenerateName: fmt.Sprintf("%s-", indexSet.Name),
+			Labels:       labels,
+			Annotations: map[string]string{
+				searchIndexHashesAnnotationKey: string(hashesAnnotationValue),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds: activeDeadlineSeconds,
+			BackoffLimit:          backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    cbfts.SyncCommand,
+							Image:   r.OperatorImage,
+							Command: []string{"/manager"},
+							Args:    args,
+							Env: []corev1.EnvVar{
+								{
+									Name: "USERNAME",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											Key: "username",
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: connection.AdminSecretName,
+											},
+										},
+									},
+								},
+								{
+									Name: "PASSWORD",
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											Key: "password",
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: connection.AdminSecretName,
+											},
+										},
+									},
+								},
+							},
+							VolumeMounts: volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+
+	controllerutil.SetControllerReference(indexSet, &job, r.Scheme)
+
+	if err := r.Create(ctx, &job); err != nil {
+		return err
+	}
+
+	now := v1.Now()
+	indexSet.Status.LastSyncTime = &now
+
+	log.FromContext(ctx).Info("Created search index sync job", "jobName", job.GetName())
+	setSearchIndexSetSyncStatus(indexSet, true, IndexSetSyncingReasonSyncing, "Sync in progress")
+	r.Event(indexSet, "Normal", "SyncStarted", "Sync started")
+
+	return nil
+}
+
+func (r *CouchbaseSearchIndexSetReconciler) removeFinalizer(ctx context.Context, indexSet *v1beta1.CouchbaseSearchIndexSet) error {
+	controllerutil.RemoveFinalizer(indexSet, searchIndexSetFinalizer)
+
+	return r.Update(ctx, indexSet)
+}
+
+func setSearchIndexSetSyncStatus(indexSet *v1beta1.CouchbaseSearchIndexSet, status bool, reason IndexSetSyncingReason, message string) {
+	meta.SetStatusCondition(&indexSet.Status.Conditions, v1.Condition{
+		Type:               ConditionTypeSyncing,
+		Status:             getStatus(status),
+		Message:            message,
+		Reason:             string(reason),
+		ObservedGeneration: indexSet.Generation,
+	})
+}
+
+func setSearchIndexSetNotReady(indexSet *v1beta1.CouchbaseSearchIndexSet, reason IndexSetReadyReason, message string) {
+	setSearchIndexSetReadyStatus(indexSet, false, reason, message)
+}
+
+func setSearchIndexSetReadyStatus(indexSet *v1beta1.CouchbaseSearchIndexSet, status bool, reason IndexSetReadyReason, message string) {
+	meta.SetStatusCondition(&indexSet.Status.Conditions, v1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             getStatus(status),
+		Message:            message,
+		Reason:             string(reason),
+		ObservedGeneration: indexSet.Generation,
+	})
+}
+
+func getSearchIndexSetState(indexSet *v1beta1.CouchbaseSearchIndexSet) IndexSetReadyReason {
+	return getCurrentStateFromCondition(meta.FindStatusCondition(indexSet.Status.Conditions, ConditionTypeReady))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CouchbaseSearchIndexSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = mgr.GetEventRecorderFor("couchbase-search-index-set-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.CouchbaseSearchIndexSet{}).
+		Owns(&batchv1.Job{}).
+		WithEventFilter(ignoreStatusChangePredicate()).
+		Complete(r)
+}
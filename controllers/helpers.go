@@ -17,10 +17,80 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
+)
+
+// Ready condition reasons shared by resources which are synced directly by the operator
+const (
+	directSyncReasonUnknown        = "Unknown"
+	directSyncReasonInSync         = "InSync"
+	directSyncReasonPaused         = "Paused"
+	directSyncReasonTransitioning  = "Transitioning"
+	directSyncReasonSyncFailed     = "SyncFailed"
+	directSyncReasonCouchbaseError = "CouchbaseError"
+	directSyncReasonInvalidSpec    = "InvalidSpec"
 )
 
+// A resource which is synced directly by the operator through the Couchbase REST APIs, rather than by a Job
+type directSyncResource struct {
+	// The resource, which is updated with any status changes
+	object    client.Object
+	finalizer string
+
+	// Fields within the status of the resource
+	conditions   *[]v1.Condition
+	lastSyncTime **v1.Time
+
+	cluster    v1beta1.CouchbaseCluster
+	bucketName string
+
+	paused        bool
+	pausedMessage string
+	inSyncMessage string
+
+	// Interval for polling changes in progress, such as a deployment
+	pollInterval time.Duration
+
+	// Applies the resource, or cleans it up when deleting. Returns a message if a change is still in progress, which
+	// is polled until it completes. Failures are retried after a minute unless wrapped with newInvalidSpecError.
+	sync func(ctx context.Context, restClient *cbrest.Client, isDeleting bool) (string, error)
+}
+
+// An error in the spec of a resource synced directly by the operator, which isn't retried until the spec changes
+type invalidSpecError struct {
+	err error
+}
+
+func newInvalidSpecError(err error) error {
+	return &invalidSpecError{err: err}
+}
+
+func (e *invalidSpecError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidSpecError) Unwrap() error {
+	return e.err
+}
+
 type jobStatus int
 
 const (
@@ -58,3 +128,152 @@ func getJobStatus(job *batchv1.Job) jobStatus {
 		return jobRunning
 	}
 }
+
+// Determines how long to wait before syncing a resource which is synced directly by the operator, matching the timing
+// of index set sync jobs. Failed syncs are retried after a minute, and successful syncs are repeated every 5 minutes.
+// Changes to the resource are synced immediately.
+func getTimeToNextDirectSync(conditions []v1.Condition, generation int64, lastSyncTime *v1.Time) time.Duration {
+	readyCondition := meta.FindStatusCondition(conditions, ConditionTypeReady)
+	if readyCondition == nil || readyCondition.ObservedGeneration != generation || lastSyncTime == nil {
+		return 0
+	}
+
+	switch readyCondition.Reason {
+	case directSyncReasonSyncFailed:
+		return getTimeToNextSync(lastSyncTime.Time, time.Minute)
+	case directSyncReasonInSync:
+		return getTimeToNextSync(lastSyncTime.Time, time.Minute*5)
+	default:
+		return 0
+	}
+}
+
+// Reconciles a resource synced directly by the operator. A finalizer is added so that definitions are cleaned up
+// from Couchbase when the resource is deleted, and any status changes are applied once the sync is complete.
+func reconcileDirectSync(ctx context.Context, c client.Client, recorder record.EventRecorder, resource *directSyncResource) (ctrl.Result, error) {
+	isDeleting := resource.object.GetDeletionTimestamp() != nil
+
+	log.FromContext(ctx).V(1).Info("reconciling")
+
+	if !controllerutil.ContainsFinalizer(resource.object, resource.finalizer) {
+		if isDeleting {
+			// We've already done the finalization, nothing to do
+			return ctrl.Result{}, nil
+		}
+
+		// Don't continue if we can't add the finalizer, we don't want to leave orphaned definitions
+		controllerutil.AddFinalizer(resource.object, resource.finalizer)
+		if err := c.Update(ctx, resource.object); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := syncDirect(ctx, c, recorder, resource, isDeleting)
+
+	// Apply any status updates, the resource is gone if the finalizer was removed
+	if statusErr := c.Status().Update(ctx, resource.object); statusErr != nil && !apierrors.IsNotFound(statusErr) {
+		log.FromContext(ctx).Error(statusErr, "unable to update status")
+		return ctrl.Result{}, statusErr
+	}
+
+	return result, err
+}
+
+func syncDirect(ctx context.Context, c client.Client, recorder record.EventRecorder, resource *directSyncResource, isDeleting bool) (ctrl.Result, error) {
+	if !isDeleting {
+		// We still sync if deleted so we can do cleanup and remove the finalizer
+		if resource.paused {
+			setDirectSyncReadyStatus(resource, false, directSyncReasonPaused, resource.pausedMessage)
+			return ctrl.Result{}, nil
+		}
+
+		if timeToNextSync := getTimeToNextDirectSync(*resource.conditions, resource.object.GetGeneration(), *resource.lastSyncTime); timeToNextSync > 0 {
+			return ctrl.Result{RequeueAfter: timeToNextSync}, nil
+		}
+	}
+
+	restClient, message, err := getDirectRestClient(ctx, c, resource.object.GetNamespace(), resource.cluster, resource.bucketName)
+	if restClient == nil {
+		if isDeleting && err == nil {
+			// The cluster can't be found, etc. In this case during a delete we don't need to worry
+			// about cleanup, just remove the finalizer.
+			return ctrl.Result{}, removeDirectSyncFinalizer(ctx, c, resource)
+		}
+
+		if err != nil {
+			setDirectSyncReadyStatus(resource, false, directSyncReasonCouchbaseError, err.Error())
+			return ctrl.Result{}, err
+		}
+
+		setDirectSyncReadyStatus(resource, false, directSyncReasonCouchbaseError, message)
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	now := v1.Now()
+	*resource.lastSyncTime = &now
+
+	inProgress, err := resource.sync(ctx, restClient, isDeleting)
+	if err != nil {
+		var specErr *invalidSpecError
+		if errors.As(err, &specErr) {
+			// Retrying won't help, wait for the spec to change
+			setDirectSyncReadyStatus(resource, false, directSyncReasonInvalidSpec, err.Error())
+			return ctrl.Result{}, nil
+		}
+
+		if getDirectSyncState(resource) != directSyncReasonSyncFailed {
+			recorder.Event(resource.object, "Warning", "SyncFailed", "Sync failed")
+		}
+
+		setDirectSyncReadyStatus(resource, false, directSyncReasonSyncFailed, err.Error())
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	if inProgress != "" {
+		setDirectSyncReadyStatus(resource, false, directSyncReasonTransitioning, inProgress)
+		return ctrl.Result{RequeueAfter: resource.pollInterval}, nil
+	}
+
+	if isDeleting {
+		log.FromContext(ctx).V(1).Info("Cleanup successful")
+		return ctrl.Result{}, removeDirectSyncFinalizer(ctx, c, resource)
+	}
+
+	if getDirectSyncState(resource) != directSyncReasonInSync {
+		recorder.Event(resource.object, "Normal", "SyncComplete", "Sync completed")
+		setDirectSyncReadyStatus(resource, true, directSyncReasonInSync, resource.inSyncMessage)
+	}
+
+	return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+}
+
+func removeDirectSyncFinalizer(ctx context.Context, c client.Client, resource *directSyncResource) error {
+	controllerutil.RemoveFinalizer(resource.object, resource.finalizer)
+
+	return c.Update(ctx, resource.object)
+}
+
+func setDirectSyncReadyStatus(resource *directSyncResource, status bool, reason string, message string) {
+	meta.SetStatusCondition(resource.conditions, v1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             getStatus(status),
+		Message:            message,
+		Reason:             reason,
+		ObservedGeneration: resource.object.GetGeneration(),
+	})
+}
+
+func getDirectSyncState(resource *directSyncResource) string {
+	readyCondition := meta.FindStatusCondition(*resource.conditions, ConditionTypeReady)
+	if readyCondition == nil {
+		return directSyncReasonUnknown
+	}
+
+	return readyCondition.Reason
+}
+
+// Gets a hash of a definition applied directly by the operator, used to detect changes
+func getDefinitionHash(definition string) string {
+	hash := sha256.Sum256([]byte(definition))
+	return hex.EncodeToString(hash[:])
+}
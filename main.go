@@ -17,6 +17,8 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
 	"os"
 
@@ -24,14 +26,19 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+	"github.com/brantburnett/couchbase-index-operator/cbfts"
+	"github.com/brantburnett/couchbase-index-operator/cbrest"
 	"github.com/brantburnett/couchbase-index-operator/controllers"
 	couchbasev2 "github.com/brantburnett/couchbase-index-operator/couchbase/v2"
 	//+kubebuilder:scaffold:imports
@@ -75,12 +82,105 @@ func getCbimImageEnv() string {
 	return ns
 }
 
+// getOperatorImageEnv returns the image of the operator from the OPERATOR_IMAGE environment variable, or blank to read
+// the image from the operator's pod
+func getOperatorImageEnv() string {
+	return os.Getenv("OPERATOR_IMAGE")
+}
+
+// getPodOperatorImage reads the image of the manager container from the operator's pod, which is identified by the
+// POD_NAME and POD_NAMESPACE environment variables
+func getPodOperatorImage(reader client.Reader) (string, error) {
+	name := types.NamespacedName{
+		Namespace: os.Getenv("POD_NAMESPACE"),
+		Name:      os.Getenv("POD_NAME"),
+	}
+	if name.Namespace == "" || name.Name == "" {
+		return "", nil
+	}
+
+	pod := corev1.Pod{}
+	if err := reader.Get(context.Background(), name, &pod); err != nil {
+		return "", err
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "manager" {
+			return container.Image, nil
+		}
+	}
+
+	return "", nil
+}
+
+// runSearchIndexSync runs a search index sync Job, applying the request file using the USERNAME and PASSWORD
+// environment variables, and returns the exit code
+func runSearchIndexSync(args []string) int {
+	fs := flag.NewFlagSet(cbfts.SyncCommand, flag.ExitOnError)
+	connectionString := fs.String("c", "", "Couchbase connection string.")
+	caCertificatesPath := fs.String("ca-cert", "", "Path of PEM encoded CA certificates used to verify the cluster.")
+	opts := zap.Options{}
+	opts.BindFlags(fs)
+	_ = fs.Parse(args)
+
+	logger := zap.New(zap.UseFlagOptions(&opts))
+
+	if *connectionString == "" || fs.NArg() != 1 {
+		logger.Info("Usage: manager " + cbfts.SyncCommand + " -c <connection string> <request file>")
+		return 2
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		logger.Error(err, "unable to read request")
+		return 1
+	}
+
+	request := cbfts.SyncRequest{}
+	if err := json.Unmarshal(data, &request); err != nil {
+		logger.Error(err, "unable to parse request")
+		return 1
+	}
+
+	restClient, err := cbrest.NewClient(*connectionString, os.Getenv("USERNAME"), os.Getenv("PASSWORD"))
+	if err != nil {
+		logger.Error(err, "unable to create client")
+		return 1
+	}
+
+	if *caCertificatesPath != "" {
+		caCertificates, err := os.ReadFile(*caCertificatesPath)
+		if err != nil {
+			logger.Error(err, "unable to read CA certificates")
+			return 1
+		}
+
+		if err := restClient.SetCACertificates(caCertificates); err != nil {
+			logger.Error(err, "unable to use CA certificates")
+			return 1
+		}
+	}
+
+	if err := cbfts.Sync(context.Background(), restClient, request, logger); err != nil {
+		logger.Error(err, "sync failed")
+		return 1
+	}
+
+	logger.Info("Sync complete")
+	return 0
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == cbfts.SyncCommand {
+		os.Exit(runSearchIndexSync(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var cbimImage string
 	var watchNamespace string
+	var operatorImage string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -88,6 +188,8 @@ func main() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&cbimImage, "cbim-image", getCbimImageEnv(), "Image used for couchbase-index-manager.")
+	flag.StringVar(&operatorImage, "operator-image", getOperatorImageEnv(),
+		"Image of the operator, used for search index sync jobs. Defaults to the image of the operator's pod.")
 	flag.StringVar(&watchNamespace, "watch-namespace", getWatchNamespaceEnv(), "Namespace to monitor, or blank to monitor all namespaces.")
 	opts := zap.Options{
 		Development: true,
@@ -111,6 +213,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	if operatorImage == "" {
+		if operatorImage, err = getPodOperatorImage(mgr.GetAPIReader()); err != nil {
+			setupLog.Error(err, "unable to read operator image")
+		}
+	}
+
 	if err = (&controllers.CouchbaseIndexSetReconciler{
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
@@ -119,6 +227,42 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "CouchbaseIndexSet")
 		os.Exit(1)
 	}
+	if err = (&controllers.CouchbaseCollectionSetReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CouchbaseCollectionSet")
+		os.Exit(1)
+	}
+	if err = (&controllers.CouchbaseSearchIndexSetReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		OperatorImage: operatorImage,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CouchbaseSearchIndexSet")
+		os.Exit(1)
+	}
+	if err = (&controllers.CouchbaseQueryFunctionSetReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CouchbaseQueryFunctionSet")
+		os.Exit(1)
+	}
+	if err = (&controllers.CouchbaseEventingFunctionReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CouchbaseEventingFunction")
+		os.Exit(1)
+	}
+	if err = (&controllers.CouchbaseAnalyticsSetReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CouchbaseAnalyticsSet")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,217 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule evaluates cron schedules and the sync windows built from them.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedules are only searched this far ahead, which covers any valid schedule including February 29th
+const maxSearchYears = 5
+
+// Shorthand descriptors supported in place of the five fields
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var dayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// A parsed cron schedule in the standard five field "minute hour day-of-month month day-of-week" format
+type Cron struct {
+	minutes     []bool
+	hours       []bool
+	daysOfMonth []bool
+	months      []bool
+	daysOfWeek  []bool
+
+	// Days match if either field matches when both are restricted, following the behavior of cron
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+// Parses a cron expression with five fields, or a descriptor such as "@daily". Fields may contain "*", lists,
+// ranges, steps, and three letter month and day names.
+func ParseCron(expression string) (*Cron, error) {
+	expression = strings.TrimSpace(expression)
+	if descriptor, ok := descriptors[strings.ToLower(expression)]; ok {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q, expected 5 fields", expression)
+	}
+
+	cron := &Cron{
+		anyDayOfMonth: fields[2] == "*" || fields[2] == "?",
+		anyDayOfWeek:  fields[4] == "*" || fields[4] == "?",
+	}
+
+	var err error
+	if cron.minutes, err = parseCronField(fields[0], cronField{"minute", 0, 59, nil}); err != nil {
+		return nil, err
+	}
+	if cron.hours, err = parseCronField(fields[1], cronField{"hour", 0, 23, nil}); err != nil {
+		return nil, err
+	}
+	if cron.daysOfMonth, err = parseCronField(fields[2], cronField{"day of month", 1, 31, nil}); err != nil {
+		return nil, err
+	}
+	if cron.months, err = parseCronField(fields[3], cronField{"month", 1, 12, monthNames}); err != nil {
+		return nil, err
+	}
+	if cron.daysOfWeek, err = parseCronField(fields[4], cronField{"day of week", 0, 7, dayNames}); err != nil {
+		return nil, err
+	}
+
+	// Sunday may be written as 0 or 7
+	if cron.daysOfWeek[7] {
+		cron.daysOfWeek[0] = true
+	}
+
+	return cron, nil
+}
+
+func parseCronField(value string, field cronField) ([]bool, error) {
+	result := make([]bool, field.max+1)
+
+	for _, part := range strings.Split(value, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid %s step in %q", field.name, part)
+			}
+			rangePart = part[:i]
+		}
+
+		start, end := field.min, field.max
+		if rangePart != "*" && rangePart != "?" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+
+			var err error
+			if start, err = parseCronValue(bounds[0], field); err != nil {
+				return nil, err
+			}
+
+			if len(bounds) == 2 {
+				if end, err = parseCronValue(bounds[1], field); err != nil {
+					return nil, err
+				}
+			} else if step == 1 {
+				end = start
+			}
+
+			if end < start {
+				return nil, fmt.Errorf("invalid %s range %q", field.name, rangePart)
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			result[i] = true
+		}
+	}
+
+	return result, nil
+}
+
+func parseCronValue(value string, field cronField) (int, error) {
+	if number, ok := field.names[strings.ToUpper(value)]; ok {
+		return number, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < field.min || number > field.max {
+		return 0, fmt.Errorf("invalid %s %q, expected %d-%d", field.name, value, field.min, field.max)
+	}
+
+	return number, nil
+}
+
+// Returns the first time matching the schedule which is strictly after the given time, evaluated in the time's
+// location. Returns the zero time if there is no match within the search limit.
+func (cron *Cron) Next(after time.Time) time.Time {
+	location := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !cron.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if !cron.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+			continue
+		}
+
+		if !cron.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+			continue
+		}
+
+		if !cron.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (cron *Cron) matchesDay(t time.Time) bool {
+	dayOfMonth := cron.daysOfMonth[t.Day()]
+	dayOfWeek := cron.daysOfWeek[int(t.Weekday())]
+
+	switch {
+	case cron.anyDayOfMonth && cron.anyDayOfWeek:
+		return true
+	case cron.anyDayOfMonth:
+		return dayOfWeek
+	case cron.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cron", func() {
+
+	// Monday, June 7th 2021
+	from := time.Date(2021, 6, 7, 10, 30, 15, 0, time.UTC)
+
+	DescribeTable("should find the next match",
+		func(expression string, expected time.Time) {
+			// Arrange
+
+			cron, err := ParseCron(expression)
+			Expect(err).To(BeNil())
+
+			// Act
+
+			result := cron.Next(from)
+
+			// Assert
+
+			Expect(result).To(Equal(expected))
+		},
+		Entry("every minute", "* * * * *", time.Date(2021, 6, 7, 10, 31, 0, 0, time.UTC)),
+		Entry("later today", "0 22 * * *", time.Date(2021, 6, 7, 22, 0, 0, 0, time.UTC)),
+		Entry("tomorrow", "0 9 * * *", time.Date(2021, 6, 8, 9, 0, 0, 0, time.UTC)),
+		Entry("steps", "*/20 * * * *", time.Date(2021, 6, 7, 10, 40, 0, 0, time.UTC)),
+		Entry("lists and ranges", "15,45 1-3 * * *", time.Date(2021, 6, 8, 1, 15, 0, 0, time.UTC)),
+		Entry("day names", "0 2 * * SAT,SUN", time.Date(2021, 6, 12, 2, 0, 0, 0, time.UTC)),
+		Entry("sunday as 7", "0 2 * * 7", time.Date(2021, 6, 13, 2, 0, 0, 0, time.UTC)),
+		Entry("month names", "0 0 1 jan *", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("day of month or day of week", "0 0 10 * MON", time.Date(2021, 6, 10, 0, 0, 0, 0, time.UTC)),
+		Entry("leap days", "0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)),
+		Entry("descriptors", "@daily", time.Date(2021, 6, 8, 0, 0, 0, 0, time.UTC)),
+	)
+
+	It("should evaluate in the time's location", func() {
+		// Arrange
+
+		location := time.FixedZone("IST", 5*60*60+30*60)
+		cron, err := ParseCron("0 2 * * *")
+		Expect(err).To(BeNil())
+
+		// Act
+
+		result := cron.Next(from.In(location))
+
+		// Assert
+
+		Expect(result).To(Equal(time.Date(2021, 6, 8, 2, 0, 0, 0, location)))
+	})
+
+	It("should return zero for impossible schedules", func() {
+		// Arrange
+
+		cron, err := ParseCron("0 0 31 2 *")
+		Expect(err).To(BeNil())
+
+		// Act
+
+		result := cron.Next(from)
+
+		// Assert
+
+		Expect(result.IsZero()).To(BeTrue())
+	})
+
+	DescribeTable("should reject invalid expressions",
+		func(expression string) {
+			// Act
+
+			_, err := ParseCron(expression)
+
+			// Assert
+
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("too few fields", "0 0 * *"),
+		Entry("out of range", "60 * * * *"),
+		Entry("bad step", "*/0 * * * *"),
+		Entry("reversed range", "0 5-1 * * *"),
+		Entry("unknown name", "0 0 * * FUN"),
+	)
+})
@@ -0,0 +1,161 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Kinds of sync windows
+const (
+	WindowKindAllow = "Allow"
+	WindowKindDeny  = "Deny"
+)
+
+// Limits the number of window boundaries examined when searching for the next allowed time
+const maxWindowBoundaries = 1000
+
+// A recurring window of time during which syncs are allowed or denied
+type Window struct {
+	Kind     string
+	Schedule *Cron
+	Duration time.Duration
+}
+
+// A set of windows evaluated in a time zone. Syncs are allowed when no deny window is active and, if there are any
+// allow windows, at least one allow window is active. An empty set always allows syncs.
+type Windows struct {
+	Location *time.Location
+	Windows  []Window
+}
+
+// Parses sync windows from an index set spec, using UTC if the time zone is not specified
+func ParseWindows(windows []couchbasev1beta1.CouchbaseIndexSetSyncWindow, timeZone *string) (Windows, error) {
+	result := Windows{
+		Location: time.UTC,
+		Windows:  make([]Window, len(windows)),
+	}
+
+	if timeZone != nil && *timeZone != "" {
+		location, err := time.LoadLocation(*timeZone)
+		if err != nil {
+			return Windows{}, fmt.Errorf("invalid time zone %q: %w", *timeZone, err)
+		}
+
+		result.Location = location
+	}
+
+	for i, window := range windows {
+		cron, err := ParseCron(window.Schedule)
+		if err != nil {
+			return Windows{}, err
+		}
+
+		if window.DurationSeconds < 1 {
+			return Windows{}, fmt.Errorf("sync window %q must have a positive duration", window.Schedule)
+		}
+
+		kind := WindowKindAllow
+		if strings.EqualFold(window.Kind, WindowKindDeny) {
+			kind = WindowKindDeny
+		}
+
+		result.Windows[i] = Window{
+			Kind:     kind,
+			Schedule: cron,
+			Duration: time.Duration(window.DurationSeconds) * time.Second,
+		}
+	}
+
+	return result, nil
+}
+
+// Returns the end of the window occurrence active at the given time, or false if the window isn't active
+func (window Window) activeUntil(t time.Time) (time.Time, bool) {
+	// The most recent start at or before t is the first start after t - duration, if it exists
+	start := window.Schedule.Next(t.Add(-window.Duration).Add(-time.Minute))
+	for !start.IsZero() && !start.After(t) {
+		end := start.Add(window.Duration)
+		if end.After(t) {
+			return end, true
+		}
+
+		start = window.Schedule.Next(start)
+	}
+
+	return time.Time{}, false
+}
+
+// Returns true if syncs are allowed at the given time
+func (windows Windows) IsAllowed(t time.Time) bool {
+	t = t.In(windows.Location)
+
+	hasAllow, inAllow := false, false
+	for _, window := range windows.Windows {
+		_, active := window.activeUntil(t)
+
+		if window.Kind == WindowKindDeny {
+			if active {
+				return false
+			}
+		} else {
+			hasAllow = true
+			inAllow = inAllow || active
+		}
+	}
+
+	return !hasAllow || inAllow
+}
+
+// Returns the first time at or after the given time when syncs are allowed, or false if there is none
+func (windows Windows) NextAllowed(t time.Time) (time.Time, bool) {
+	t = t.In(windows.Location)
+
+	for i := 0; i < maxWindowBoundaries; i++ {
+		if windows.IsAllowed(t) {
+			return t, true
+		}
+
+		// Move to the next boundary which could change the result, either the end of an active deny window or the
+		// start of an allow window
+		next := time.Time{}
+		for _, window := range windows.Windows {
+			var candidate time.Time
+			if window.Kind == WindowKindDeny {
+				candidate, _ = window.activeUntil(t)
+			} else {
+				candidate = window.Schedule.Next(t)
+			}
+
+			if !candidate.IsZero() && candidate.After(t) && (next.IsZero() || candidate.Before(next)) {
+				next = candidate
+			}
+		}
+
+		if next.IsZero() {
+			return time.Time{}, false
+		}
+
+		t = next
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,112 @@
+package schedule
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("Windows", func() {
+
+	// Nightly allow window from 22:00 to 04:00 New York time, except on Saturday nights
+	parse := func() Windows {
+		windows, err := ParseWindows([]couchbasev1beta1.CouchbaseIndexSetSyncWindow{
+			{Kind: "Allow", Schedule: "0 22 * * *", DurationSeconds: 6 * 60 * 60},
+			{Kind: "Deny", Schedule: "0 20 * * SAT", DurationSeconds: 12 * 60 * 60},
+		}, pointer.StringPtr("America/New_York"))
+		Expect(err).To(BeNil())
+
+		return windows
+	}
+
+	It("should allow syncs within an allow window", func() {
+		// Arrange
+
+		windows := parse()
+
+		// Act
+
+		result := windows.IsAllowed(time.Date(2021, 6, 8, 3, 0, 0, 0, windows.Location))
+
+		// Assert
+
+		Expect(result).To(BeTrue())
+	})
+
+	It("should find the next allow window", func() {
+		// Arrange
+
+		windows := parse()
+
+		// Act
+
+		result, ok := windows.NextAllowed(time.Date(2021, 6, 8, 13, 0, 0, 0, windows.Location))
+
+		// Assert
+
+		Expect(ok).To(BeTrue())
+		Expect(result).To(BeTemporally("==", time.Date(2021, 6, 8, 22, 0, 0, 0, windows.Location)))
+	})
+
+	It("should skip windows blocked by a deny window", func() {
+		// Arrange
+
+		windows := parse()
+
+		// Act
+
+		result, ok := windows.NextAllowed(time.Date(2021, 6, 12, 13, 0, 0, 0, windows.Location))
+
+		// Assert
+
+		Expect(ok).To(BeTrue())
+		Expect(result).To(BeTemporally("==", time.Date(2021, 6, 13, 22, 0, 0, 0, windows.Location)))
+	})
+
+	It("should resume when a deny window ends", func() {
+		// Arrange
+
+		windows, err := ParseWindows([]couchbasev1beta1.CouchbaseIndexSetSyncWindow{
+			{Kind: "Deny", Schedule: "0 9 * * MON-FRI", DurationSeconds: 8 * 60 * 60},
+		}, nil)
+		Expect(err).To(BeNil())
+
+		// Act
+
+		result, ok := windows.NextAllowed(time.Date(2021, 6, 7, 12, 0, 0, 0, time.UTC))
+
+		// Assert
+
+		Expect(ok).To(BeTrue())
+		Expect(result).To(BeTemporally("==", time.Date(2021, 6, 7, 17, 0, 0, 0, time.UTC)))
+	})
+
+	It("should always allow syncs without windows", func() {
+		// Arrange
+
+		windows, err := ParseWindows(nil, nil)
+		Expect(err).To(BeNil())
+
+		// Act
+
+		result := windows.IsAllowed(time.Now())
+
+		// Assert
+
+		Expect(result).To(BeTrue())
+	})
+
+	It("should reject invalid time zones", func() {
+		// Act
+
+		_, err := ParseWindows(nil, pointer.StringPtr("Mars/Olympus_Mons"))
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlpp
+
+import (
+	"fmt"
+	"strings"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+const (
+	DefaultDataverseName = "Default"
+	LocalLinkName        = "Local"
+)
+
+// Returns the name of a dataverse, defaulting to "Default"
+func GetDataverseName(dataverseName *string) string {
+	if dataverseName == nil || *dataverseName == "" {
+		return DefaultDataverseName
+	}
+
+	return *dataverseName
+}
+
+// Returns the qualified name of an Analytics dataset or link
+func GetAnalyticsPath(dataverseName string, name string) string {
+	return EscapeIdentifier(dataverseName) + "." + EscapeIdentifier(name)
+}
+
+// Builds a CREATE DATAVERSE statement which succeeds if the dataverse already exists
+func CreateDataverseStatement(dataverseName string) string {
+	return fmt.Sprintf("CREATE DATAVERSE %s IF NOT EXISTS", EscapeIdentifier(dataverseName))
+}
+
+// Builds a CREATE DATASET statement for an Analytics dataset
+func CreateDatasetStatement(dataset couchbasev1beta1.AnalyticsDataset) string {
+	source := []string{dataset.Source.BucketName}
+	if dataset.Source.ScopeName != nil || dataset.Source.CollectionName != nil {
+		source = append(source, defaultedKeyspaceName(dataset.Source.ScopeName), defaultedKeyspaceName(dataset.Source.CollectionName))
+	}
+	for i, v := range source {
+		source[i] = EscapeIdentifier(v)
+	}
+
+	linkName := LocalLinkName
+	if dataset.LinkName != nil {
+		linkName = *dataset.LinkName
+	}
+
+	statement := fmt.Sprintf("CREATE DATASET %s ON %s AT %s",
+		GetAnalyticsPath(GetDataverseName(dataset.DataverseName), dataset.Name),
+		strings.Join(source, "."),
+		EscapeIdentifier(linkName))
+
+	if dataset.Where != nil && *dataset.Where != "" {
+		statement += " WHERE " + *dataset.Where
+	}
+
+	return statement
+}
+
+// Builds a DROP DATASET statement which succeeds if the dataset doesn't exist
+func DropDatasetStatement(dataverseName string, name string) string {
+	return fmt.Sprintf("DROP DATASET %s IF EXISTS", GetAnalyticsPath(dataverseName, name))
+}
+
+// Builds a CREATE INDEX statement for an Analytics index
+func CreateAnalyticsIndexStatement(index couchbasev1beta1.AnalyticsIndex) string {
+	fields := make([]string, len(index.Fields))
+	for i, field := range index.Fields {
+		path := strings.Split(field.Path, ".")
+		for j, v := range path {
+			path[j] = EscapeIdentifier(v)
+		}
+
+		fields[i] = strings.Join(path, ".") + ": " + field.Type
+	}
+
+	return fmt.Sprintf("CREATE INDEX %s ON %s(%s)",
+		EscapeIdentifier(index.Name),
+		GetAnalyticsPath(GetDataverseName(index.DataverseName), index.DatasetName),
+		strings.Join(fields, ", "))
+}
+
+// Builds a DROP INDEX statement for an Analytics index which succeeds if the index doesn't exist
+func DropAnalyticsIndexStatement(dataverseName string, datasetName string, name string) string {
+	return fmt.Sprintf("DROP INDEX %s.%s IF EXISTS", GetAnalyticsPath(dataverseName, datasetName), EscapeIdentifier(name))
+}
+
+// Builds a CONNECT LINK statement, which starts ingestion for datasets on the link
+func ConnectLinkStatement(dataverseName string, linkName string) string {
+	return "CONNECT LINK " + GetAnalyticsPath(dataverseName, linkName)
+}
+
+// Builds a DISCONNECT LINK statement
+func DisconnectLinkStatement(dataverseName string, linkName string) string {
+	return "DISCONNECT LINK " + GetAnalyticsPath(dataverseName, linkName)
+}
+
+func defaultedKeyspaceName(name *string) string {
+	if name == nil || *name == "" {
+		return "_default"
+	}
+
+	return *name
+}
@@ -0,0 +1,92 @@
+package sqlpp
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("CreateDatasetStatement", func() {
+
+	It("should create datasets on buckets", func() {
+		// Arrange
+
+		dataset := couchbasev1beta1.AnalyticsDataset{
+			Name: "airlines",
+			Source: couchbasev1beta1.AnalyticsDatasetSource{
+				BucketName: "travel",
+			},
+		}
+
+		// Act
+
+		result := CreateDatasetStatement(dataset)
+
+		// Assert
+
+		Expect(result).To(Equal("CREATE DATASET `Default`.`airlines` ON `travel` AT `Local`"))
+	})
+
+	It("should create datasets on collections with a filter", func() {
+		// Arrange
+
+		dataset := couchbasev1beta1.AnalyticsDataset{
+			Name:          "airlines",
+			DataverseName: pointer.StringPtr("travel"),
+			LinkName:      pointer.StringPtr("remote"),
+			Source: couchbasev1beta1.AnalyticsDatasetSource{
+				BucketName:     "travel",
+				ScopeName:      pointer.StringPtr("inventory"),
+				CollectionName: pointer.StringPtr("airline"),
+			},
+			Where: pointer.StringPtr("country = \"United States\""),
+		}
+
+		// Act
+
+		result := CreateDatasetStatement(dataset)
+
+		// Assert
+
+		Expect(result).To(Equal("CREATE DATASET `travel`.`airlines` ON `travel`.`inventory`.`airline` AT `remote` WHERE country = \"United States\""))
+	})
+})
+
+var _ = Describe("CreateAnalyticsIndexStatement", func() {
+
+	It("should create indices with typed fields", func() {
+		// Arrange
+
+		index := couchbasev1beta1.AnalyticsIndex{
+			Name:        "byCountry",
+			DatasetName: "airlines",
+			Fields: []couchbasev1beta1.AnalyticsIndexField{
+				{Path: "country", Type: "string"},
+				{Path: "geo.alt", Type: "double"},
+			},
+		}
+
+		// Act
+
+		result := CreateAnalyticsIndexStatement(index)
+
+		// Assert
+
+		Expect(result).To(Equal("CREATE INDEX `byCountry` ON `Default`.`airlines`(`country`: string, `geo`.`alt`: double)"))
+	})
+})
+
+var _ = Describe("DropAnalyticsIndexStatement", func() {
+
+	It("should drop qualified indices", func() {
+		// Act
+
+		result := DropAnalyticsIndexStatement("Default", "airlines", "byCountry")
+
+		// Assert
+
+		Expect(result).To(Equal("DROP INDEX `Default`.`airlines`.`byCountry` IF EXISTS"))
+	})
+})
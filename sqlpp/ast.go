@@ -0,0 +1,281 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlpp
+
+import (
+	"strings"
+)
+
+// Parsed SQL++ expression. String returns the expression in the canonical form used by the indexer.
+type node interface {
+	String() string
+}
+
+type literalNode struct {
+	value string
+}
+
+func (n *literalNode) String() string {
+	return n.value
+}
+
+type identifierNode struct {
+	name string
+}
+
+func (n *identifierNode) String() string {
+	return EscapeIdentifier(n.name)
+}
+
+type fieldNode struct {
+	base node
+	name string
+}
+
+func (n *fieldNode) String() string {
+	return "(" + n.base.String() + "." + EscapeIdentifier(n.name) + ")"
+}
+
+type elementNode struct {
+	base    node
+	index   node
+	end     node
+	isSlice bool
+}
+
+func (n *elementNode) String() string {
+	index := n.index.String()
+	if n.isSlice {
+		index += ":"
+		if n.end != nil {
+			index += n.end.String()
+		}
+	}
+
+	return "(" + n.base.String() + "[" + index + "])"
+}
+
+type unaryNode struct {
+	operator string
+	operand  node
+}
+
+func (n *unaryNode) String() string {
+	return "(" + n.operator + n.operand.String() + ")"
+}
+
+type binaryNode struct {
+	operator string
+	left     node
+	right    node
+}
+
+func (n *binaryNode) String() string {
+	return "(" + n.left.String() + " " + n.operator + " " + n.right.String() + ")"
+}
+
+// AND or OR with any number of operands, matching the indexer which flattens chains of the same operator
+type logicalNode struct {
+	operator string
+	operands []node
+}
+
+func newLogicalNode(operator string, left node, right node) node {
+	result := &logicalNode{operator: operator}
+
+	for _, operand := range []node{left, right} {
+		if logical, ok := operand.(*logicalNode); ok && logical.operator == operator {
+			result.operands = append(result.operands, logical.operands...)
+		} else {
+			result.operands = append(result.operands, operand)
+		}
+	}
+
+	return result
+}
+
+func (n *logicalNode) String() string {
+	return "(" + joinNodes(n.operands, " "+n.operator+" ") + ")"
+}
+
+type isNode struct {
+	operand node
+	negated bool
+	value   string
+}
+
+func (n *isNode) String() string {
+	not := ""
+	if n.negated {
+		not = "not "
+	}
+
+	return "(" + n.operand.String() + " is " + not + n.value + ")"
+}
+
+type betweenNode struct {
+	operand node
+	low     node
+	high    node
+	negated bool
+}
+
+func (n *betweenNode) String() string {
+	operator := " between "
+	if n.negated {
+		operator = " not between "
+	}
+
+	return "(" + n.operand.String() + operator + n.low.String() + " and " + n.high.String() + ")"
+}
+
+type functionNode struct {
+	name      string
+	distinct  bool
+	arguments []node
+}
+
+func (n *functionNode) String() string {
+	distinct := ""
+	if n.distinct {
+		distinct = "distinct "
+	}
+
+	return n.name + "(" + distinct + joinNodes(n.arguments, ", ") + ")"
+}
+
+// Expression followed by index key modifiers, such as a key within FLATTEN_KEYS
+type suffixNode struct {
+	operand node
+	suffix  string
+}
+
+func (n *suffixNode) String() string {
+	return n.operand.String() + n.suffix
+}
+
+type arrayNode struct {
+	elements []node
+}
+
+func (n *arrayNode) String() string {
+	return "[" + joinNodes(n.elements, ", ") + "]"
+}
+
+type objectNode struct {
+	names  []node
+	values []node
+}
+
+func (n *objectNode) String() string {
+	pairs := make([]string, len(n.names))
+	for i := range n.names {
+		pairs[i] = n.names[i].String() + ": " + n.values[i].String()
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+type caseNode struct {
+	subject   node
+	whens     []node
+	thens     []node
+	otherwise node
+}
+
+func (n *caseNode) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("case")
+	if n.subject != nil {
+		sb.WriteString(" " + n.subject.String())
+	}
+
+	for i := range n.whens {
+		sb.WriteString(" when " + n.whens[i].String() + " then " + n.thens[i].String())
+	}
+
+	if n.otherwise != nil {
+		sb.WriteString(" else " + n.otherwise.String())
+	}
+
+	sb.WriteString(" end")
+	return sb.String()
+}
+
+type binding struct {
+	positionName string
+	name         string
+	operator     string
+	expression   node
+}
+
+func (b binding) String() string {
+	name := EscapeIdentifier(b.name)
+	if b.positionName != "" {
+		name = EscapeIdentifier(b.positionName) + " : " + name
+	}
+
+	return name + " " + b.operator + " " + b.expression.String()
+}
+
+// ARRAY, FIRST, and OBJECT collection operators, or ANY, EVERY, and ANY AND EVERY collection predicates. For
+// predicates, when holds the SATISFIES condition.
+type collectionNode struct {
+	operator string
+	mapping  []node
+	bindings []binding
+	when     node
+}
+
+func (n *collectionNode) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("(" + n.operator + " ")
+
+	if len(n.mapping) > 0 {
+		sb.WriteString(joinNodes(n.mapping, " : ") + " for ")
+	}
+
+	for i, b := range n.bindings {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(b.String())
+	}
+
+	if n.when != nil {
+		if len(n.mapping) > 0 {
+			sb.WriteString(" when ")
+		} else {
+			sb.WriteString(" satisfies ")
+		}
+		sb.WriteString(n.when.String())
+	}
+
+	sb.WriteString(" end)")
+	return sb.String()
+}
+
+func joinNodes(nodes []node, separator string) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = n.String()
+	}
+
+	return strings.Join(parts, separator)
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlpp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Parsed CREATE INDEX, CREATE PRIMARY INDEX, or CREATE VECTOR INDEX statement. Expressions are returned as written.
+type CreateIndexStatement struct {
+	Name string
+	// True for CREATE PRIMARY INDEX, the name is optional for primary indices
+	IsPrimary bool
+	// True for CREATE VECTOR INDEX, which creates a hyperscale vector index
+	IsVector       bool
+	BucketName     string
+	ScopeName      *string
+	CollectionName *string
+	// Index keys, including any INCLUDE MISSING, ASC, DESC, or VECTOR modifiers
+	IndexKey []string
+	// Properties included in a hyperscale vector index
+	Include              []string
+	PartitionStrategy    *string
+	PartitionExpressions []string
+	Condition            *string
+	// Options from the WITH clause
+	With map[string]interface{}
+}
+
+// Splits a script into statements delimited by semicolons. Semicolons within strings, identifiers, or comments are
+// ignored, and empty statements are removed.
+func SplitStatements(script string) []string {
+	runes := []rune(script)
+	statements := []string{}
+
+	start := 0
+	addStatement := func(end int) {
+		if statement := strings.TrimSpace(string(runes[start:end])); statement != "" && !isOnlyComments(statement) {
+			statements = append(statements, statement)
+		}
+		start = end + 1
+	}
+
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case r == '`' || r == '"' || r == '\'':
+			i = skipQuoted(runes, i)
+		case isCommentStart(runes, i):
+			i = skipComment(runes, i)
+		case r == ';':
+			addStatement(i)
+			i++
+		default:
+			i++
+		}
+	}
+
+	if start < len(runes) {
+		addStatement(len(runes))
+	}
+
+	return statements
+}
+
+func isOnlyComments(statement string) bool {
+	tokens, err := tokenize(statement)
+	return err == nil && len(tokens) == 1
+}
+
+// Returns the leading keywords of a statement, such as "CREATE INDEX" or "BUILD INDEX", upper case
+func GetStatementType(statement string) string {
+	tokens, err := tokenize(statement)
+	if err != nil {
+		return ""
+	}
+
+	keywords := []string{}
+	for _, t := range tokens {
+		if t.kind != tokenIdentifier || len(keywords) == 3 {
+			break
+		}
+
+		keyword := strings.ToUpper(t.value)
+		keywords = append(keywords, keyword)
+		if keyword == "INDEX" {
+			break
+		}
+	}
+
+	return strings.Join(keywords, " ")
+}
+
+// Parses a CREATE INDEX, CREATE PRIMARY INDEX, or CREATE VECTOR INDEX statement
+func ParseCreateIndex(statement string) (*CreateIndexStatement, error) {
+	p, err := newParser(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CreateIndexStatement{}
+
+	if err := p.expectKeyword("CREATE"); err != nil {
+		return nil, err
+	}
+
+	switch t := p.peek(); {
+	case t.isKeyword("PRIMARY"):
+		p.next()
+		result.IsPrimary = true
+	case t.isKeyword("VECTOR"):
+		p.next()
+		result.IsVector = true
+	}
+
+	if err := p.expectKeyword("INDEX"); err != nil {
+		return nil, err
+	}
+
+	if err := p.parseIfNotExists(); err != nil {
+		return nil, err
+	}
+
+	// The name is optional for primary indices
+	if !result.IsPrimary || !p.peek().isKeyword("ON") {
+		if result.Name, err = p.parseIdentifier(); err != nil {
+			return nil, err
+		}
+
+		if err := p.parseIfNotExists(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectKeyword("ON"); err != nil {
+		return nil, err
+	}
+
+	if err := p.parseKeyspace(result); err != nil {
+		return nil, err
+	}
+
+	if !result.IsPrimary {
+		if err := p.expectPunctuation("("); err != nil {
+			return nil, err
+		}
+
+		if result.IndexKey, err = p.parseSourceList(true); err != nil {
+			return nil, err
+		}
+	}
+
+	for p.peek().kind != tokenEOF {
+		switch t := p.next(); {
+		case t.isKeyword("INCLUDE") && !result.IsPrimary:
+			if err := p.expectPunctuation("("); err != nil {
+				return nil, err
+			}
+
+			if result.Include, err = p.parseSourceList(false); err != nil {
+				return nil, err
+			}
+
+		case t.isKeyword("PARTITION") && !result.IsPrimary:
+			if err := p.expectKeyword("BY"); err != nil {
+				return nil, err
+			}
+
+			strategy, expressions, err := p.parsePartition()
+			if err != nil {
+				return nil, err
+			}
+
+			result.PartitionStrategy = &strategy
+			result.PartitionExpressions = expressions
+
+		case t.isKeyword("WHERE") && !result.IsPrimary:
+			start := p.peek().pos
+			if _, err := p.parseExpression(precedenceNone); err != nil {
+				return nil, err
+			}
+
+			condition := p.sourceFrom(start)
+			result.Condition = &condition
+
+		case t.isKeyword("USING"):
+			using := p.next()
+			if !using.isKeyword("GSI") {
+				return nil, fmt.Errorf("only GSI indexes are supported, found USING %s", using.value)
+			}
+
+		case t.isKeyword("WITH"):
+			if result.With, err = p.parseWith(); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, p.unexpected(t)
+		}
+	}
+
+	return result, nil
+}
+
+// Parses a partition expression, such as "HASH(`type`, `id`)" from the partition column of system:indexes, returning
+// the strategy in lower case and the partition expressions as written
+func ParsePartition(partition string) (string, []string, error) {
+	p, err := newParser(partition)
+	if err != nil {
+		return "", nil, err
+	}
+
+	strategy, expressions, err := p.parsePartition()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return strategy, expressions, p.expectEOF()
+}
+
+func (p *parser) parseIfNotExists() error {
+	if !p.peek().isKeyword("IF") {
+		return nil
+	}
+
+	p.next()
+	if err := p.expectKeyword("NOT"); err != nil {
+		return err
+	}
+
+	return p.expectKeyword("EXISTS")
+}
+
+// Parses a keyspace path, such as "default:`travel-sample`.inventory.airline". The namespace is optional.
+func (p *parser) parseKeyspace(result *CreateIndexStatement) error {
+	path := []string{}
+
+	for {
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return err
+		}
+
+		if len(path) == 0 && p.peek().isPunctuation(":") {
+			// Skip the namespace
+			p.next()
+			continue
+		}
+
+		path = append(path, name)
+
+		if !p.peek().isPunctuation(".") {
+			break
+		}
+		p.next()
+	}
+
+	switch len(path) {
+	case 1:
+		result.BucketName = path[0]
+	case 3:
+		result.BucketName = path[0]
+		result.ScopeName = &path[1]
+		result.CollectionName = &path[2]
+	default:
+		return fmt.Errorf("invalid keyspace %s, expected a bucket or bucket.scope.collection", strings.Join(path, "."))
+	}
+
+	return nil
+}
+
+// Parses a comma delimited list of expressions followed by a closing parenthesis, returning each expression as written
+func (p *parser) parseSourceList(allowKeyModifiers bool) ([]string, error) {
+	result := []string{}
+
+	for {
+		start := p.peek().pos
+		if _, err := p.parseExpression(precedenceNone); err != nil {
+			return nil, err
+		}
+
+		if allowKeyModifiers {
+			p.parseKeyModifiers(true)
+		}
+
+		result = append(result, p.sourceFrom(start))
+
+		switch t := p.next(); {
+		case t.isPunctuation(","):
+			continue
+		case t.isPunctuation(")"):
+			return result, nil
+		default:
+			return nil, p.unexpected(t)
+		}
+	}
+}
+
+func (p *parser) parsePartition() (string, []string, error) {
+	strategy := p.next()
+	if !strategy.isKeyword("HASH") {
+		return "", nil, fmt.Errorf("unsupported partition strategy %s", strategy)
+	}
+
+	if err := p.expectPunctuation("("); err != nil {
+		return "", nil, err
+	}
+
+	expressions, err := p.parseSourceList(false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return strings.ToLower(strategy.value), expressions, nil
+}
+
+// Parses the object following WITH, which must contain only constant values
+func (p *parser) parseWith() (map[string]interface{}, error) {
+	if t := p.next(); !t.isPunctuation("{") {
+		return nil, fmt.Errorf("expected an object following WITH, found %s at position %d", t, t.pos)
+	}
+
+	object, err := p.parseObject()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rendered literals, arrays, and objects are valid JSON
+	with := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(object.String()), &with); err != nil {
+		return nil, errors.New("WITH may only contain constant values")
+	}
+
+	return with, nil
+}
@@ -0,0 +1,168 @@
+package sqlpp
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("SplitStatements", func() {
+
+	It("should split on semicolons outside strings and comments", func() {
+		// Act
+
+		result := SplitStatements(`
+-- Airline indices; created by hand
+CREATE INDEX a ON b(c) WHERE d = ';';
+/* block; comment */
+CREATE INDEX e ON f(g);
+;
+BUILD INDEX ON b(a)`)
+
+		// Assert
+
+		Expect(result).To(Equal([]string{
+			"-- Airline indices; created by hand\nCREATE INDEX a ON b(c) WHERE d = ';'",
+			"/* block; comment */\nCREATE INDEX e ON f(g)",
+			"BUILD INDEX ON b(a)",
+		}))
+	})
+})
+
+var _ = Describe("GetStatementType", func() {
+
+	It("should return leading keywords", func() {
+		// Act
+
+		result := []string{
+			GetStatementType("-- comment\ncreate primary index ON b"),
+			GetStatementType("BUILD INDEX ON b(a)"),
+			GetStatementType("CREATE SCOPE b.s"),
+		}
+
+		// Assert
+
+		Expect(result).To(Equal([]string{"CREATE PRIMARY INDEX", "BUILD INDEX", "CREATE SCOPE B"}))
+	})
+})
+
+var _ = Describe("ParseCreateIndex", func() {
+
+	It("should parse a simple index", func() {
+		// Act
+
+		result, err := ParseCreateIndex("CREATE INDEX idx_type ON `travel-sample`(type)")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(&CreateIndexStatement{
+			Name:       "idx_type",
+			BucketName: "travel-sample",
+			IndexKey:   []string{"type"},
+		}))
+	})
+
+	It("should parse every clause", func() {
+		// Act
+
+		result, err := ParseCreateIndex(`CREATE INDEX IF NOT EXISTS ` + "`def_route`" + ` ON default:` + "`travel-sample`" + `.inventory.route(
+				airline INCLUDE MISSING,
+				DISTINCT ARRAY s.flight FOR s IN schedule END,
+				distance DESC)
+			PARTITION BY HASH(META().id, airline)
+			WHERE type = 'route' AND (stops = 0)
+			USING GSI
+			WITH {"num_replica": 1, "defer_build": true, "retain_deleted_xattr": false, "nodes": ["a:8091"]}`)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(&CreateIndexStatement{
+			Name:                 "def_route",
+			BucketName:           "travel-sample",
+			ScopeName:            pointer.StringPtr("inventory"),
+			CollectionName:       pointer.StringPtr("route"),
+			IndexKey:             []string{"airline INCLUDE MISSING", "DISTINCT ARRAY s.flight FOR s IN schedule END", "distance DESC"},
+			PartitionStrategy:    pointer.StringPtr("hash"),
+			PartitionExpressions: []string{"META().id", "airline"},
+			Condition:            pointer.StringPtr("type = 'route' AND (stops = 0)"),
+			With: map[string]interface{}{
+				"num_replica":          float64(1),
+				"defer_build":          true,
+				"retain_deleted_xattr": false,
+				"nodes":                []interface{}{"a:8091"},
+			},
+		}))
+	})
+
+	It("should parse primary indices", func() {
+		// Act
+
+		result, err := ParseCreateIndex("CREATE PRIMARY INDEX ON `travel-sample` USING GSI")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(&CreateIndexStatement{
+			IsPrimary:  true,
+			BucketName: "travel-sample",
+		}))
+	})
+
+	It("should parse vector indices", func() {
+		// Act
+
+		result, err := ParseCreateIndex("CREATE VECTOR INDEX hotel_vec ON hotels(embedding VECTOR) INCLUDE (city, rating) " +
+			"WITH {'dimension': 384, 'similarity': 'COSINE', 'description': 'IVF,SQ8'}")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal(&CreateIndexStatement{
+			Name:       "hotel_vec",
+			IsVector:   true,
+			BucketName: "hotels",
+			IndexKey:   []string{"embedding VECTOR"},
+			Include:    []string{"city", "rating"},
+			With: map[string]interface{}{
+				"dimension":   float64(384),
+				"similarity":  "COSINE",
+				"description": "IVF,SQ8",
+			},
+		}))
+	})
+
+	DescribeTable("should reject invalid statements",
+		func(statement string) {
+			// Act
+
+			_, err := ParseCreateIndex(statement)
+
+			// Assert
+
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("other statements", "BUILD INDEX ON b(a)"),
+		Entry("non-GSI indices", "CREATE INDEX a ON b(c) USING VIEW"),
+		Entry("partial keyspaces", "CREATE INDEX a ON b.c(d)"),
+		Entry("unknown clauses", "CREATE INDEX a ON b(c) ORDER BY d"),
+		Entry("non-constant options", "CREATE INDEX a ON b(c) WITH {\"nodes\": node}"),
+	)
+})
+
+var _ = Describe("ParsePartition", func() {
+
+	It("should parse system:indexes partitions", func() {
+		// Act
+
+		strategy, expressions, err := ParsePartition("HASH((meta().`id`), `type`)")
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(strategy).To(Equal("hash"))
+		Expect(expressions).To(Equal([]string{"(meta().`id`)", "`type`"}))
+	})
+})
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlpp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+// Keywords which may be followed by a parenthesized expression without being a function call
+var keywords = map[string]bool{
+	"AND": true, "ANY": true, "ARRAY": true, "AS": true, "BETWEEN": true, "BY": true, "CASE": true,
+	"DISTINCT": true, "ELSE": true, "END": true, "EVERY": true, "EXISTS": true, "FIRST": true, "FOR": true,
+	"FROM": true, "IN": true, "INCLUDE": true, "IS": true, "LIKE": true, "NOT": true, "OBJECT": true, "ON": true,
+	"OR": true, "SATISFIES": true, "SELECT": true, "SOME": true, "THEN": true, "WHEN": true, "WHERE": true,
+	"WITHIN": true,
+}
+
+// Escapes an identifier using backticks
+func EscapeIdentifier(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+// Returns the fully qualified name of a scoped user-defined function
+func GetFunctionPath(bucketName string, scopeName string, name string) string {
+	return fmt.Sprintf("default:%s.%s.%s", EscapeIdentifier(bucketName), EscapeIdentifier(scopeName), EscapeIdentifier(name))
+}
+
+// Builds a CREATE OR REPLACE FUNCTION statement for a user-defined function within a scope
+func CreateFunctionStatement(bucketName string, scopeName string, function couchbasev1beta1.QueryFunction) (string, error) {
+	parameters := make([]string, len(function.Parameters))
+	for i, parameter := range function.Parameters {
+		parameters[i] = EscapeIdentifier(parameter)
+	}
+
+	statement := fmt.Sprintf("CREATE OR REPLACE FUNCTION %s(%s)",
+		GetFunctionPath(bucketName, scopeName, function.Name), strings.Join(parameters, ", "))
+
+	if function.Expression != nil && function.JavaScript == nil {
+		return fmt.Sprintf("%s { %s }", statement, *function.Expression), nil
+	}
+
+	if function.JavaScript != nil && function.Expression == nil {
+		functionName := function.Name
+		if function.JavaScript.FunctionName != nil {
+			functionName = *function.JavaScript.FunctionName
+		}
+
+		// Scoped libraries are referenced by path
+		libraryPath := strings.Join([]string{bucketName, scopeName, function.JavaScript.LibraryName}, "/")
+
+		return fmt.Sprintf("%s LANGUAGE JAVASCRIPT AS %s AT %s", statement, quoteString(functionName), quoteString(libraryPath)), nil
+	}
+
+	return "", errors.New("function must have exactly one of expression or javaScript")
+}
+
+// Builds a DROP FUNCTION statement for a user-defined function within a scope
+func DropFunctionStatement(bucketName string, scopeName string, name string) string {
+	return "DROP FUNCTION " + GetFunctionPath(bucketName, scopeName, name)
+}
+
+func quoteString(value string) string {
+	// JSON string escaping is compatible with SQL++ double quoted strings
+	bytes, _ := json.Marshal(value)
+	return string(bytes)
+}
+
+// Finds the names of functions called by an expression. Qualified calls such as "scope.func()" return only the
+// function name. String literals are ignored, and expressions which can't be tokenized have no calls.
+func FindFunctionCalls(expression string) []string {
+	result := []string{}
+	found := map[string]bool{}
+
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return result
+	}
+
+	for i, t := range tokens[:len(tokens)-1] {
+		if t.kind != tokenIdentifier && t.kind != tokenQuotedIdentifier {
+			continue
+		}
+		if t.kind == tokenIdentifier && keywords[strings.ToUpper(t.value)] {
+			continue
+		}
+
+		if tokens[i+1].isPunctuation("(") && !found[t.value] {
+			found[t.value] = true
+			result = append(result, t.value)
+		}
+	}
+
+	return result
+}
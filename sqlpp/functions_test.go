@@ -0,0 +1,86 @@
+package sqlpp
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+
+	couchbasev1beta1 "github.com/brantburnett/couchbase-index-operator/api/v1beta1"
+)
+
+var _ = Describe("CreateFunctionStatement", func() {
+
+	It("should create inline functions", func() {
+		// Arrange
+
+		function := couchbasev1beta1.QueryFunction{
+			Name:       "celsius",
+			Parameters: []string{"degrees"},
+			Expression: pointer.StringPtr("(degrees - 32) * 5/9"),
+		}
+
+		// Act
+
+		result, err := CreateFunctionStatement("travel", "inventory", function)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal("CREATE OR REPLACE FUNCTION default:`travel`.`inventory`.`celsius`(`degrees`) { (degrees - 32) * 5/9 }"))
+	})
+
+	It("should create JavaScript functions", func() {
+		// Arrange
+
+		function := couchbasev1beta1.QueryFunction{
+			Name:       "distance",
+			Parameters: []string{"a", "b"},
+			JavaScript: &couchbasev1beta1.QueryFunctionJavaScript{
+				LibraryName:  "geo",
+				FunctionName: pointer.StringPtr("haversine"),
+			},
+		}
+
+		// Act
+
+		result, err := CreateFunctionStatement("travel", "inventory", function)
+
+		// Assert
+
+		Expect(err).To(BeNil())
+		Expect(result).To(Equal("CREATE OR REPLACE FUNCTION default:`travel`.`inventory`.`distance`(`a`, `b`) LANGUAGE JAVASCRIPT AS \"haversine\" AT \"travel/inventory/geo\""))
+	})
+
+	It("should require a body", func() {
+		// Act
+
+		_, err := CreateFunctionStatement("travel", "inventory", couchbasev1beta1.QueryFunction{Name: "empty"})
+
+		// Assert
+
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("FindFunctionCalls", func() {
+
+	It("should find calls", func() {
+		// Act
+
+		result := FindFunctionCalls("LOWER(name) = celsius (temp) AND inventory.`my func`(x) AND `type` = 'f(x)' AND fn2(\"g(\")")
+
+		// Assert
+
+		Expect(result).To(Equal([]string{"LOWER", "celsius", "my func", "fn2"}))
+	})
+
+	It("should ignore identifiers which are not calls", func() {
+		// Act
+
+		result := FindFunctionCalls("type = \"airline\" AND (id > 5)")
+
+		// Assert
+
+		Expect(result).To(BeEmpty())
+	})
+})
@@ -0,0 +1,247 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlpp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenParameter
+	tokenPunctuation
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// Returns true if the token is the given keyword, keywords are case insensitive
+func (t token) isKeyword(keyword string) bool {
+	return t.kind == tokenIdentifier && strings.EqualFold(t.value, keyword)
+}
+
+func (t token) isPunctuation(value string) bool {
+	return t.kind == tokenPunctuation && t.value == value
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+
+	return strconv.Quote(t.value)
+}
+
+// Multi-character operators, which must be matched before single characters
+var operators = []string{"==", "!=", "<>", "<=", ">=", "||"}
+
+const punctuation = "=<>+-*/%.,()[]{}:"
+
+// Splits a SQL++ expression into tokens. String and quoted identifier values are unescaped.
+func tokenize(expression string) ([]token, error) {
+	runes := []rune(expression)
+	tokens := []token{}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case isCommentStart(runes, i):
+			i = skipComment(runes, i)
+
+		case r == '`' || r == '"' || r == '\'':
+			end := skipQuoted(runes, i)
+			if end > len(runes) || end-i < 2 || runes[end-1] != r {
+				return nil, fmt.Errorf("unterminated quote at position %d", i)
+			}
+
+			kind := tokenString
+			if r == '`' {
+				kind = tokenQuotedIdentifier
+			}
+
+			tokens = append(tokens, token{kind: kind, value: unescapeQuoted(runes[i+1:end-1], r), pos: i})
+			i = end
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdentifier, value: string(runes[start:i]), pos: start})
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i++
+				if i < len(runes) && (runes[i] == '+' || runes[i] == '-') {
+					i++
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), pos: start})
+
+		case r == '$':
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenParameter, value: string(runes[start:i]), pos: start})
+
+		default:
+			matched := false
+			for _, operator := range operators {
+				if strings.HasPrefix(string(runes[i:]), operator) {
+					tokens = append(tokens, token{kind: tokenPunctuation, value: operator, pos: i})
+					i += len(operator)
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				if !strings.ContainsRune(punctuation, r) {
+					return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+				}
+
+				tokens = append(tokens, token{kind: tokenPunctuation, value: string(r), pos: i})
+				i++
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func isCommentStart(runes []rune, i int) bool {
+	return i+1 < len(runes) && ((runes[i] == '-' && runes[i+1] == '-') || (runes[i] == '/' && runes[i+1] == '*'))
+}
+
+// Returns the index following a line or block comment which starts at the given index
+func skipComment(runes []rune, start int) int {
+	if runes[start] == '-' {
+		for i := start; i < len(runes); i++ {
+			if runes[i] == '\n' {
+				return i + 1
+			}
+		}
+
+		return len(runes)
+	}
+
+	for i := start + 2; i+1 < len(runes); i++ {
+		if runes[i] == '*' && runes[i+1] == '/' {
+			return i + 2
+		}
+	}
+
+	return len(runes)
+}
+
+// Unescapes the contents of a quoted string or identifier. Quotes may be escaped by doubling them and, except for
+// identifiers, escape sequences start with a backslash.
+func unescapeQuoted(runes []rune, quote rune) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == quote && i+1 < len(runes) && runes[i+1] == quote {
+			sb.WriteRune(quote)
+			i++
+			continue
+		}
+
+		if r == '\\' && quote != '`' && i+1 < len(runes) {
+			i++
+			switch runes[i] {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			case 'b':
+				sb.WriteRune('\b')
+			case 'f':
+				sb.WriteRune('\f')
+			case 'u':
+				if i+4 < len(runes) {
+					if code, err := strconv.ParseUint(string(runes[i+1:i+5]), 16, 32); err == nil {
+						sb.WriteRune(rune(code))
+						i += 4
+						continue
+					}
+				}
+				sb.WriteRune('u')
+			default:
+				sb.WriteRune(runes[i])
+			}
+			continue
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// Returns the index following a quoted string or identifier which starts at the given index. Quotes are escaped
+// by doubling them or, except for identifiers, with a backslash.
+func skipQuoted(runes []rune, start int) int {
+	quote := runes[start]
+
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && quote != '`':
+			i++
+		case runes[i] == quote:
+			if i+1 < len(runes) && runes[i+1] == quote {
+				i++
+				continue
+			}
+
+			return i + 1
+		}
+	}
+
+	return len(runes)
+}
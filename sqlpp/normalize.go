@@ -0,0 +1,805 @@
+/*
+Copyright 2021 Brant Burnett
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sqlpp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Operator precedence, from lowest to highest
+const (
+	precedenceNone = iota
+	precedenceOr
+	precedenceAnd
+	precedenceNot
+	precedenceComparison
+	precedenceConcat
+	precedenceAdditive
+	precedenceMultiplicative
+	precedenceUnary
+	precedencePostfix
+)
+
+// Normalizes a SQL++ expression to the canonical form used by the indexer in system:indexes. Identifiers are
+// escaped with backticks, strings are double quoted, keywords and function names are lower case, and every operation
+// is wrapped in parentheses. Two expressions are equivalent to the indexer if their normalized forms are equal.
+func NormalizeExpression(expression string) (string, error) {
+	parser, err := newParser(expression)
+	if err != nil {
+		return "", err
+	}
+
+	node, err := parser.parseExpression(precedenceNone)
+	if err != nil {
+		return "", err
+	}
+
+	if err := parser.expectEOF(); err != nil {
+		return "", err
+	}
+
+	return node.String(), nil
+}
+
+// Normalizes an index key, which is an expression optionally followed by INCLUDE MISSING, ASC, DESC, or VECTOR.
+// ASC is the default and is omitted from the result.
+func NormalizeIndexKey(key string) (string, error) {
+	parser, err := newParser(key)
+	if err != nil {
+		return "", err
+	}
+
+	node, err := parser.parseExpression(precedenceNone)
+	if err != nil {
+		return "", err
+	}
+
+	result := node.String() + parser.parseKeyModifiers(true)
+
+	if err := parser.expectEOF(); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// Returns true if two expressions are equivalent once normalized. Expressions which can't be parsed are compared
+// as written.
+func ExpressionsEqual(a string, b string) bool {
+	normalizedA, errA := NormalizeExpression(a)
+	normalizedB, errB := NormalizeExpression(b)
+	if errA != nil || errB != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+
+	return normalizedA == normalizedB
+}
+
+// Returns true if two index keys are equivalent once normalized. Keys which can't be parsed are compared as written.
+func IndexKeysEqual(a string, b string) bool {
+	normalizedA, errA := NormalizeIndexKey(a)
+	normalizedB, errB := NormalizeIndexKey(b)
+	if errA != nil || errB != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+
+	return normalizedA == normalizedB
+}
+
+type parser struct {
+	source []rune
+	tokens []token
+	pos    int
+}
+
+func newParser(expression string) (*parser, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parser{source: []rune(expression), tokens: tokens}, nil
+}
+
+// Returns the source text from the given position up to the current token, as written
+func (p *parser) sourceFrom(start int) string {
+	return strings.TrimSpace(string(p.source[start:p.peek().pos]))
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) unexpected(t token) error {
+	return fmt.Errorf("unexpected %s at position %d", t, t.pos)
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if t := p.next(); !t.isKeyword(keyword) {
+		return fmt.Errorf("expected %s, found %s at position %d", keyword, t, t.pos)
+	}
+
+	return nil
+}
+
+func (p *parser) expectPunctuation(value string) error {
+	if t := p.next(); !t.isPunctuation(value) {
+		return fmt.Errorf("expected %q, found %s at position %d", value, t, t.pos)
+	}
+
+	return nil
+}
+
+func (p *parser) expectEOF() error {
+	if t := p.peek(); t.kind != tokenEOF {
+		return p.unexpected(t)
+	}
+
+	return nil
+}
+
+func (p *parser) parseIdentifier() (string, error) {
+	t := p.next()
+	if t.kind != tokenIdentifier && t.kind != tokenQuotedIdentifier {
+		return "", fmt.Errorf("expected identifier, found %s at position %d", t, t.pos)
+	}
+
+	return t.value, nil
+}
+
+// Parses optional INCLUDE MISSING, ASC, DESC, and VECTOR modifiers following an index key
+func (p *parser) parseKeyModifiers(allowVector bool) string {
+	result := ""
+
+	if p.peek().isKeyword("INCLUDE") && p.peekAt(1).isKeyword("MISSING") {
+		p.pos += 2
+		result += " INCLUDE MISSING"
+	}
+
+	switch t := p.peek(); {
+	case t.isKeyword("ASC"):
+		p.next()
+	case t.isKeyword("DESC"):
+		p.next()
+		result += " DESC"
+	}
+
+	if allowVector && p.peek().isKeyword("VECTOR") {
+		p.next()
+		result += " VECTOR"
+	}
+
+	return result
+}
+
+// Parses an expression using precedence climbing, consuming operators which bind tighter than minPrecedence
+func (p *parser) parseExpression(minPrecedence int) (node, error) {
+	left, err := p.parsePrefix()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		precedence := p.infixPrecedence()
+		if precedence <= minPrecedence {
+			return left, nil
+		}
+
+		left, err = p.parseInfix(left, precedence)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Returns the precedence of the infix or postfix operator at the current position, or precedenceNone
+func (p *parser) infixPrecedence() int {
+	t := p.peek()
+
+	switch t.kind {
+	case tokenPunctuation:
+		switch t.value {
+		case "=", "==", "!=", "<>", "<", "<=", ">", ">=":
+			return precedenceComparison
+		case "||":
+			return precedenceConcat
+		case "+", "-":
+			return precedenceAdditive
+		case "*", "/", "%":
+			return precedenceMultiplicative
+		case ".", "[":
+			return precedencePostfix
+		}
+
+	case tokenIdentifier:
+		switch strings.ToUpper(t.value) {
+		case "OR":
+			return precedenceOr
+		case "AND":
+			return precedenceAnd
+		case "IS", "LIKE", "BETWEEN", "IN", "WITHIN":
+			return precedenceComparison
+		case "NOT":
+			switch next := p.peekAt(1); {
+			case next.isKeyword("LIKE"), next.isKeyword("BETWEEN"), next.isKeyword("IN"), next.isKeyword("WITHIN"):
+				return precedenceComparison
+			}
+		}
+	}
+
+	return precedenceNone
+}
+
+func (p *parser) parseInfix(left node, precedence int) (node, error) {
+	t := p.next()
+
+	if t.kind == tokenPunctuation {
+		switch t.value {
+		case ".":
+			name, err := p.parseIdentifier()
+			if err != nil {
+				return nil, err
+			}
+
+			return &fieldNode{base: left, name: name}, nil
+
+		case "[":
+			index, err := p.parseExpression(precedenceNone)
+			if err != nil {
+				return nil, err
+			}
+
+			var end node
+			isSlice := false
+			if p.peek().isPunctuation(":") {
+				p.next()
+				isSlice = true
+
+				if !p.peek().isPunctuation("]") {
+					if end, err = p.parseExpression(precedenceNone); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			if err := p.expectPunctuation("]"); err != nil {
+				return nil, err
+			}
+
+			return &elementNode{base: left, index: index, end: end, isSlice: isSlice}, nil
+		}
+
+		right, err := p.parseExpression(precedence)
+		if err != nil {
+			return nil, err
+		}
+
+		operator := t.value
+		switch operator {
+		case "==":
+			operator = "="
+		case "<>":
+			operator = "!="
+		}
+
+		return &binaryNode{operator: operator, left: left, right: right}, nil
+	}
+
+	keyword := strings.ToLower(t.value)
+	switch keyword {
+	case "or", "and":
+		right, err := p.parseExpression(precedence)
+		if err != nil {
+			return nil, err
+		}
+
+		return newLogicalNode(keyword, left, right), nil
+
+	case "is":
+		negated := false
+		if p.peek().isKeyword("NOT") {
+			p.next()
+			negated = true
+		}
+
+		test := p.next()
+		if test.kind != tokenIdentifier {
+			return nil, p.unexpected(test)
+		}
+
+		switch value := strings.ToLower(test.value); value {
+		case "null", "missing", "valued", "known":
+			return &isNode{operand: left, negated: negated, value: value}, nil
+		default:
+			return nil, p.unexpected(test)
+		}
+
+	case "not":
+		operator := strings.ToLower(p.next().value)
+		return p.parseComparisonKeyword(left, operator, true, precedence)
+
+	default:
+		return p.parseComparisonKeyword(left, keyword, false, precedence)
+	}
+}
+
+// Parses the right hand side of LIKE, BETWEEN, IN, or WITHIN
+func (p *parser) parseComparisonKeyword(left node, operator string, negated bool, precedence int) (node, error) {
+	right, err := p.parseExpression(precedence)
+	if err != nil {
+		return nil, err
+	}
+
+	if operator == "between" {
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+
+		high, err := p.parseExpression(precedence)
+		if err != nil {
+			return nil, err
+		}
+
+		return &betweenNode{operand: left, low: right, high: high, negated: negated}, nil
+	}
+
+	if negated {
+		operator = "not " + operator
+	}
+
+	return &binaryNode{operator: operator, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrefix() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenString:
+		return &literalNode{value: quoteString(t.value)}, nil
+
+	case tokenNumber:
+		return &literalNode{value: normalizeNumber(t.value)}, nil
+
+	case tokenParameter:
+		return &literalNode{value: t.value}, nil
+
+	case tokenQuotedIdentifier:
+		return &identifierNode{name: t.value}, nil
+
+	case tokenPunctuation:
+		switch t.value {
+		case "(":
+			if p.peek().isKeyword("SELECT") {
+				return nil, fmt.Errorf("subqueries are not supported at position %d", p.peek().pos)
+			}
+
+			inner, err := p.parseExpression(precedenceNone)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expectPunctuation(")"); err != nil {
+				return nil, err
+			}
+
+			return inner, nil
+
+		case "-":
+			operand, err := p.parseExpression(precedenceUnary)
+			if err != nil {
+				return nil, err
+			}
+
+			if literal, ok := operand.(*literalNode); ok && isNumber(literal.value) {
+				return &literalNode{value: normalizeNumber("-" + literal.value)}, nil
+			}
+
+			return &unaryNode{operator: "-", operand: operand}, nil
+
+		case "[":
+			elements, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+
+			return &arrayNode{elements: elements}, nil
+
+		case "{":
+			return p.parseObject()
+		}
+
+	case tokenIdentifier:
+		return p.parseIdentifierPrefix(t)
+	}
+
+	return nil, p.unexpected(t)
+}
+
+func (p *parser) parseIdentifierPrefix(t token) (node, error) {
+	switch keyword := strings.ToLower(t.value); keyword {
+	case "true", "false", "null", "missing":
+		return &literalNode{value: keyword}, nil
+
+	case "not":
+		operand, err := p.parseExpression(precedenceNot)
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryNode{operator: "not ", operand: operand}, nil
+
+	case "exists":
+		operand, err := p.parseExpression(precedenceComparison)
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryNode{operator: "exists ", operand: operand}, nil
+
+	case "case":
+		return p.parseCase()
+
+	case "any", "some", "every":
+		if keyword == "any" && p.peek().isKeyword("AND") && p.peekAt(1).isKeyword("EVERY") {
+			p.pos += 2
+			keyword = "any and every"
+		} else if keyword == "some" {
+			keyword = "any"
+		}
+
+		return p.parseCollectionPredicate(keyword)
+
+	case "array", "first", "object":
+		if !p.endsOperand() {
+			return p.parseCollectionOperator(keyword)
+		}
+
+	case "distinct", "all":
+		// The indexer wraps the array in parentheses, such as "(distinct (array ... end))"
+		if p.peek().isKeyword("ARRAY") || (p.peek().isPunctuation("(") && p.peekAt(1).isKeyword("ARRAY")) {
+			inner, err := p.parsePrefix()
+			if err != nil {
+				return nil, err
+			}
+
+			if collection, ok := inner.(*collectionNode); !ok || collection.operator != "array" {
+				return nil, fmt.Errorf("expected ARRAY following %s at position %d", strings.ToUpper(keyword), t.pos)
+			}
+
+			return &unaryNode{operator: keyword + " ", operand: inner}, nil
+		}
+	}
+
+	if p.peek().isPunctuation("(") {
+		p.next()
+		return p.parseFunction(t.value)
+	}
+
+	return &identifierNode{name: t.value}, nil
+}
+
+// Returns true if the current token ends an operand, used to treat keywords such as FIRST as identifiers
+func (p *parser) endsOperand() bool {
+	t := p.peek()
+
+	return t.kind == tokenEOF || t.isPunctuation(")") || t.isPunctuation(",") || t.isPunctuation("]") ||
+		t.isPunctuation("}") || t.isPunctuation(":") || p.infixPrecedence() != precedenceNone
+}
+
+func (p *parser) parseFunction(name string) (node, error) {
+	name = strings.ToLower(name)
+	function := &functionNode{name: name}
+
+	if p.peek().isPunctuation(")") {
+		p.next()
+		return function, nil
+	}
+
+	if p.peek().isPunctuation("*") {
+		p.next()
+		function.arguments = []node{&literalNode{value: "*"}}
+		return function, p.expectPunctuation(")")
+	}
+
+	if p.peek().isKeyword("DISTINCT") {
+		p.next()
+		function.distinct = true
+	}
+
+	for {
+		argument, err := p.parseExpression(precedenceNone)
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "flatten_keys" {
+			// Each key within FLATTEN_KEYS may have its own modifiers
+			if modifiers := p.parseKeyModifiers(false); modifiers != "" {
+				argument = &suffixNode{operand: argument, suffix: modifiers}
+			}
+		}
+
+		function.arguments = append(function.arguments, argument)
+
+		if !p.peek().isPunctuation(",") {
+			break
+		}
+		p.next()
+	}
+
+	if err := p.expectPunctuation(")"); err != nil {
+		return nil, err
+	}
+
+	return function, nil
+}
+
+// Parses a comma delimited list of expressions followed by the closing punctuation
+func (p *parser) parseList(closing string) ([]node, error) {
+	elements := []node{}
+
+	if p.peek().isPunctuation(closing) {
+		p.next()
+		return elements, nil
+	}
+
+	for {
+		element, err := p.parseExpression(precedenceNone)
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, element)
+
+		if p.peek().isPunctuation(",") {
+			p.next()
+			continue
+		}
+
+		return elements, p.expectPunctuation(closing)
+	}
+}
+
+func (p *parser) parseObject() (node, error) {
+	object := &objectNode{}
+
+	if p.peek().isPunctuation("}") {
+		p.next()
+		return object, nil
+	}
+
+	for {
+		name, err := p.parsePrefix()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expectPunctuation(":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseExpression(precedenceNone)
+		if err != nil {
+			return nil, err
+		}
+
+		object.names = append(object.names, name)
+		object.values = append(object.values, value)
+
+		if p.peek().isPunctuation(",") {
+			p.next()
+			continue
+		}
+
+		return object, p.expectPunctuation("}")
+	}
+}
+
+func (p *parser) parseCase() (node, error) {
+	result := &caseNode{}
+
+	if !p.peek().isKeyword("WHEN") {
+		subject, err := p.parseExpression(precedenceNone)
+		if err != nil {
+			return nil, err
+		}
+
+		result.subject = subject
+	}
+
+	for p.peek().isKeyword("WHEN") {
+		p.next()
+
+		when, err := p.parseExpression(precedenceNone)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expectKeyword("THEN"); err != nil {
+			return nil, err
+		}
+
+		then, err := p.parseExpression(precedenceNone)
+		if err != nil {
+			return nil, err
+		}
+
+		result.whens = append(result.whens, when)
+		result.thens = append(result.thens, then)
+	}
+
+	if len(result.whens) == 0 {
+		return nil, p.unexpected(p.peek())
+	}
+
+	if p.peek().isKeyword("ELSE") {
+		p.next()
+
+		otherwise, err := p.parseExpression(precedenceNone)
+		if err != nil {
+			return nil, err
+		}
+
+		result.otherwise = otherwise
+	}
+
+	return result, p.expectKeyword("END")
+}
+
+// Parses the bindings for a collection operator or predicate, such as "v IN items, w WITHIN other"
+func (p *parser) parseBindings() ([]binding, error) {
+	bindings := []binding{}
+
+	for {
+		b := binding{}
+
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek().isPunctuation(":") {
+			p.next()
+
+			b.positionName = name
+			if name, err = p.parseIdentifier(); err != nil {
+				return nil, err
+			}
+		}
+		b.name = name
+
+		switch t := p.next(); {
+		case t.isKeyword("IN"):
+			b.operator = "in"
+		case t.isKeyword("WITHIN"):
+			b.operator = "within"
+		default:
+			return nil, p.unexpected(t)
+		}
+
+		if b.expression, err = p.parseExpression(precedenceNone); err != nil {
+			return nil, err
+		}
+
+		bindings = append(bindings, b)
+
+		if !p.peek().isPunctuation(",") {
+			return bindings, nil
+		}
+		p.next()
+	}
+}
+
+// Parses ARRAY, FIRST, or OBJECT collection operators
+func (p *parser) parseCollectionOperator(operator string) (node, error) {
+	result := &collectionNode{operator: operator}
+
+	mapping, err := p.parseExpression(precedenceNone)
+	if err != nil {
+		return nil, err
+	}
+
+	if operator == "object" {
+		if err := p.expectPunctuation(":"); err != nil {
+			return nil, err
+		}
+
+		value, err := p.parseExpression(precedenceNone)
+		if err != nil {
+			return nil, err
+		}
+
+		result.mapping = []node{mapping, value}
+	} else {
+		result.mapping = []node{mapping}
+	}
+
+	if err := p.expectKeyword("FOR"); err != nil {
+		return nil, err
+	}
+
+	if result.bindings, err = p.parseBindings(); err != nil {
+		return nil, err
+	}
+
+	if p.peek().isKeyword("WHEN") {
+		p.next()
+
+		if result.when, err = p.parseExpression(precedenceNone); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, p.expectKeyword("END")
+}
+
+// Parses ANY, EVERY, or ANY AND EVERY collection predicates
+func (p *parser) parseCollectionPredicate(operator string) (node, error) {
+	result := &collectionNode{operator: operator}
+
+	var err error
+	if result.bindings, err = p.parseBindings(); err != nil {
+		return nil, err
+	}
+
+	if err := p.expectKeyword("SATISFIES"); err != nil {
+		return nil, err
+	}
+
+	if result.when, err = p.parseExpression(precedenceNone); err != nil {
+		return nil, err
+	}
+
+	return result, p.expectKeyword("END")
+}
+
+func isNumber(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+// Normalizes numeric literals so that equivalent numbers, such as 1.50 and 1.5, are written the same way
+func normalizeNumber(value string) string {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return strconv.FormatInt(i, 10)
+	}
+
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	return value
+}
@@ -0,0 +1,157 @@
+package sqlpp
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NormalizeExpression", func() {
+
+	DescribeTable("should match system:indexes",
+		func(expression string, expected string) {
+			// Act
+
+			result, err := NormalizeExpression(expression)
+
+			// Assert
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(expected))
+
+			// Normalizing the indexer's output must not change it
+			result, err = NormalizeExpression(expected)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("identifier", "type", "`type`"),
+		Entry("escaped identifier", "`my``field`", "`my``field`"),
+		Entry("field", "geo.alt", "(`geo`.`alt`)"),
+		Entry("nested field", "a.b.`c`", "((`a`.`b`).`c`)"),
+		Entry("element", "schedule[0].day", "((`schedule`[0]).`day`)"),
+		Entry("slice", "items[1:]", "(`items`[1:])"),
+		Entry("meta", "META().id", "(meta().`id`)"),
+		Entry("meta with keyspace", "meta(h).cas", "(meta(`h`).`cas`)"),
+		Entry("single quoted string", "type = 'airline'", "(`type` = \"airline\")"),
+		Entry("double equals", "type == \"airline\"", "(`type` = \"airline\")"),
+		Entry("not equals", "type <> 'x'", "(`type` != \"x\")"),
+		Entry("escaped string", "name = 'it''s'", "(`name` = \"it's\")"),
+		Entry("and chain", "type = 'hotel' AND country = 'France' AND free_parking = TRUE",
+			"((`type` = \"hotel\") and (`country` = \"France\") and (`free_parking` = true))"),
+		Entry("precedence", "a = 1 OR b = 2 AND c = 3", "((`a` = 1) or ((`b` = 2) and (`c` = 3)))"),
+		Entry("redundant parentheses", "((a = 1)) AND (((b)) = 2)", "((`a` = 1) and (`b` = 2))"),
+		Entry("not", "NOT (a = 1)", "(not (`a` = 1))"),
+		Entry("arithmetic", "price * 1.50 + tax", "((`price` * 1.5) + `tax`)"),
+		Entry("negative number", "a > -5", "(`a` > -5)"),
+		Entry("concat", "first || ' ' || last", "((`first` || \" \") || `last`)"),
+		Entry("is not missing", "email IS NOT MISSING", "(`email` is not missing)"),
+		Entry("is null", "email is null", "(`email` is null)"),
+		Entry("is valued", "email IS VALUED", "(`email` is valued)"),
+		Entry("in", "type IN ['airline', 'airport']", "(`type` in [\"airline\", \"airport\"])"),
+		Entry("not in", "type NOT IN ['hotel']", "(`type` not in [\"hotel\"])"),
+		Entry("like", "name LIKE 'A%'", "(`name` like \"A%\")"),
+		Entry("not like", "name NOT LIKE 'A%'", "(`name` not like \"A%\")"),
+		Entry("between", "age BETWEEN 18 AND 65 AND active", "((`age` between 18 and 65) and `active`)"),
+		Entry("not between", "age NOT BETWEEN 18 AND 65", "(`age` not between 18 and 65)"),
+		Entry("function", "LOWER(name)", "lower(`name`)"),
+		Entry("nested function", "UPPER(SUBSTR(name, 0, 1))", "upper(substr(`name`, 0, 1))"),
+		Entry("count star", "COUNT(*)", "count(*)"),
+		Entry("distinct array", "DISTINCT ARRAY s.flight FOR s IN schedule END",
+			"(distinct (array (`s`.`flight`) for `s` in `schedule` end))"),
+		Entry("all array with when", "ALL ARRAY v.name FOR v IN children WHEN v.age > 10 END",
+			"(all (array (`v`.`name`) for `v` in `children` when ((`v`.`age`) > 10) end))"),
+		Entry("array within", "ARRAY v FOR v WITHIN doc END", "(array `v` for `v` within `doc` end)"),
+		Entry("flatten keys", "DISTINCT ARRAY FLATTEN_KEYS(s.day DESC, s.flight) FOR s IN schedule END",
+			"(distinct (array flatten_keys((`s`.`day`) DESC, (`s`.`flight`)) for `s` in `schedule` end))"),
+		Entry("any satisfies", "ANY v IN schedule SATISFIES v.utc > '19:00' END",
+			"(any `v` in `schedule` satisfies ((`v`.`utc`) > \"19:00\") end)"),
+		Entry("some satisfies", "SOME v IN tags SATISFIES v = 'x' END", "(any `v` in `tags` satisfies (`v` = \"x\") end)"),
+		Entry("every satisfies", "EVERY v IN tags SATISFIES v = 'x' END", "(every `v` in `tags` satisfies (`v` = \"x\") end)"),
+		Entry("case", "CASE WHEN age < 18 THEN 'minor' ELSE 'adult' END",
+			"case when (`age` < 18) then \"minor\" else \"adult\" end"),
+		Entry("object literal", "{'a': 1, \"b\": [true, null]}", "{\"a\": 1, \"b\": [true, null]}"),
+		Entry("parameter", "type = $type", "(`type` = $type)"),
+	)
+
+	It("should treat equivalent expressions as equal", func() {
+		// Act
+
+		result := ExpressionsEqual("type='airline' and country=\"United States\"",
+			"((`type` = \"airline\") and (`country` = \"United States\"))")
+
+		// Assert
+
+		Expect(result).To(BeTrue())
+	})
+
+	It("should treat different expressions as not equal", func() {
+		// Act
+
+		result := ExpressionsEqual("type = 'airline'", "type = 'Airline'")
+
+		// Assert
+
+		Expect(result).To(BeFalse())
+	})
+
+	It("should keep identifiers case sensitive", func() {
+		// Act
+
+		result := ExpressionsEqual("Name", "name")
+
+		// Assert
+
+		Expect(result).To(BeFalse())
+	})
+
+	DescribeTable("should reject invalid expressions",
+		func(expression string) {
+			// Act
+
+			_, err := NormalizeExpression(expression)
+
+			// Assert
+
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("unterminated string", "type = 'airline"),
+		Entry("unbalanced parentheses", "(a = 1"),
+		Entry("trailing tokens", "a b"),
+		Entry("missing END", "ARRAY v FOR v IN items"),
+		Entry("subquery", "(SELECT 1)"),
+	)
+})
+
+var _ = Describe("NormalizeIndexKey", func() {
+
+	DescribeTable("should match system:indexes",
+		func(key string, expected string) {
+			// Act
+
+			result, err := NormalizeIndexKey(key)
+
+			// Assert
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(expected))
+		},
+		Entry("plain key", "name", "`name`"),
+		Entry("ascending key", "name ASC", "`name`"),
+		Entry("descending key", "name desc", "`name` DESC"),
+		Entry("include missing", "type INCLUDE MISSING", "`type` INCLUDE MISSING"),
+		Entry("include missing descending", "type include missing DESC", "`type` INCLUDE MISSING DESC"),
+		Entry("vector", "embedding VECTOR", "`embedding` VECTOR"),
+		Entry("expression", "LOWER(name) DESC", "lower(`name`) DESC"),
+	)
+
+	It("should treat equivalent keys as equal", func() {
+		// Act
+
+		result := IndexKeysEqual("DISTINCT ARRAY s.flight FOR s IN schedule END",
+			"(distinct (array (`s`.`flight`) for `s` in `schedule` end))")
+
+		// Assert
+
+		Expect(result).To(BeTrue())
+	})
+})